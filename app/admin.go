@@ -0,0 +1,154 @@
+package app
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"net/http"
+	"slices"
+	"strings"
+	"syscall"
+
+	"github.com/gorilla/mux"
+	"github.com/nmvalera/go-utils/config"
+)
+
+// redactedValue replaces the values of sensitive configuration fields served by the admin API
+const redactedValue = "[REDACTED]"
+
+// defaultRedactedKeys are the (case-insensitive) substrings of the configuration keys whose values are redacted
+var defaultRedactedKeys = []string{"password", "secret", "token", "credential", "apikey", "privatekey"}
+
+// Shutdown requests Run to gracefully stop the app, as on SIGTERM
+// It does not wait for the app to stop.
+func (app *App) Shutdown() {
+	select {
+	case app.done <- syscall.SIGTERM:
+	default:
+		// a stop has already been requested
+	}
+}
+
+// RedactedConfig returns the JSON configuration of the app (see config.Marshal)
+// with the values of sensitive fields (passwords, secrets, tokens...) redacted
+//
+// Once the config has been reloaded (see Reload), it returns the reloaded configuration
+func (app *App) RedactedConfig() ([]byte, error) {
+	cfg := app.cfg
+	if reloaded := app.reloadedCfg.Load(); reloaded != nil {
+		cfg = reloaded
+	}
+
+	b, err := config.Marshal(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	var m map[string]any
+	if err := json.Unmarshal(b, &m); err != nil {
+		return nil, err
+	}
+	RedactConfig(m, app.redactedKeys...)
+
+	return json.Marshal(m)
+}
+
+// RedactConfig replaces the values of the fields of a JSON configuration unmarshalled into m
+// whose key contains one of the default sensitive keys (password, secret, token...) or one of the given keys
+func RedactConfig(m map[string]any, keys ...string) {
+	redact(m, slices.Concat(defaultRedactedKeys, keys))
+}
+
+// RedactEnv replaces the values of the environment variables in env
+// whose name contains one of the default sensitive keys (password, secret, token...) or one of the given keys
+func RedactEnv(env map[string]string, keys ...string) {
+	keys = slices.Concat(defaultRedactedKeys, keys)
+	for k := range env {
+		if isRedacted(k, keys) {
+			env[k] = redactedValue
+		}
+	}
+}
+
+func redact(m map[string]any, keys []string) {
+	for k, v := range m {
+		if nested, ok := v.(map[string]any); ok {
+			redact(nested, keys)
+			continue
+		}
+		if isRedacted(k, keys) {
+			m[k] = redactedValue
+		}
+	}
+}
+
+// isRedacted returns true if key contains one of keys, ignoring case and separators
+// so keys match both configuration keys (e.g. adminToken) and environment variables (e.g. ADMIN_TOKEN)
+func isRedacted(key string, keys []string) bool {
+	key = normalizeKey(key)
+	for _, k := range keys {
+		if strings.Contains(key, normalizeKey(k)) {
+			return true
+		}
+	}
+	return false
+}
+
+func normalizeKey(key string) string {
+	return strings.ToLower(strings.NewReplacer("_", "", "-", "").Replace(key))
+}
+
+// setAdminHandlers serves the admin API under the admin path
+func (app *App) setAdminHandlers() {
+	router := app.healthzRouter.PathPrefix(*app.cfg.HealthzServer.AdminPath).Subrouter()
+	if app.cfg.HealthzServer.AdminToken != nil && *app.cfg.HealthzServer.AdminToken != "" {
+		router.Use(adminTokenMiddleware(*app.cfg.HealthzServer.AdminToken))
+	}
+
+	router.Path("/services").Methods(http.MethodGet).Handler(app.servicesHandler())
+	// zap.AtomicLevel serves GET and PUT requests with a {"level": "<level>"} JSON body
+	router.Path("/log/level").Methods(http.MethodGet, http.MethodPut).Handler(app.logLevel)
+	router.Path("/config").Methods(http.MethodGet).Handler(app.configHandler())
+	router.Path("/shutdown").Methods(http.MethodPost).Handler(app.shutdownHandler())
+}
+
+// adminTokenMiddleware rejects requests without an "Authorization: Bearer <token>" header matching token
+func adminTokenMiddleware(token string) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			auth := r.Header.Get("Authorization")
+			if !strings.HasPrefix(auth, "Bearer ") || subtle.ConstantTimeCompare([]byte(strings.TrimPrefix(auth, "Bearer ")), []byte(token)) != 1 {
+				w.Header().Set("WWW-Authenticate", "Bearer")
+				http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func (app *App) servicesHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(app.Graph().Services)
+	})
+}
+
+func (app *App) configHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		b, err := app.RedactedConfig()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(b)
+	})
+}
+
+func (app *App) shutdownHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		app.logger.Warn("Shutdown requested through the admin API")
+		app.Shutdown()
+		w.WriteHeader(http.StatusAccepted)
+	})
+}
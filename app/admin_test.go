@@ -0,0 +1,146 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"syscall"
+	"testing"
+
+	"github.com/nmvalera/go-utils/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+)
+
+func adminRequest(t *testing.T, method, url, token, body string) *http.Response {
+	req, err := http.NewRequest(method, url, strings.NewReader(body))
+	require.NoError(t, err)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = resp.Body.Close() })
+	return resp
+}
+
+func TestAdminAPI(t *testing.T) {
+	app := newTestApp(t)
+	app.cfg.HealthzServer.EnableAdmin = common.Ptr(true)
+	app.cfg.HealthzServer.AdminPath = common.Ptr("/admin")
+	app.cfg.HealthzServer.AdminToken = common.Ptr("s3cr3t")
+
+	app.Provide("test-service", func() (any, error) { return "value", nil })
+	app.EnableHealthzEntrypoint()
+	require.NoError(t, app.Start(context.Background()))
+	defer func() { require.NoError(t, app.Stop(context.Background())) }()
+
+	baseURL := "http://" + app.healthz.Addr() + "/admin"
+
+	// Authentication
+	resp := adminRequest(t, http.MethodGet, baseURL+"/services", "", "")
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	resp = adminRequest(t, http.MethodGet, baseURL+"/services", "invalid", "")
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+	// Services
+	resp = adminRequest(t, http.MethodGet, baseURL+"/services", "s3cr3t", "")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var services []map[string]any
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&services))
+	statuses := make(map[string]any)
+	for _, s := range services {
+		statuses[s["id"].(string)] = s["status"]
+	}
+	assert.Equal(t, "Constructed", statuses["test-service"])
+	assert.Equal(t, "Running", statuses["system.healthz.entrypoint"])
+
+	// Log level
+	resp = adminRequest(t, http.MethodGet, baseURL+"/log/level", "s3cr3t", "")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	resp = adminRequest(t, http.MethodPut, baseURL+"/log/level", "s3cr3t", `{"level":"debug"}`)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, zapcore.DebugLevel, app.logLevel.Level())
+
+	// Config
+	resp = adminRequest(t, http.MethodGet, baseURL+"/config", "s3cr3t", "")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var cfg map[string]any
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&cfg))
+	healthzAPI := cfg["healthzApi"].(map[string]any)
+	assert.Equal(t, redactedValue, healthzAPI["adminToken"])
+	assert.Equal(t, "/admin", healthzAPI["adminPath"])
+
+	// Shutdown
+	resp = adminRequest(t, http.MethodPost, baseURL+"/shutdown", "s3cr3t", "")
+	assert.Equal(t, http.StatusAccepted, resp.StatusCode)
+	select {
+	case sig := <-app.done:
+		assert.Equal(t, syscall.SIGTERM, sig)
+	default:
+		t.Fatal("shutdown not requested")
+	}
+}
+
+func TestAdminAPIDisabled(t *testing.T) {
+	app := newTestApp(t)
+
+	app.EnableHealthzEntrypoint()
+	require.NoError(t, app.Start(context.Background()))
+	defer func() { require.NoError(t, app.Stop(context.Background())) }()
+
+	resp := adminRequest(t, http.MethodGet, "http://"+app.healthz.Addr()+"/admin/services", "", "")
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
+
+func TestRedact(t *testing.T) {
+	m := map[string]any{
+		"name": "test",
+		"db": map[string]any{
+			"url":      "postgres://localhost",
+			"Password": "pass",
+		},
+		"aws": map[string]any{
+			"credentials": map[string]any{
+				"accessKey": "AKIA",
+				"secretKey": "secret",
+			},
+		},
+		"apiToken": "token",
+	}
+	redact(m, append(defaultRedactedKeys, "accessKey"))
+
+	assert.Equal(t, map[string]any{
+		"name": "test",
+		"db": map[string]any{
+			"url":      "postgres://localhost",
+			"Password": redactedValue,
+		},
+		"aws": map[string]any{
+			"credentials": map[string]any{
+				"accessKey": redactedValue,
+				"secretKey": redactedValue,
+			},
+		},
+		"apiToken": redactedValue,
+	}, m)
+}
+
+func TestRedactEnv(t *testing.T) {
+	env := map[string]string{
+		"NAME":                    "test",
+		"HEALTHZ_API_ADMIN_TOKEN": "token",
+		"AWS_API_KEY":             "key",
+		"AWS_ACCESS_KEY":          "AKIA",
+	}
+	RedactEnv(env, "accessKey")
+
+	assert.Equal(t, map[string]string{
+		"NAME":                    "test",
+		"HEALTHZ_API_ADMIN_TOKEN": redactedValue,
+		"AWS_API_KEY":             redactedValue,
+		"AWS_ACCESS_KEY":          redactedValue,
+	}, env)
+}
@@ -12,19 +12,26 @@ import (
 	"sync"
 	"sync/atomic"
 	"syscall"
+	"time"
 
 	"github.com/MadAppGang/httplog"
 	httplogzap "github.com/MadAppGang/httplog/zap"
+	"github.com/cenkalti/backoff/v4"
 	"github.com/gorilla/mux"
 	"github.com/hellofresh/health-go/v5"
 	"github.com/justinas/alice"
 	"github.com/nmvalera/go-utils/app/svc"
 	"github.com/nmvalera/go-utils/log"
+	kkrtgrpc "github.com/nmvalera/go-utils/net/grpc"
 	kkrthttp "github.com/nmvalera/go-utils/net/http"
 	"github.com/nmvalera/go-utils/tag"
+	"github.com/nmvalera/go-utils/tracing"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/collectors"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/spf13/viper"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/multierr"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 )
@@ -42,23 +49,71 @@ type App struct {
 
 	done chan os.Signal
 
+	fatal         chan error
+	supervisorMux sync.Mutex
+	stopping      bool
+	restarts      sync.WaitGroup
+	restartCtx    context.Context
+	restartCancel context.CancelFunc
+
+	draining atomic.Bool
+
 	logger               *zap.Logger
+	logLevel             zap.AtomicLevel
 	replaceGlobalLoggers bool
 	resetGlobalLoggers   func()
 
-	mainMiddleware alice.Chain
-	main           *kkrthttp.Entrypoint
-	mainRouter     *mux.Router
+	main *kkrthttp.Entrypoint
+
+	// httpEntrypoints are the named HTTP entrypoints (including main) with their router and middleware chain
+	httpEntrypoints map[string]*httpEntrypoint
 
 	healthz       *kkrthttp.Entrypoint
 	healthzRouter *mux.Router
 
-	liveHealth  *health.Health
-	readyHealth *health.Health
+	grpc         *kkrtgrpc.Entrypoint
+	grpcServices *kkrtgrpc.ServiceRegistry
+	grpcMetrics  *kkrtgrpc.ServerMetrics
+
+	liveHealth    *health.Health
+	readyHealth   *health.Health
+	startupHealth *health.Health
+
+	// readyChecks are the checks registered on readyHealth (see registerReadyCheck)
+	readyChecksMux sync.RWMutex
+	readyChecks    []*healthCheck
+
+	// readyEvents is notified when readiness may have changed (see runReadyHooks)
+	readyEvents chan struct{}
+
+	// started is set once all services have reached Running (see registerStartupCheck)
+	started atomic.Bool
+
+	serviceStartDuration *prometheus.GaugeVec
+	serviceStatus        *prometheus.GaugeVec
+
+	onStart    []Hook
+	onReady    []Hook
+	onStop     []Hook
+	statusSubs statusSubscriptions
 
 	prometheus *prometheus.Registry
 
-	tags tag.Set
+	tagsMux sync.RWMutex
+	tags    tag.Set
+
+	tracerProvider         trace.TracerProvider
+	shutdownTracerProvider func(context.Context) error
+
+	// redactedKeys are additional configuration keys redacted by the admin API (see WithRedactedConfigKeys)
+	redactedKeys []string
+
+	viper     *viper.Viper
+	reloadMux sync.Mutex
+	reloads   chan struct{}
+
+	// reloadedCfg is the configuration loaded by the last successful Reload (served by the admin API)
+	reloadedCfg atomic.Pointer[Config]
 
 	runCtx    context.Context
 	runCancel context.CancelFunc
@@ -66,14 +121,16 @@ type App struct {
 
 func NewApp(cfg *Config, opts ...Option) (*App, error) {
 	app := &App{
-		cfg:            cfg,
-		services:       make(map[string]*service),
-		done:           make(chan os.Signal),
-		logger:         zap.NewNop(),
-		mainMiddleware: alice.New(),
-		mainRouter:     mux.NewRouter(),
-		healthzRouter:  mux.NewRouter(),
-		prometheus:     prometheus.NewRegistry(),
+		cfg:             cfg,
+		services:        make(map[string]*service),
+		done:            make(chan os.Signal, 1),
+		reloads:         make(chan struct{}, 1),
+		fatal:           make(chan error, 1),
+		logger:          zap.NewNop(),
+		httpEntrypoints: make(map[string]*httpEntrypoint),
+		healthzRouter:   mux.NewRouter(),
+		grpcServices:    kkrtgrpc.NewServiceRegistry(),
+		prometheus:      prometheus.NewRegistry(),
 	}
 
 	// Set name and version from config
@@ -84,11 +141,13 @@ func NewApp(cfg *Config, opts ...Option) (*App, error) {
 		app.version = *cfg.Version
 	}
 
-	logger, err := cfg.Log.ZapConfig().Build()
+	zapCfg := cfg.Log.ZapConfig()
+	logger, err := zapCfg.Build()
 	if err != nil {
 		return nil, err
 	}
 	app.logger = logger
+	app.logLevel = zapCfg.Level
 
 	// Options can override name/version from config
 	for _, opt := range opts {
@@ -97,27 +156,54 @@ func NewApp(cfg *Config, opts ...Option) (*App, error) {
 		}
 	}
 
-	// Build app-level tags: name, version, and user-configured tags
-	// User configured tags override name/version tags
-	if app.name != "" {
-		app.tags = app.tags.WithTags(tag.Key("app").String(app.name))
-	}
-	if app.version != "" {
-		app.tags = app.tags.WithTags(tag.Key("version").String(app.version))
-	}
+	app.setTags(cfg.Tags)
 
-	if len(cfg.Tags) > 0 {
-		app.tags = app.tags.WithTags(tag.MapTags(cfg.Tags)...)
+	if err := app.setTracerProvider(); err != nil {
+		return nil, err
 	}
 
 	app.liveHealth = newHealth(app)
 	app.readyHealth = newHealth(app)
+	app.readyEvents = make(chan struct{}, 1)
+	app.startupHealth = newHealth(app)
+	if err := app.registerStartupCheck(); err != nil {
+		return nil, err
+	}
+	if err := app.registerShutdownCheck(); err != nil {
+		return nil, err
+	}
 
 	app.registerBaseMetrics()
 
 	return app, nil
 }
 
+// setTags builds app-level tags: name, version, and user-configured tags
+// User configured tags override name/version tags
+func (app *App) setTags(cfgTags map[string]any) {
+	var tags tag.Set
+	if app.name != "" {
+		tags = tags.WithTags(tag.Key("app").String(app.name))
+	}
+	if app.version != "" {
+		tags = tags.WithTags(tag.Key("version").String(app.version))
+	}
+
+	if len(cfgTags) > 0 {
+		tags = tags.WithTags(tag.MapTags(cfgTags)...)
+	}
+
+	app.tagsMux.Lock()
+	app.tags = tags
+	app.tagsMux.Unlock()
+}
+
+func (app *App) getTags() tag.Set {
+	app.tagsMux.RLock()
+	defer app.tagsMux.RUnlock()
+	return app.tags
+}
+
 func (app *App) replaceLoggers() {
 	if app.replaceGlobalLoggers {
 		app.resetGlobalLoggers = zap.ReplaceGlobals(app.logger)
@@ -135,14 +221,15 @@ func (app *App) Provide(id string, constructor func() (any, error), opts ...Serv
 		panic(fmt.Sprintf("invalid service id: %q (system.* is reserved for internal use)", id))
 	}
 
+	if id == "" {
+		// the constructor returns any which does not identify the service, so this panics
+		id = typeID[any]()
+	}
+
 	return app.provide(id, constructor, opts...)
 }
 
 func (app *App) provide(id string, constructor func() (any, error), opts ...ServiceOption) any {
-	if id == "" {
-		id = reflect.TypeOf(constructor).Out(0).String()
-	}
-
 	if srvc, ok := app.services[id]; ok {
 		if app.current != nil {
 			// we are under construction so we add a dependency
@@ -162,9 +249,13 @@ func (app *App) createService(id string, constructor func() (any, error), opts .
 	previous := app.current
 	srvc := newService(id, constructor, opts...)
 	srvc.app = app
+	// initialize the status metric of the service, it is then updated on every status change
+	app.setServiceStatusMetric(srvc.id, srvc.Status(), srvc.Status())
 
 	app.current = srvc // set the current service pointer
-	srvc.construct()   // construct can perform calls to Provide moving the current service pointer
+	if srvc.err == nil {
+		srvc.construct() // construct can perform calls to Provide moving the current service pointer
+	}
 	if previous != nil {
 		previous.addDep(srvc)
 	} else {
@@ -185,6 +276,11 @@ func Provide[T any](app *App, id string, constructor func() (T, error), opts ...
 }
 
 func provide[T any](app *App, id string, constructor func() (T, error), opts ...ServiceOption) T {
+	if id == "" {
+		// the wrapped constructor returns any, so we identify the service by T
+		id = typeID[T]()
+	}
+
 	val := app.provide(id, func() (any, error) {
 		return constructor()
 	}, opts...)
@@ -212,30 +308,52 @@ func (app *App) Context(ctx context.Context) context.Context {
 
 func (app *App) context(ctx context.Context) context.Context {
 	ctx = log.WithLogger(ctx, app.logger)
-	if len(app.tags) > 0 {
-		ctx = tag.WithTags(ctx, app.tags...)
+	if tags := app.getTags(); len(tags) > 0 {
+		ctx = tag.WithTags(ctx, tags...)
 	}
 	return ctx
 }
 
-func (app *App) Start(ctx context.Context) error {
-	startCtx, startCancel := context.WithTimeout(ctx, *app.cfg.StartTimeout)
+// Start starts all services in dependency order
+//
+// The Start of each service is bounded by its start timeout (see WithStartTimeout) or, by default, by StartTimeout in Config
+func (app *App) Start(ctx context.Context) (err error) {
+	startCtx, startCancel := context.WithTimeout(ctx, app.startTimeout())
 	defer startCancel()
 
-	startCtx = app.context(startCtx)
+	app.replaceTracerProvider()
+
+	startCtx, span := tracing.StartSpan(app.context(startCtx), "app.start")
+	defer func() { tracing.End(span, err) }()
 
 	logger := log.LoggerFromContext(startCtx)
 	logger.Info("System starting...")
 
 	app.runCtx, app.runCancel = context.WithCancel(context.Background())
 	app.runCtx = app.context(app.runCtx)
+	app.restartCtx, app.restartCancel = context.WithCancel(app.runCtx)
+	app.runReadyChecks(app.runCtx)
 
-	err := app.start(startCtx)
+	err = app.start(startCtx)
 	if err != nil {
 		app.runCancel()
 		logger.Error("System failed to start", zap.Error(err))
 		return err
 	}
+
+	if err = app.runStartHooks(startCtx); err != nil {
+		logger.Error("Start hook failed, stopping services...", zap.Error(err))
+		// Services have started, so we stop them before giving up
+		stopCtx, stopCancel := context.WithTimeout(app.context(context.WithoutCancel(ctx)), app.stopTimeout())
+		defer stopCancel()
+		if stopErr := app.stop(stopCtx); stopErr != nil {
+			logger.Error("System failed to stop", zap.Error(stopErr))
+		}
+		app.runCancel()
+		return err
+	}
+	go app.runReadyHooks(app.runCtx)
+
 	logger.Info("System successfully started")
 	return nil
 }
@@ -249,8 +367,11 @@ func (app *App) start(ctx context.Context) error {
 		return app.top.err
 	}
 
+	if err := app.setHandlers(); err != nil {
+		return err
+	}
+
 	app.replaceLoggers()
-	app.setHandlers()
 	if err := app.top.start(ctx); err != nil {
 		app.resetLoggers()
 		return err
@@ -259,25 +380,40 @@ func (app *App) start(ctx context.Context) error {
 	return nil
 }
 
-func (app *App) Stop(ctx context.Context) error {
-	stopCtx, stopCancel := context.WithTimeout(ctx, *app.cfg.StopTimeout)
+// Stop gracefully stops the app
+// It first drains the app (see DrainDelay and DrainTimeout in Config), then stops all services in reverse dependency order
+//
+// The Stop of each service is bounded by its stop timeout (see WithStopTimeout) or, by default, by StopTimeout in Config
+func (app *App) Stop(ctx context.Context) (err error) {
+	defer app.shutdownTracing(ctx)
+
+	ctx, span := tracing.StartSpan(app.context(ctx), "app.stop")
+	defer func() { tracing.End(span, err) }()
+
+	app.drain(ctx)
+
+	stopCtx, stopCancel := context.WithTimeout(ctx, app.stopTimeout())
 	defer stopCancel()
 
-	stopCtx = app.context(stopCtx)
 	logger := log.LoggerFromContext(stopCtx)
 	logger.Info("System stopping...")
 
+	hooksErr := app.runStopHooks(stopCtx)
+	if hooksErr != nil {
+		logger.Error("Stop hook failed", zap.Error(hooksErr))
+	}
+
 	done := make(chan error, 1)
 	go func() {
 		done <- app.stop(stopCtx)
 	}()
 
-	var err error
 	select {
 	case err = <-done:
 	case <-stopCtx.Done():
 		err = stopCtx.Err()
 	}
+	err = multierr.Combine(hooksErr, err)
 
 	// Cancel the run context
 	app.runCancel()
@@ -293,6 +429,9 @@ func (app *App) Stop(ctx context.Context) error {
 func (app *App) stop(ctx context.Context) error {
 	defer app.resetLoggers()
 
+	// Prevent any further restart of failed services
+	app.stopRestarts()
+
 	if app.top == nil {
 		return fmt.Errorf("no service constructed yet")
 	}
@@ -311,17 +450,45 @@ func (app *App) Run(ctx context.Context) error {
 	}
 
 	app.listenSignals()
+	stopWatchingConfig := app.watchConfig()
 
-	sig := <-app.done
-	log.LoggerFromContext(app.runCtx).Warn("Received signal", zap.String("signal", sig.String()))
+	runErr := app.wait(ctx)
 
+	stopWatchingConfig()
 	app.stopListeningSignals()
 
-	return app.Stop(ctx)
+	return multierr.Combine(runErr, app.Stop(ctx))
+}
+
+// wait waits until the app is requested to stop, reloading the config when requested
+// It returns the fatal error of a service if the stop has been caused by a service failure
+func (app *App) wait(ctx context.Context) error {
+	logger := log.LoggerFromContext(app.runCtx)
+	for {
+		select {
+		case sig := <-app.done:
+			if sig == syscall.SIGHUP {
+				logger.Info("Received signal", zap.String("signal", sig.String()))
+				app.reload(ctx)
+				continue
+			}
+			logger.Warn("Received signal", zap.String("signal", sig.String()))
+			return nil
+		case <-app.reloads:
+			app.reload(ctx)
+		case err := <-app.fatal:
+			logger.Error("Service failure", zap.Error(err))
+			return err
+		}
+	}
 }
 
 func (app *App) listenSignals() {
-	signal.Notify(app.done, os.Interrupt, syscall.SIGINT, syscall.SIGTERM)
+	signals := []os.Signal{os.Interrupt, syscall.SIGINT, syscall.SIGTERM}
+	if app.viper != nil {
+		signals = append(signals, syscall.SIGHUP)
+	}
+	signal.Notify(app.done, signals...)
 }
 
 func (app *App) stopListeningSignals() {
@@ -342,7 +509,7 @@ func (app *App) HealthzEntrypoint() *kkrthttp.Entrypoint {
 }
 
 func (app *App) EnableMainEntrypoint() {
-	app.main = app.entrypoint("main", app.cfg.MainEntrypoint)
+	app.main = app.EnableEntrypoint(MainEntrypoint, app.cfg.MainEntrypoint)
 }
 
 func (app *App) EnableHealthzEntrypoint() {
@@ -355,16 +522,13 @@ func (app *App) entrypoint(name string, cfg *kkrthttp.EntrypointConfig) *kkrthtt
 	})
 }
 
-func (app *App) setHandlers() {
-	app.setMainHandler()
-	app.setHealthzHandler()
-}
-
-func (app *App) setMainHandler() {
-	if app.main != nil {
-		h := app.instrumentMiddleware().Extend(app.mainMiddleware).Then(app.mainRouter)
-		app.main.SetHandler(h)
+func (app *App) setHandlers() error {
+	if err := app.setEntrypointHandlers(); err != nil {
+		return err
 	}
+	app.setHealthzHandler()
+	app.setGRPCHandler()
+	return nil
 }
 
 func (app *App) instrumentMiddleware() alice.Chain {
@@ -373,6 +537,8 @@ func (app *App) instrumentMiddleware() alice.Chain {
 		httplog.LoggerWithConfig(httplog.LoggerConfig{
 			Formatter: httplogzap.ZapLogger(app.logger, zapcore.InfoLevel, ""),
 		}),
+		// Create a server span for every request on main router
+		tracing.Middleware,
 		// Instrument main router with prometheus metrics
 		func(next http.Handler) http.Handler {
 			return promhttp.InstrumentMetricHandler(app.prometheus, next)
@@ -382,8 +548,23 @@ func (app *App) instrumentMiddleware() alice.Chain {
 
 func (app *App) setHealthzHandler() {
 	app.healthzRouter.Path(*app.cfg.HealthzServer.LivenessPath).Methods(http.MethodGet).Handler(app.liveHealth.Handler())
-	app.healthzRouter.Path(*app.cfg.HealthzServer.ReadinessPath).Methods(http.MethodGet).Handler(app.readyHealth.Handler())
+	app.healthzRouter.Path(*app.cfg.HealthzServer.ReadinessPath).Methods(http.MethodGet).Handler(app.readinessHandler())
+	if app.cfg.HealthzServer.StartupPath != nil {
+		app.healthzRouter.Path(*app.cfg.HealthzServer.StartupPath).Methods(http.MethodGet).Handler(app.startupHealth.Handler())
+	}
 	app.healthzRouter.Path(*app.cfg.HealthzServer.MetricsPath).Methods(http.MethodGet).Handler(promhttp.HandlerFor(app.prometheus, promhttp.HandlerOpts{}))
+	if app.cfg.HealthzServer.GraphPath != nil && *app.cfg.HealthzServer.GraphPath != "" {
+		app.healthzRouter.Path(*app.cfg.HealthzServer.GraphPath).Methods(http.MethodGet).Handler(app.graphHandler())
+	}
+	if app.cfg.HealthzServer.BuildInfoPath != nil && *app.cfg.HealthzServer.BuildInfoPath != "" {
+		app.healthzRouter.Path(*app.cfg.HealthzServer.BuildInfoPath).Methods(http.MethodGet).Handler(app.buildInfoHandler())
+	}
+	if app.cfg.HealthzServer.EnablePprof != nil && *app.cfg.HealthzServer.EnablePprof {
+		app.setPprofHandlers()
+	}
+	if app.cfg.HealthzServer.EnableAdmin != nil && *app.cfg.HealthzServer.EnableAdmin {
+		app.setAdminHandlers()
+	}
 
 	if app.healthz != nil {
 		app.healthz.SetHandler(app.healthzRouter)
@@ -393,6 +574,10 @@ func (app *App) setHealthzHandler() {
 func (app *App) registerBaseMetrics() {
 	app.prometheus.MustRegister(collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}))
 	app.prometheus.MustRegister(collectors.NewGoCollector())
+	app.registerServiceMetrics()
+	if app.cfg.HealthzServer != nil && app.cfg.HealthzServer.EnableBuildInfoMetric != nil && *app.cfg.HealthzServer.EnableBuildInfoMetric {
+		app.registerBuildInfoMetric()
+	}
 }
 
 type ServiceStatus uint32
@@ -407,6 +592,27 @@ const (
 	Error
 )
 
+var serviceStatusNames = [...]string{
+	Constructing: "Constructing",
+	Constructed:  "Constructed",
+	Starting:     "Starting",
+	Running:      "Running",
+	Stopping:     "Stopping",
+	Stopped:      "Stopped",
+	Error:        "Error",
+}
+
+func (s ServiceStatus) String() string {
+	if int(s) < len(serviceStatusNames) {
+		return serviceStatusNames[s]
+	}
+	return fmt.Sprintf("ServiceStatus(%d)", uint32(s))
+}
+
+func (s ServiceStatus) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
 type service struct {
 	id string
 
@@ -422,15 +628,34 @@ type service struct {
 	status atomic.Uint32
 	err    *ServiceError
 
+	// runErr is the fatal error reported by the service while running (see svc.Fallible)
+	runErr         error
+	runDone        chan struct{}
+	runSince       time.Time
+	restartPolicy  *RestartPolicy
+	restartBackOff backoff.BackOff
+
 	startOnce sync.Once
 
 	stopOnce sync.Once
 	stopChan chan struct{}
 
+	metricsOnce sync.Once
+
+	startDuration time.Duration
+	stopDuration  time.Duration
+
+	// startTimeout and stopTimeout override StartTimeout and StopTimeout in Config for the service
+	startTimeout *time.Duration
+	stopTimeout  *time.Duration
+
 	name            string
+	groups          []string
+	entrypoint      string
 	chainedName     bool
 	tags            tag.Set
 	healthConfig    *health.Config
+	checkOptions    checkOptions
 	metricsPrefix   string
 	runContextAware svc.RunContextAware
 }
@@ -445,12 +670,13 @@ func newService(id string, constructor func() (any, error), opts ...ServiceOptio
 		stopChan:     make(chan struct{}),
 		tags:         tag.EmptySet,
 		healthConfig: new(health.Config),
+		entrypoint:   MainEntrypoint,
 	}
 
 	for _, opt := range opts {
 		if err := opt(s); err != nil {
 			_ = s.fail(err)
-			return nil
+			return s
 		}
 	}
 
@@ -478,7 +704,10 @@ func (s *service) Status() ServiceStatus {
 }
 
 func (s *service) setStatus(status ServiceStatus) {
-	s.status.Store(uint32(status))
+	previous := ServiceStatus(s.status.Swap(uint32(status)))
+	if previous != status && s.app != nil {
+		s.app.publishServiceStatus(s, previous, status)
+	}
 }
 
 func (s *service) setStatusWithLock(status ServiceStatus) {
@@ -531,11 +760,16 @@ func (s *service) construct() {
 	}
 
 	if t, ok := val.(svc.API); ok {
-		t.RegisterHandler(s.app.mainRouter)
+		t.RegisterHandler(s.app.httpEntrypoint(s.entrypoint).router)
 	}
 
 	if t, ok := val.(svc.Middleware); ok {
-		s.app.mainMiddleware = t.RegisterMiddleware(s.app.mainMiddleware)
+		ep := s.app.httpEntrypoint(s.entrypoint)
+		ep.middleware = t.RegisterMiddleware(ep.middleware)
+	}
+
+	if t, ok := val.(svc.GRPCAPI); ok {
+		t.RegisterGRPCService(s.app.grpcServices)
 	}
 
 	if t, ok := val.(svc.Healthz); ok {
@@ -632,7 +866,13 @@ func (s *service) start(startCtx context.Context) *ServiceError {
 			if start, ok := s.value.(svc.Runnable); ok {
 				logger := log.LoggerFromContext(s.context(startCtx))
 				logger.Info("Service starting...")
-				err := start.Start(s.context(startCtx))
+				ctx, cancel := s.withStartTimeout(startCtx)
+				ctx, span := tracing.StartSpan(s.context(ctx), "service.start")
+				begin := time.Now()
+				err := start.Start(ctx)
+				s.setStartDuration(time.Since(begin))
+				tracing.End(span, err)
+				cancel()
 				if err != nil {
 					s.failWithLock(err)
 					logger.Error("Service failed to start", zap.Error(err))
@@ -644,6 +884,7 @@ func (s *service) start(startCtx context.Context) *ServiceError {
 
 		s.registerMetric()
 		s.setStatusWithLock(Running)
+		s.supervise()
 	})
 
 	return s.err
@@ -654,9 +895,9 @@ func (s *service) stop(stopCtx context.Context) *ServiceError {
 		return s.err
 	}
 
-	// if one of the dependencies is not running then don't stop
+	// if one of the dependents has not stopped yet then wait for it to stop us
 	for _, dep := range s.depsOf {
-		if dep.Status() <= Stopping {
+		if dep.stopPending() {
 			<-s.stopChan
 			return s.err
 		}
@@ -667,21 +908,13 @@ func (s *service) stop(stopCtx context.Context) *ServiceError {
 			return
 		}
 
-		s.setStatusWithLock(Stopping)
 		defer func() {
 			close(s.stopChan)
 		}()
 
-		if stop, ok := s.value.(svc.Runnable); ok {
-			logger := log.LoggerFromContext(s.context(stopCtx))
-			logger.Info("Service stopping...")
-			err := stop.Stop(s.context(stopCtx))
-			if err != nil {
-				s.failWithLock(err)
-				logger.Error("Service failed to stop", zap.Error(err))
-				return
-			}
-			logger.Info("Service successfully stopped")
+		if err := s.stopSelf(stopCtx); err != nil {
+			s.failWithLock(err)
+			return
 		}
 		if s.err == nil {
 			s.setStatusWithLock(Stopped)
@@ -710,6 +943,55 @@ func (s *service) stop(stopCtx context.Context) *ServiceError {
 	return s.err
 }
 
+// stopPending returns true if the service has not been stopped yet
+//
+// A service that failed at runtime (see handleFailure) is still pending, as it may be restarted
+// and it stops its dependencies once stopped.
+func (s *service) stopPending() bool {
+	status := s.Status()
+	return status <= Stopping || (status == Error && s.err == nil)
+}
+
+// stopSelf stops the service without stopping its dependencies
+func (s *service) stopSelf(stopCtx context.Context) error {
+	s.endSupervision()
+	s.setStatusWithLock(Stopping)
+
+	if stop, ok := s.value.(svc.Runnable); ok {
+		logger := log.LoggerFromContext(s.context(stopCtx))
+		logger.Info("Service stopping...")
+		ctx, cancel := s.withStopTimeout(stopCtx)
+		ctx, span := tracing.StartSpan(s.context(ctx), "service.stop")
+		begin := time.Now()
+		err := stop.Stop(ctx)
+		s.setStopDuration(time.Since(begin))
+		tracing.End(span, err)
+		cancel()
+		if err != nil {
+			logger.Error("Service failed to stop", zap.Error(err))
+			return err
+		}
+		logger.Info("Service successfully stopped")
+	}
+
+	return nil
+}
+
+func (s *service) setStartDuration(d time.Duration) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	s.startDuration = d
+	if s.app != nil && s.app.serviceStartDuration != nil {
+		s.app.serviceStartDuration.WithLabelValues(s.id).Set(d.Seconds())
+	}
+}
+
+func (s *service) setStopDuration(d time.Duration) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	s.stopDuration = d
+}
+
 func (s *service) registerReadyCheck() error {
 	if s.healthConfig.Check == nil {
 		return nil
@@ -723,7 +1005,7 @@ func (s *service) registerReadyCheck() error {
 		}
 	}
 
-	return s.app.readyHealth.Register(*s.healthConfig)
+	return s.app.registerReadyCheck(*s.healthConfig, &s.checkOptions, s.checkStatus)
 }
 
 func (s *service) wrapCheck(check health.CheckFunc) health.CheckFunc {
@@ -733,24 +1015,30 @@ func (s *service) wrapCheck(check health.CheckFunc) health.CheckFunc {
 		s.mux.RLock()
 		defer s.mux.RUnlock()
 
-		switch s.Status() {
-		case Constructing, Constructed:
-			return fmt.Errorf("service not started")
-		case Starting:
-			return fmt.Errorf("service starting")
-		case Running:
-			return check(ctx)
-		case Stopping:
-			return fmt.Errorf("service stopping")
-		case Stopped:
-			return fmt.Errorf("service stopped")
-		case Error:
-			return fmt.Errorf("service in error state: %v", s.err)
+		if err := s.checkStatus(); err != nil {
+			return err
 		}
-		return nil
+		return check(ctx)
 	}
 }
 
+// checkStatus returns an error if the service is not Running
+func (s *service) checkStatus() error {
+	switch s.Status() {
+	case Constructing, Constructed:
+		return fmt.Errorf("service not started")
+	case Starting:
+		return fmt.Errorf("service starting")
+	case Stopping:
+		return fmt.Errorf("service stopping")
+	case Stopped:
+		return fmt.Errorf("service stopped")
+	case Error:
+		return fmt.Errorf("service in error state: %v", s.runError())
+	}
+	return nil
+}
+
 func (s *service) setMetrics() {
 	if m, ok := s.value.(svc.Metricable); ok {
 		subsystem := s.name
@@ -762,13 +1050,16 @@ func (s *service) setMetrics() {
 }
 
 func (s *service) registerMetric() {
-	if collector, ok := s.value.(prometheus.Collector); ok {
-		if s.metricsPrefix != "" {
-			prometheus.WrapRegistererWithPrefix(s.metricsPrefix, s.app.prometheus).MustRegister(collector)
-		} else {
-			s.app.prometheus.MustRegister(collector)
+	// Metrics are registered only once, even if the service is restarted
+	s.metricsOnce.Do(func() {
+		if collector, ok := s.value.(prometheus.Collector); ok {
+			if s.metricsPrefix != "" {
+				prometheus.WrapRegistererWithPrefix(s.metricsPrefix, s.app.prometheus).MustRegister(collector)
+			} else {
+				s.app.prometheus.MustRegister(collector)
+			}
 		}
-	}
+	})
 }
 
 type ServiceError struct {
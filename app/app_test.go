@@ -19,6 +19,7 @@ import (
 	kkrthttp "github.com/nmvalera/go-utils/net/http"
 	"github.com/nmvalera/go-utils/tag"
 	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
@@ -503,27 +504,35 @@ func TestMetrics(t *testing.T) {
 	assert.Equal(t, resp.StatusCode, http.StatusOK)
 
 	// Test collectors are registered with correct labels
-	families, err := app.prometheus.Gather()
-	require.NoError(t, err)
-	familyCount := len(families)
-	assert.GreaterOrEqual(t, familyCount, 2)
-	assert.Equal(t, "testApp_subsystem_B_count", families[familyCount-2].GetName())
-	assert.Equal(t, "testApp_test_wo_cfg_A_count", families[familyCount-1].GetName())
+	families := gatherMetrics(t, app)
+	require.Contains(t, families, "testApp_subsystem_B_count")
+	require.Contains(t, families, "testApp_test_wo_cfg_A_count")
 
 	// Test metrics are updated
-	assert.Equal(t, float64(0), families[familyCount-1].GetMetric()[0].GetCounter().GetValue())
+	assert.Equal(t, float64(0), families["testApp_test_wo_cfg_A_count"].GetMetric()[0].GetCounter().GetValue())
 	metrics.incr()
 	metrics.incr()
 	metrics.incr()
 
-	families, err = app.prometheus.Gather()
-	require.NoError(t, err)
-	assert.Equal(t, float64(3), families[familyCount-1].GetMetric()[0].GetCounter().GetValue())
+	families = gatherMetrics(t, app)
+	assert.Equal(t, float64(3), families["testApp_test_wo_cfg_A_count"].GetMetric()[0].GetCounter().GetValue())
 
 	err = app.Stop(context.Background())
 	require.NoError(t, err)
 }
 
+// gatherMetrics returns the metric families of the app indexed by name
+func gatherMetrics(t *testing.T, app *App) map[string]*dto.MetricFamily {
+	families, err := app.prometheus.Gather()
+	require.NoError(t, err)
+
+	byName := make(map[string]*dto.MetricFamily, len(families))
+	for _, family := range families {
+		byName[family.GetName()] = family
+	}
+	return byName
+}
+
 type healthzAPIService struct{}
 
 func (s *healthzAPIService) RegisterHealthzHandler(router *mux.Router) {
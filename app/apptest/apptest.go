@@ -0,0 +1,219 @@
+// Package apptest provides utilities to test an app.App end-to-end in-process.
+package apptest
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/nmvalera/go-utils/app"
+	"github.com/nmvalera/go-utils/common"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// App is an app.App built for tests
+//
+// Entrypoints listen on ephemeral local ports, logs are captured and the app is stopped on test cleanup.
+type App struct {
+	*app.App
+
+	// Logs holds the logs emitted by the app
+	Logs *observer.ObservedLogs
+
+	t            testing.TB
+	readyPath    string
+	readyTimeout time.Duration
+	stopTimeout  time.Duration
+}
+
+type options struct {
+	cfgFuncs     []func(*app.Config)
+	appOpts      []app.Option
+	logLevel     zapcore.Level
+	readyTimeout time.Duration
+}
+
+type Option func(*options)
+
+// WithConfig modifies the app configuration before the app is created
+// Entrypoint addresses are set to ephemeral local ports before the function is called
+func WithConfig(f func(cfg *app.Config)) Option {
+	return func(o *options) {
+		o.cfgFuncs = append(o.cfgFuncs, f)
+	}
+}
+
+// WithAppOptions sets options passed to app.NewApp
+func WithAppOptions(opts ...app.Option) Option {
+	return func(o *options) {
+		o.appOpts = append(o.appOpts, opts...)
+	}
+}
+
+// WithLogLevel sets the minimum level of the captured logs (default debug)
+func WithLogLevel(level zapcore.Level) Option {
+	return func(o *options) {
+		o.logLevel = level
+	}
+}
+
+// WithReadyTimeout sets the maximum duration to wait for the app to be ready after Start (default 5s)
+func WithReadyTimeout(timeout time.Duration) Option {
+	return func(o *options) {
+		o.readyTimeout = timeout
+	}
+}
+
+// New creates a new App for tests
+//
+// Services are provided as on any app.App (e.g. app.Provide(a.App, ...)) before calling Start
+func New(t testing.TB, opts ...Option) *App {
+	t.Helper()
+
+	o := &options{
+		logLevel:     zapcore.DebugLevel,
+		readyTimeout: 5 * time.Second,
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	cfg := app.DefaultConfig()
+	cfg.Name = common.Ptr("apptest")
+	cfg.MainEntrypoint.Addr = common.Ptr("127.0.0.1:0")
+	cfg.HealthzEntrypoint.Addr = common.Ptr("127.0.0.1:0")
+	for _, f := range o.cfgFuncs {
+		f(cfg)
+	}
+
+	// Logs are both captured and written to the test output
+	observerCore, logs := observer.New(o.logLevel)
+	testCore := zaptest.NewLogger(t, zaptest.Level(o.logLevel)).Core()
+	logger := zap.New(zapcore.NewTee(observerCore, testCore))
+
+	a, err := app.NewApp(cfg, append([]app.Option{app.WithLogger(logger)}, o.appOpts...)...)
+	require.NoError(t, err)
+
+	return &App{
+		App:          a,
+		Logs:         logs,
+		t:            t,
+		readyPath:    *cfg.HealthzServer.ReadinessPath,
+		readyTimeout: o.readyTimeout,
+		stopTimeout:  *cfg.StopTimeout,
+	}
+}
+
+// Start starts the app, waits for it to be ready and registers its stop on test cleanup
+func (a *App) Start() {
+	a.t.Helper()
+
+	require.NoError(a.t, a.App.Start(context.Background()))
+	a.t.Cleanup(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), a.stopTimeout)
+		defer cancel()
+		if err := a.App.Stop(ctx); err != nil {
+			a.t.Errorf("failed to stop app: %v", err)
+		}
+	})
+
+	a.WaitReady()
+}
+
+// WaitReady waits until the readiness probe of the app succeeds
+// It does nothing if the healthz entrypoint is not enabled
+func (a *App) WaitReady() {
+	a.t.Helper()
+
+	if a.HealthzEntrypoint() == nil {
+		return
+	}
+
+	client := a.HealthzClient()
+	deadline := time.Now().Add(a.readyTimeout)
+	for {
+		err := probe(client, a.readyPath)
+		if err == nil {
+			return
+		}
+		if time.Now().After(deadline) {
+			require.FailNow(a.t, "app not ready", err.Error())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func probe(client *http.Client, path string) error {
+	resp, err := client.Get(path)
+	if err != nil {
+		return err
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("probe %s returned status %d", path, resp.StatusCode)
+	}
+	return nil
+}
+
+// MainAddr returns the address the main entrypoint listens on
+// It fails the test if the main entrypoint is not enabled or not started
+func (a *App) MainAddr() string {
+	a.t.Helper()
+	require.NotNil(a.t, a.MainEntrypoint(), "main entrypoint not enabled")
+	addr := a.MainEntrypoint().Addr()
+	require.NotEmpty(a.t, addr, "main entrypoint not started")
+	return addr
+}
+
+// HealthzAddr returns the address the healthz entrypoint listens on
+// It fails the test if the healthz entrypoint is not enabled or not started
+func (a *App) HealthzAddr() string {
+	a.t.Helper()
+	require.NotNil(a.t, a.HealthzEntrypoint(), "healthz entrypoint not enabled")
+	addr := a.HealthzEntrypoint().Addr()
+	require.NotEmpty(a.t, addr, "healthz entrypoint not started")
+	return addr
+}
+
+// MainClient returns an HTTP client sending requests with a relative URL (e.g. "/users") to the main entrypoint
+func (a *App) MainClient() *http.Client {
+	a.t.Helper()
+	return newClient(a.MainAddr())
+}
+
+// HealthzClient returns an HTTP client sending requests with a relative URL (e.g. "/ready") to the healthz entrypoint
+func (a *App) HealthzClient() *http.Client {
+	a.t.Helper()
+	return newClient(a.HealthzAddr())
+}
+
+func newClient(addr string) *http.Client {
+	return &http.Client{
+		Transport: &baseURLTransport{
+			host: addr,
+			next: http.DefaultTransport,
+		},
+	}
+}
+
+// baseURLTransport completes relative request URLs with the address of an entrypoint
+type baseURLTransport struct {
+	host string
+	next http.RoundTripper
+}
+
+func (t *baseURLTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.URL.Host == "" {
+		req = req.Clone(req.Context())
+		req.URL.Scheme = "http"
+		req.URL.Host = t.host
+		req.Host = t.host
+	}
+	return t.next.RoundTrip(req)
+}
@@ -0,0 +1,94 @@
+package apptest
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/nmvalera/go-utils/app"
+	"github.com/nmvalera/go-utils/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type helloService struct {
+	ready atomic.Bool
+}
+
+func (s *helloService) Start(_ context.Context) error {
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		s.ready.Store(true)
+	}()
+	return nil
+}
+
+func (s *helloService) Stop(_ context.Context) error { return nil }
+
+func (s *helloService) Ready(_ context.Context) error {
+	if !s.ready.Load() {
+		return errors.New("not ready yet")
+	}
+	return nil
+}
+
+func (s *helloService) RegisterHandler(router *mux.Router) {
+	router.Path("/hello").Methods(http.MethodGet).HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte("hello"))
+	})
+}
+
+func TestApp(t *testing.T) {
+	a := New(t, WithAppOptions(app.WithName("hello-app")))
+
+	s := new(helloService)
+	app.Provide(a.App, "hello", func() (*helloService, error) {
+		a.EnableMainEntrypoint()
+		a.EnableHealthzEntrypoint()
+		return s, nil
+	})
+
+	a.Start()
+
+	// The app is ready once Start returns
+	assert.True(t, s.ready.Load())
+
+	// Entrypoints listen on ephemeral ports
+	assert.NotEqual(t, a.MainAddr(), a.HealthzAddr())
+	assert.NotContains(t, a.MainAddr(), ":8080")
+
+	resp, err := a.MainClient().Get("/hello")
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Equal(t, "hello", string(body))
+
+	resp, err = a.HealthzClient().Get("/live")
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	// Logs are captured
+	assert.Equal(t, 1, a.Logs.FilterMessage("System successfully started").Len())
+	assert.Equal(t, "hello-app", a.Logs.FilterMessage("System successfully started").All()[0].ContextMap()["app"])
+}
+
+func TestAppWithoutEntrypoints(t *testing.T) {
+	a := New(t, WithConfig(func(cfg *app.Config) {
+		cfg.StopTimeout = common.Ptr(time.Second)
+	}))
+
+	app.Provide(a.App, "value", func() (string, error) {
+		return "value", nil
+	})
+
+	a.Start()
+	assert.Nil(t, a.MainEntrypoint())
+	assert.Positive(t, a.Logs.Len())
+}
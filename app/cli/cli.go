@@ -0,0 +1,208 @@
+// Package cli runs an app.App as a command line application.
+//
+// NewCommand builds a root command with standard subcommands
+//   - run: runs the app
+//   - config print: prints the effective configuration as JSON
+//   - config env: prints the effective configuration as environment variables
+//     (sensitive values are redacted, see WithRedactedConfigKeys)
+//   - healthcheck: probes the healthz entrypoint of a running instance
+//   - version: prints the version of the binary
+//
+// Configuration is loaded once for all subcommands with precedence flag > environment variable > config file > default.
+package cli
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/nmvalera/go-utils/app"
+	"github.com/nmvalera/go-utils/common"
+	"github.com/nmvalera/go-utils/config"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// SetupFunc provides the services of the application on the given app (see app.Provide)
+// It is called by the run subcommand before the app is run.
+type SetupFunc[C any] func(a *app.App, cfg *C) error
+
+// Option configures the command built by NewCommand
+type Option func(*options)
+
+type options struct {
+	version      string
+	short        string
+	appOptions   []app.Option
+	redactedKeys []string
+}
+
+// WithVersion sets the version of the application
+// It is printed by the version subcommand and set on the app (see app.WithVersion).
+func WithVersion(version string) Option {
+	return func(o *options) {
+		o.version = version
+	}
+}
+
+// WithShort sets the short description of the root command
+func WithShort(short string) Option {
+	return func(o *options) {
+		o.short = short
+	}
+}
+
+// WithAppOptions sets options on the app created by the run subcommand
+func WithAppOptions(opts ...app.Option) Option {
+	return func(o *options) {
+		o.appOptions = append(o.appOptions, opts...)
+	}
+}
+
+// WithRedactedConfigKeys redacts the values of the configuration fields whose key contains one of the given keys
+// in the output of the config subcommands and in the admin API (see app.WithRedactedConfigKeys)
+//
+// Values of default sensitive keys (password, secret, token...) are always redacted.
+func WithRedactedConfigKeys(keys ...string) Option {
+	return func(o *options) {
+		o.redactedKeys = append(o.redactedKeys, keys...)
+		o.appOptions = append(o.appOptions, app.WithRedactedConfigKeys(keys...))
+	}
+}
+
+// command holds the configuration shared by all subcommands
+type command[C any] struct {
+	name          string
+	opts          *options
+	defaultConfig func() *C
+	setup         SetupFunc[C]
+
+	v          *viper.Viper
+	configFile string
+	appCfg     *app.Config
+	cfg        *C
+}
+
+// NewCommand returns the root command of the application with the given name
+//
+// The application configuration C holds the fields specific to the application.
+// Its flags, environment variables and keys (see config.AddFlags) are set next to the ones of app.Config,
+// so they must not collide. Use struct{} if the application has no specific configuration.
+//
+// defaultConfig returns the default application configuration and setup provides the services of the application.
+func NewCommand[C any](name string, defaultConfig func() *C, setup SetupFunc[C], opts ...Option) *cobra.Command {
+	o := new(options)
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	c := &command[C]{
+		name:          name,
+		opts:          o,
+		defaultConfig: defaultConfig,
+		setup:         setup,
+		v:             config.NewViper(),
+	}
+
+	return c.rootCmd()
+}
+
+func (c *command[C]) rootCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:          c.name,
+		Short:        c.opts.short,
+		SilenceUsage: true,
+		PersistentPreRunE: func(*cobra.Command, []string) error {
+			return c.loadConfig()
+		},
+	}
+
+	// Configuration flags are bound once to viper so they are persistent flags shared by all subcommands
+	flags := cmd.PersistentFlags()
+	flags.StringVar(&c.configFile, "config", "", "Path to a config file (JSON, YAML or TOML)")
+	if err := app.AddFlags(c.v, flags); err != nil {
+		panic(fmt.Sprintf("invalid app config: %v", err))
+	}
+	if err := config.AddFlags(c.defaultConfig(), c.v, flags); err != nil {
+		panic(fmt.Sprintf("invalid application config: %v", err))
+	}
+
+	// app.Config already declares a --version flag so we do not set cmd.Version
+	cmd.AddCommand(
+		c.runCmd(),
+		c.configCmd(),
+		c.healthcheckCmd(),
+		c.versionCmd(),
+	)
+
+	return cmd
+}
+
+// loadConfig reads the config file (if any) and unmarshals the app and application configurations
+func (c *command[C]) loadConfig() error {
+	if c.configFile != "" {
+		c.v.SetConfigFile(c.configFile)
+		if err := c.v.ReadInConfig(); err != nil {
+			return fmt.Errorf("failed to read config file: %w", err)
+		}
+	}
+
+	c.appCfg = new(app.Config)
+	if err := c.appCfg.Unmarshal(c.v); err != nil {
+		return fmt.Errorf("failed to unmarshal app config: %w", err)
+	}
+
+	c.cfg = new(C)
+	if err := config.Unmarshal(c.cfg, c.v); err != nil {
+		return fmt.Errorf("failed to unmarshal config: %w", err)
+	}
+
+	return nil
+}
+
+// appName returns the name of the application, giving precedence to the configured one
+func (c *command[C]) appName() string {
+	if name := common.Val(c.appCfg.Name); name != "" {
+		return name
+	}
+	return c.name
+}
+
+// appVersion returns the version of the application, giving precedence to the configured one
+func (c *command[C]) appVersion() string {
+	if version := common.Val(c.appCfg.Version); version != "" {
+		return version
+	}
+	return c.opts.version
+}
+
+func (c *command[C]) runCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "run",
+		Short: "Run the application",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			appOpts := []app.Option{
+				app.WithName(c.appName()),
+				app.WithVersion(c.appVersion()),
+				app.WithReloadableConfig(c.v),
+			}
+
+			a, err := app.NewApp(c.appCfg, append(appOpts, c.opts.appOptions...)...)
+			if err != nil {
+				return fmt.Errorf("failed to create app: %w", err)
+			}
+
+			if err := c.setup(a, c.cfg); err != nil {
+				return fmt.Errorf("failed to setup app: %w", err)
+			}
+
+			// App.Run stops on signals, so we also stop it when the command context is done
+			// (e.g. when executed with cobra.Command.ExecuteContext)
+			ctx := cmd.Context()
+			stop := context.AfterFunc(ctx, a.Shutdown)
+			defer stop()
+
+			return a.Run(context.WithoutCancel(ctx))
+		},
+	}
+}
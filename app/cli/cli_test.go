@@ -0,0 +1,152 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nmvalera/go-utils/app"
+	"github.com/nmvalera/go-utils/common"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testConfig struct {
+	RPCURL  *string `key:"rpcUrl" env:"RPC_URL" flag:"rpc-url" desc:"RPC URL"`
+	Workers *int    `key:"workers" env:"WORKERS" flag:"workers" desc:"Number of workers"`
+}
+
+func defaultTestConfig() *testConfig {
+	return &testConfig{
+		RPCURL:  common.Ptr("http://localhost:8545"),
+		Workers: common.Ptr(4),
+	}
+}
+
+func noSetup(*app.App, *testConfig) error { return nil }
+
+func execute(t *testing.T, cmd *cobra.Command, args ...string) (string, error) {
+	t.Helper()
+	out := new(bytes.Buffer)
+	cmd.SetOut(out)
+	cmd.SetErr(out)
+	cmd.SetArgs(args)
+	err := cmd.Execute()
+	return out.String(), err
+}
+
+func TestConfigPrint(t *testing.T) {
+	dir := t.TempDir()
+	configFile := filepath.Join(dir, "config.yaml")
+	require.NoError(t, os.WriteFile(configFile, []byte("rpcUrl: http://file:8545\nworkers: 8\nname: from-file\n"), 0o600))
+
+	// Precedence is flag > env > file > default
+	t.Setenv("WORKERS", "16")
+
+	cmd := NewCommand("test", defaultTestConfig, noSetup)
+	out, err := execute(t, cmd, "config", "print", "--config", configFile, "--rpc-url", "http://flag:8545")
+	require.NoError(t, err)
+
+	var cfg map[string]any
+	require.NoError(t, json.Unmarshal([]byte(out), &cfg))
+	assert.Equal(t, "http://flag:8545", cfg["rpcUrl"])
+	assert.InDelta(t, 16, cfg["workers"], 0)
+	assert.Equal(t, "from-file", cfg["name"])
+	assert.Equal(t, ":8081", cfg["healthzEp"].(map[string]any)["addr"])
+}
+
+func TestConfigEnv(t *testing.T) {
+	cmd := NewCommand("test", defaultTestConfig, noSetup)
+	out, err := execute(t, cmd, "config", "env", "--workers", "2")
+	require.NoError(t, err)
+
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	assert.Contains(t, lines, "RPC_URL=http://localhost:8545")
+	assert.Contains(t, lines, "WORKERS=2")
+	assert.Contains(t, lines, "HEALTHZ_EP_ADDR=:8081")
+}
+
+func TestConfigRedacted(t *testing.T) {
+	cmd := NewCommand("test", defaultTestConfig, noSetup, WithRedactedConfigKeys("rpcUrl"))
+	out, err := execute(t, cmd, "config", "print", "--healthz-api-admin-token", "admin-secret")
+	require.NoError(t, err)
+	assert.NotContains(t, out, "admin-secret")
+
+	var cfg map[string]any
+	require.NoError(t, json.Unmarshal([]byte(out), &cfg))
+	assert.Equal(t, "[REDACTED]", cfg["healthzApi"].(map[string]any)["adminToken"])
+	assert.Equal(t, "[REDACTED]", cfg["rpcUrl"])
+	assert.InDelta(t, 4, cfg["workers"], 0)
+
+	cmd = NewCommand("test", defaultTestConfig, noSetup, WithRedactedConfigKeys("rpcUrl"))
+	out, err = execute(t, cmd, "config", "env", "--healthz-api-admin-token", "admin-secret")
+	require.NoError(t, err)
+	assert.NotContains(t, out, "admin-secret")
+
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	assert.Contains(t, lines, "HEALTHZ_API_ADMIN_TOKEN=[REDACTED]")
+	assert.Contains(t, lines, "RPC_URL=[REDACTED]")
+	assert.Contains(t, lines, "WORKERS=4")
+}
+
+func TestHealthcheck(t *testing.T) {
+	ready := true
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/ready" && ready {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+	addr := strings.TrimPrefix(srv.URL, "http://")
+
+	out, err := execute(t, NewCommand("test", defaultTestConfig, noSetup), "healthcheck", "--healthz-ep-addr", addr)
+	require.NoError(t, err)
+	assert.Equal(t, "healthy\n", out)
+
+	ready = false
+	_, err = execute(t, NewCommand("test", defaultTestConfig, noSetup), "healthcheck", "--addr", addr)
+	require.Error(t, err)
+
+	_, err = execute(t, NewCommand("test", defaultTestConfig, noSetup), "healthcheck", "--addr", addr, "--probe", "invalid")
+	require.Error(t, err)
+}
+
+func TestVersion(t *testing.T) {
+	out, err := execute(t, NewCommand("test", defaultTestConfig, noSetup, WithVersion("1.2.3")), "version")
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(out, "test 1.2.3 ("), out)
+}
+
+func TestRun(t *testing.T) {
+	var (
+		gotName string
+		gotCfg  *testConfig
+	)
+	setup := func(a *app.App, cfg *testConfig) error {
+		gotName = a.BuildInfo().Name
+		gotCfg = cfg
+		app.Provide(a, "svc", func() (string, error) { return "value", nil })
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	cmd := NewCommand("test", defaultTestConfig, setup)
+	cmd.SetArgs([]string{"run", "--workers", "3", "--log-level", "error"})
+	require.NoError(t, cmd.ExecuteContext(ctx))
+
+	assert.Equal(t, "test", gotName)
+	require.NotNil(t, gotCfg)
+	assert.Equal(t, 3, common.Val(gotCfg.Workers))
+}
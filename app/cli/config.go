@@ -0,0 +1,103 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/nmvalera/go-utils/app"
+	"github.com/nmvalera/go-utils/config"
+	"github.com/spf13/cobra"
+)
+
+func (c *command[C]) configCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "config",
+		Short: "Inspect the effective configuration",
+	}
+
+	cmd.AddCommand(
+		&cobra.Command{
+			Use:   "print",
+			Short: "Print the effective configuration as JSON",
+			Args:  cobra.NoArgs,
+			RunE: func(cmd *cobra.Command, _ []string) error {
+				b, err := c.marshalConfig()
+				if err != nil {
+					return err
+				}
+				_, err = fmt.Fprintln(cmd.OutOrStdout(), string(b))
+				return err
+			},
+		},
+		&cobra.Command{
+			Use:   "env",
+			Short: "Print the effective configuration as environment variables",
+			Args:  cobra.NoArgs,
+			RunE: func(cmd *cobra.Command, _ []string) error {
+				env, err := c.env()
+				if err != nil {
+					return err
+				}
+				for _, k := range slices.Sorted(maps.Keys(env)) {
+					if _, err := fmt.Fprintf(cmd.OutOrStdout(), "%s=%s\n", k, quoteEnv(env[k])); err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+		},
+	)
+
+	return cmd
+}
+
+// marshalConfig returns the app and application configurations merged in a single indented JSON object
+// with the values of sensitive fields redacted (see WithRedactedConfigKeys)
+func (c *command[C]) marshalConfig() ([]byte, error) {
+	merged := make(map[string]any)
+	for _, cfg := range []any{c.appCfg, c.cfg} {
+		b, err := config.Marshal(cfg)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal config: %w", err)
+		}
+
+		var m map[string]any
+		if err := json.Unmarshal(b, &m); err != nil {
+			return nil, fmt.Errorf("failed to marshal config: %w", err)
+		}
+		maps.Copy(merged, m)
+	}
+	app.RedactConfig(merged, c.opts.redactedKeys...)
+
+	return json.MarshalIndent(merged, "", "  ")
+}
+
+// env returns the environment variables of the app and application configurations
+// with the values of sensitive variables redacted (see WithRedactedConfigKeys)
+func (c *command[C]) env() (map[string]string, error) {
+	env, err := c.appCfg.Env()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get app config environment variables: %w", err)
+	}
+
+	cfgEnv, err := config.Env(c.cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get config environment variables: %w", err)
+	}
+	maps.Copy(env, cfgEnv)
+	app.RedactEnv(env, c.opts.redactedKeys...)
+
+	return env, nil
+}
+
+// quoteEnv quotes values that can not be used unquoted in a shell or .env file
+func quoteEnv(val string) string {
+	if strings.ContainsAny(val, " \t\n\"'$`\\#") {
+		return strconv.Quote(val)
+	}
+	return val
+}
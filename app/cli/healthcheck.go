@@ -0,0 +1,104 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/nmvalera/go-utils/common"
+	"github.com/spf13/cobra"
+)
+
+func (c *command[C]) healthcheckCmd() *cobra.Command {
+	var (
+		probe   string
+		addr    string
+		timeout time.Duration
+	)
+
+	cmd := &cobra.Command{
+		Use:   "healthcheck",
+		Short: "Probe the healthz entrypoint of a running instance",
+		Long: "Probe the healthz entrypoint of a running instance and exit with a non-zero code if it is not healthy.\n" +
+			"It is typically used as a container HEALTHCHECK.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			url, err := c.probeURL(probe, addr)
+			if err != nil {
+				return err
+			}
+
+			ctx, cancel := context.WithTimeout(cmd.Context(), timeout)
+			defer cancel()
+
+			status, err := healthcheck(ctx, url)
+			if err != nil {
+				return err
+			}
+			_, err = fmt.Fprintln(cmd.OutOrStdout(), status)
+			return err
+		},
+	}
+
+	cmd.Flags().StringVar(&probe, "probe", "ready", "Probe to check (live, ready or startup)")
+	cmd.Flags().StringVar(&addr, "addr", "", "Address of the healthz entrypoint (defaults to the configured healthz entrypoint address on localhost)")
+	cmd.Flags().DurationVar(&timeout, "timeout", 5*time.Second, "Timeout of the probe")
+
+	return cmd
+}
+
+// probeURL returns the URL of the given probe on the healthz entrypoint
+func (c *command[C]) probeURL(probe, addr string) (string, error) {
+	var path *string
+	switch probe {
+	case "live":
+		path = c.appCfg.HealthzServer.LivenessPath
+	case "ready":
+		path = c.appCfg.HealthzServer.ReadinessPath
+	case "startup":
+		path = c.appCfg.HealthzServer.StartupPath
+	default:
+		return "", fmt.Errorf("invalid probe %q (expected live, ready or startup)", probe)
+	}
+
+	if addr == "" {
+		addr = common.Val(c.appCfg.HealthzEntrypoint.Addr)
+	}
+
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return "", fmt.Errorf("invalid healthz address %q: %w", addr, err)
+	}
+
+	// The healthz entrypoint typically listens on all interfaces
+	if host == "" || host == "0.0.0.0" || host == "::" {
+		host = "localhost"
+	}
+
+	return fmt.Sprintf("http://%s%s", net.JoinHostPort(host, port), common.Val(path)), nil
+}
+
+// healthcheck calls the probe at url and returns its status if it is healthy
+func healthcheck(ctx context.Context, url string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, http.NoBody)
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("healthcheck failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("unhealthy: %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+
+	return "healthy", nil
+}
@@ -0,0 +1,46 @@
+package cli
+
+import (
+	"fmt"
+	"runtime"
+	"runtime/debug"
+
+	"github.com/spf13/cobra"
+)
+
+func (c *command[C]) versionCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "version",
+		Short: "Print the version",
+		Args:  cobra.NoArgs,
+		// The version does not depend on the configuration so an invalid configuration does not prevent printing it
+		PersistentPreRunE: func(*cobra.Command, []string) error { return nil },
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			_, err := fmt.Fprintln(cmd.OutOrStdout(), c.versionString())
+			return err
+		},
+	}
+}
+
+// versionString returns the version of the binary, e.g. "my-app 1.0.0 (revision 1a2b3c4, go1.24.1)"
+func (c *command[C]) versionString() string {
+	version := c.opts.version
+	if version == "" {
+		version = "unknown"
+	}
+
+	goVersion, revision := runtime.Version(), ""
+	if bi, ok := debug.ReadBuildInfo(); ok {
+		goVersion = bi.GoVersion
+		for _, setting := range bi.Settings {
+			if setting.Key == "vcs.revision" {
+				revision = setting.Value
+			}
+		}
+	}
+
+	if revision == "" {
+		return fmt.Sprintf("%s %s (%s)", c.name, version, goVersion)
+	}
+	return fmt.Sprintf("%s %s (revision %s, %s)", c.name, version, revision, goVersion)
+}
@@ -6,7 +6,9 @@ import (
 	"github.com/nmvalera/go-utils/common"
 	"github.com/nmvalera/go-utils/config"
 	"github.com/nmvalera/go-utils/log"
+	kkrtgrpc "github.com/nmvalera/go-utils/net/grpc"
 	kkrthttp "github.com/nmvalera/go-utils/net/http"
+	"github.com/nmvalera/go-utils/tracing"
 	"github.com/spf13/pflag"
 	"github.com/spf13/viper"
 )
@@ -16,17 +18,34 @@ func DefaultConfig() *Config {
 	mainEp.Addr = common.Ptr(":8080")
 	healthzEp := kkrthttp.DefaultEntrypointConfig()
 	healthzEp.Addr = common.Ptr(":8081")
+	grpcEp := kkrtgrpc.DefaultEntrypointConfig()
+	grpcEp.Addr = common.Ptr(":9090")
 	return &Config{
 		MainEntrypoint:    mainEp,
 		HealthzEntrypoint: healthzEp,
+		GRPCEntrypoint:    grpcEp,
 		HealthzServer: &HealthzServerConfig{
-			LivenessPath:  common.Ptr("/live"),
-			ReadinessPath: common.Ptr("/ready"),
-			MetricsPath:   common.Ptr("/metrics"),
+			LivenessPath:          common.Ptr("/live"),
+			ReadinessPath:         common.Ptr("/ready"),
+			StartupPath:           common.Ptr("/startup"),
+			MetricsPath:           common.Ptr("/metrics"),
+			GraphPath:             common.Ptr(""),
+			BuildInfoPath:         common.Ptr("/buildinfo"),
+			EnablePprof:           common.Ptr(false),
+			EnableAdmin:           common.Ptr(false),
+			AdminPath:             common.Ptr("/admin"),
+			AdminToken:            common.Ptr(""),
+			CheckInterval:         common.Ptr(time.Duration(0)),
+			CheckTimeout:          common.Ptr(2 * time.Second),
+			CheckFailureThreshold: common.Ptr(1),
+			EnableBuildInfoMetric: common.Ptr(true),
 		},
 		Log:          log.DefaultConfig(),
 		StartTimeout: common.Ptr(15 * time.Second),
 		StopTimeout:  common.Ptr(15 * time.Second),
+		DrainDelay:   common.Ptr(time.Duration(0)),
+		DrainTimeout: common.Ptr(15 * time.Second),
+		Tracing:      tracing.DefaultConfig(),
 		Tags:         map[string]any{},
 	}
 }
@@ -37,10 +56,14 @@ type Config struct {
 	Version           *string                    `key:"version" env:"VERSION" flag:"version" desc:"Application version"`
 	MainEntrypoint    *kkrthttp.EntrypointConfig `key:"mainEp" env:"MAIN_EP" flag:"main-ep" desc:"main entrypoint: "`
 	HealthzEntrypoint *kkrthttp.EntrypointConfig `key:"healthzEp" env:"HEALTHZ_EP" flag:"healthz-ep" desc:"healthz entrypoint: "`
+	GRPCEntrypoint    *kkrtgrpc.EntrypointConfig `key:"grpcEp" env:"GRPC_EP" flag:"grpc-ep" desc:"gRPC entrypoint: "`
 	HealthzServer     *HealthzServerConfig       `key:"healthzApi" env:"HEALTHZ_API" flag:"healthz-api" desc:"healthz API: "`
 	Log               *log.Config                `key:"log"`
 	StartTimeout      *time.Duration             `key:"startTimeout" env:"START_TIMEOUT" flag:"start-timeout" desc:"Start timeout"`
 	StopTimeout       *time.Duration             `key:"stopTimeout" env:"STOP_TIMEOUT" flag:"stop-timeout" desc:"Stop timeout"`
+	DrainDelay        *time.Duration             `key:"drainDelay" env:"DRAIN_DELAY" flag:"drain-delay" desc:"Delay between marking the app not ready and draining services on shutdown"`
+	DrainTimeout      *time.Duration             `key:"drainTimeout" env:"DRAIN_TIMEOUT" flag:"drain-timeout" desc:"Drain timeout"`
+	Tracing           *tracing.Config            `key:"tracing" env:"TRACING" flag:"tracing" desc:"tracing: "`
 	Tags              map[string]any             `key:"tags" env:"TAGS" flag:"tags" desc:"Tags to attach to contexts (key=value pairs)"`
 }
 
@@ -51,7 +74,20 @@ func (cfg *Config) MarshalJSON() ([]byte, error) {
 type HealthzServerConfig struct {
 	LivenessPath  *string `key:"livenessPath" env:"LIVENESS_PATH" flag:"liveness-path" desc:"Path on which the liveness probe will be served"`
 	ReadinessPath *string `key:"readinessPath" env:"READINESS_PATH" flag:"readiness-path" desc:"Path on which the readiness probe will be served"`
+	StartupPath   *string `key:"startupPath" env:"STARTUP_PATH" flag:"startup-path" desc:"Path on which the startup probe will be served"`
 	MetricsPath   *string `key:"metricsPath" env:"METRICS_PATH" flag:"metrics-path" desc:"Path on which the metrics will be served"`
+	GraphPath     *string `key:"graphPath" env:"GRAPH_PATH" flag:"graph-path" desc:"Path on which the service dependency graph will be served as JSON or Graphviz DOT with ?format=dot (empty disables it)"`
+	BuildInfoPath *string `key:"buildInfoPath" env:"BUILD_INFO_PATH" flag:"build-info-path" desc:"Path on which the build and runtime info will be served (empty disables it)"`
+	EnablePprof   *bool   `key:"enablePprof" env:"ENABLE_PPROF" flag:"enable-pprof" desc:"Serve pprof profiling endpoints on /debug/pprof/"`
+	EnableAdmin   *bool   `key:"enableAdmin" env:"ENABLE_ADMIN" flag:"enable-admin" desc:"Serve the admin API to inspect and control the app at runtime"`
+	AdminPath     *string `key:"adminPath" env:"ADMIN_PATH" flag:"admin-path" desc:"Path prefix on which the admin API will be served"`
+	AdminToken    *string `key:"adminToken" env:"ADMIN_TOKEN" flag:"admin-token" desc:"Bearer token required to call the admin API (empty disables authentication)"`
+
+	CheckInterval         *time.Duration `key:"checkInterval" env:"CHECK_INTERVAL" flag:"check-interval" desc:"Interval at which readiness checks run in the background with probes serving their cached results (zero runs checks on every probe)"`
+	CheckTimeout          *time.Duration `key:"checkTimeout" env:"CHECK_TIMEOUT" flag:"check-timeout" desc:"Default timeout of readiness checks"`
+	CheckFailureThreshold *int           `key:"checkFailureThreshold" env:"CHECK_FAILURE_THRESHOLD" flag:"check-failure-threshold" desc:"Default number of consecutive failures before a readiness check reports failing"`
+
+	EnableBuildInfoMetric *bool `key:"enableBuildInfoMetric" env:"ENABLE_BUILD_INFO_METRIC" flag:"enable-build-info-metric" desc:"Expose the <app>_build_info metric"`
 }
 
 func (cfg *HealthzServerConfig) MarshalJSON() ([]byte, error) {
@@ -7,7 +7,9 @@ import (
 	"github.com/nmvalera/go-utils/common"
 	"github.com/nmvalera/go-utils/config"
 	"github.com/nmvalera/go-utils/log"
+	kkrtgrpc "github.com/nmvalera/go-utils/net/grpc"
 	kkrthttp "github.com/nmvalera/go-utils/net/http"
+	"github.com/nmvalera/go-utils/tracing"
 	"github.com/spf13/pflag"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -241,14 +243,57 @@ func TestAddFlagsAndLoadEnv(t *testing.T) {
 			},
 			TLS: &kkrthttp.TLSCertConfig{},
 		},
+		GRPCEntrypoint: &kkrtgrpc.EntrypointConfig{
+			Addr: common.Ptr("localhost:8887"),
+			GRPC: &kkrtgrpc.ServerConfig{
+				MaxRecvMsgSize:       common.Ptr(60000),
+				MaxSendMsgSize:       common.Ptr(61000),
+				MaxConcurrentStreams: common.Ptr(62),
+				ConnectionTimeout:    common.Ptr(63 * time.Second),
+				MaxConnectionIdle:    common.Ptr(64 * time.Second),
+				MaxConnectionAge:     common.Ptr(65 * time.Second),
+				KeepAliveTime:        common.Ptr(66 * time.Second),
+				KeepAliveTimeout:     common.Ptr(67 * time.Second),
+			},
+			Net: &kkrthttp.ListenConfig{
+				KeepAlive: common.Ptr(68 * time.Second),
+				KeepAliveProbe: &kkrthttp.KeepAliveProbeConfig{
+					Enable:   common.Ptr(true),
+					Idle:     common.Ptr(69 * time.Second),
+					Interval: common.Ptr(70 * time.Second),
+					Count:    common.Ptr(71),
+				},
+			},
+			TLS: &kkrthttp.TLSCertConfig{},
+		},
 		HealthzServer: &HealthzServerConfig{
-			LivenessPath:  common.Ptr("/live"),
-			ReadinessPath: common.Ptr("/ready"),
-			MetricsPath:   common.Ptr("/metrics"),
+			LivenessPath:          common.Ptr("/live"),
+			ReadinessPath:         common.Ptr("/ready"),
+			StartupPath:           common.Ptr("/started"),
+			MetricsPath:           common.Ptr("/metrics"),
+			GraphPath:             common.Ptr(""),
+			BuildInfoPath:         common.Ptr("/version"),
+			EnablePprof:           common.Ptr(true),
+			EnableAdmin:           common.Ptr(true),
+			AdminPath:             common.Ptr("/internal"),
+			AdminToken:            common.Ptr("admin-token"),
+			CheckInterval:         common.Ptr(10 * time.Second),
+			CheckTimeout:          common.Ptr(3 * time.Second),
+			CheckFailureThreshold: common.Ptr(3),
+			EnableBuildInfoMetric: common.Ptr(false),
 		},
 		Log:          log.DefaultConfig(),
 		StartTimeout: common.Ptr(10 * time.Second),
 		StopTimeout:  common.Ptr(20 * time.Second),
+		DrainDelay:   common.Ptr(5 * time.Second),
+		DrainTimeout: common.Ptr(25 * time.Second),
+		Tracing: &tracing.Config{
+			Exporter:    common.Ptr("otlphttp"),
+			File:        common.Ptr("spans.json"),
+			Endpoint:    common.Ptr("collector:4318"),
+			Insecure:    common.Ptr(true),
+			SampleRatio: common.Ptr(0.5),
+		},
 		Tags: map[string]any{
 			"env":     "production",
 			"cluster": "us-east-1",
@@ -267,7 +312,7 @@ func TestAddFlagsAndLoadEnv(t *testing.T) {
 	err := AddFlags(v, set)
 	require.NoError(t, err)
 
-	expectedUsage := "      --healthz-api-liveness-path string                  healthz API: Path on which the liveness probe will be served [env: HEALTHZ_API_LIVENESS_PATH] (default \"/live\")\n      --healthz-api-metrics-path string                   healthz API: Path on which the metrics will be served [env: HEALTHZ_API_METRICS_PATH] (default \"/metrics\")\n      --healthz-api-readiness-path string                 healthz API: Path on which the readiness probe will be served [env: HEALTHZ_API_READINESS_PATH] (default \"/ready\")\n      --healthz-ep-addr string                            healthz entrypoint: TCP Address to listen on [env: HEALTHZ_EP_ADDR] (default \":8081\")\n      --healthz-ep-http-idle-timeout string               healthz entrypoint: Maximum duration to wait for the next request when keep-alives are enabled (zero uses the value of read timeout) [env: HEALTHZ_EP_HTTP_IDLE_TIMEOUT] (default \"30s\")\n      --healthz-ep-http-max-header-bytes int              healthz entrypoint: Maximum number of bytes the server will read parsing the request header's keys and values [env: HEALTHZ_EP_HTTP_MAX_HEADER_BYTES] (default 1048576)\n      --healthz-ep-http-read-header-timeout string        healthz entrypoint: Maximum duration for reading request headers (zero uses the value of read timeout) [env: HEALTHZ_EP_HTTP_READ_HEADER_TIMEOUT] (default \"30s\")\n      --healthz-ep-http-read-timeout string               healthz entrypoint: Maximum duration for reading the entire request including the body (zero means no timeout) [env: HEALTHZ_EP_HTTP_READ_TIMEOUT] (default \"30s\")\n      --healthz-ep-http-write-timeout string              healthz entrypoint: Maximum duration before timing out writes of the response (zero means no timeout) [env: HEALTHZ_EP_HTTP_WRITE_TIMEOUT] (default \"30s\")\n      --healthz-ep-net-keep-alive string                  healthz entrypoint: Keep alive period for network connections accepted by this entrypoint [env: HEALTHZ_EP_NET_KEEP_ALIVE] (default \"-1s\")\n      --healthz-ep-net-keep-alive-probe-count int         healthz entrypoint: Maximum number of keep-alive probes that can go unanswered before dropping a connection [env: HEALTHZ_EP_NET_KEEP_ALIVE_PROBE_COUNT] (default 9)\n      --healthz-ep-net-keep-alive-probe-enable            healthz entrypoint: Enable keep alive probes [env: HEALTHZ_EP_NET_KEEP_ALIVE_PROBE_ENABLE]\n      --healthz-ep-net-keep-alive-probe-idle string       healthz entrypoint: Time that the connection must be idle before the first keep-alive probe is sent [env: HEALTHZ_EP_NET_KEEP_ALIVE_PROBE_IDLE] (default \"15s\")\n      --healthz-ep-net-keep-alive-probe-interval string   healthz entrypoint: Time between keep-alive probes [env: HEALTHZ_EP_NET_KEEP_ALIVE_PROBE_INTERVAL] (default \"15s\")\n      --healthz-ep-tls-certfile string                    healthz entrypoint: Path to the certificate file [env: HEALTHZ_EP_TLS_CERT_FILE]\n      --healthz-ep-tls-keyfile string                     healthz entrypoint: Path to the key file [env: HEALTHZ_EP_TLS_KEY_FILE]\n      --log-enable-caller                                 Enable caller [env: LOG_ENABLE_CALLER]\n      --log-enable-stacktrace                             Enable automatic stacktrace capturing [env: LOG_ENABLE_STACKTRACE]\n      --log-encoding-caller-encoder string                Encoding: Primitive representation for the log caller (e.g. 'full' [env: LOG_ENCODING_CALLER_ENCODER] (default \"short\")\n      --log-encoding-caller-key string                    Encoding: Key for the log caller (if empty [env: LOG_ENCODING_CALLER_KEY] (default \"caller\")\n      --log-encoding-console-separator string             Encoding: Field separator used by the console encoder [env: LOG_ENCODING_CONSOLE_SEPARATOR] (default \"\\t\")\n      --log-encoding-duration-encoder string              Encoding: Primitive representation for the log duration (e.g. 'string' [env: LOG_ENCODING_DURATION_ENCODER] (default \"s\")\n      --log-encoding-function-key string                  Encoding: Key for the log function (if empty [env: LOG_ENCODING_FUNCTION_KEY]\n      --log-encoding-level-encoder string                 Encoding: Primitive representation for the log level (e.g. 'capital' [env: LOG_ENCODING_LEVEL_ENCODER] (default \"capitalColor\")\n      --log-encoding-level-key string                     Encoding: Key for the log level (if empty [env: LOG_ENCODING_LEVEL_KEY] (default \"level\")\n      --log-encoding-line-ending string                   Encoding: Line ending [env: LOG_ENCODING_LINE_ENDING] (default \"\\n\")\n      --log-encoding-message-key string                   Encoding: Key for the log message (if empty [env: LOG_ENCODING_MESSAGE_KEY] (default \"msg\")\n      --log-encoding-name-encoder string                  Encoding: Primitive representation for the log logger name (e.g. 'full' [env: LOG_ENCODING_NAME_ENCODER] (default \"full\")\n      --log-encoding-name-key string                      Encoding: Key for the log logger name (if empty [env: LOG_ENCODING_NAME_KEY] (default \"logger\")\n      --log-encoding-skip-line-ending                     Encoding: Skip the line ending [env: LOG_ENCODING_SKIP_LINE_ENDING]\n      --log-encoding-stacktrace-key string                Encoding: Key for the log stacktrace (if empty [env: LOG_ENCODING_STACKTRACE_KEY] (default \"stacktrace\")\n      --log-encoding-time-encoder string                  Encoding: Primitive representation for the log timestamp (e.g. 'rfc3339nano' [env: LOG_ENCODING_TIME_ENCODER] (default \"rfc3339\")\n      --log-encoding-time-key string                      Encoding: Key for the log timestamp (if empty [env: LOG_ENCODING_TIME_KEY] (default \"ts\")\n      --log-err-output strings                            List of URLs to write internal logger errors to [env: LOG_ERROR_OUTPUT_PATHS] (default [stderr])\n      --log-format string                                 Log format [env: LOG_FORMAT] (default \"text\")\n      --log-level string                                  Minimum enabled logging level [env: LOG_LEVEL] (default \"info\")\n      --log-output strings                                List of URLs or file paths to write logging output to [env: LOG_OUTPUT_PATHS] (default [stderr])\n      --log-sampling-initial int                          Sampling: Number of log entries with the same level and message to log before dropping entries [env: LOG_SAMPLING_INITIAL] (default 100)\n      --log-sampling-thereafter int                       Sampling: After the initial number of entries [env: LOG_SAMPLING_THEREAFTER] (default 100)\n      --main-ep-addr string                               main entrypoint: TCP Address to listen on [env: MAIN_EP_ADDR] (default \":8080\")\n      --main-ep-http-idle-timeout string                  main entrypoint: Maximum duration to wait for the next request when keep-alives are enabled (zero uses the value of read timeout) [env: MAIN_EP_HTTP_IDLE_TIMEOUT] (default \"30s\")\n      --main-ep-http-max-header-bytes int                 main entrypoint: Maximum number of bytes the server will read parsing the request header's keys and values [env: MAIN_EP_HTTP_MAX_HEADER_BYTES] (default 1048576)\n      --main-ep-http-read-header-timeout string           main entrypoint: Maximum duration for reading request headers (zero uses the value of read timeout) [env: MAIN_EP_HTTP_READ_HEADER_TIMEOUT] (default \"30s\")\n      --main-ep-http-read-timeout string                  main entrypoint: Maximum duration for reading the entire request including the body (zero means no timeout) [env: MAIN_EP_HTTP_READ_TIMEOUT] (default \"30s\")\n      --main-ep-http-write-timeout string                 main entrypoint: Maximum duration before timing out writes of the response (zero means no timeout) [env: MAIN_EP_HTTP_WRITE_TIMEOUT] (default \"30s\")\n      --main-ep-net-keep-alive string                     main entrypoint: Keep alive period for network connections accepted by this entrypoint [env: MAIN_EP_NET_KEEP_ALIVE] (default \"-1s\")\n      --main-ep-net-keep-alive-probe-count int            main entrypoint: Maximum number of keep-alive probes that can go unanswered before dropping a connection [env: MAIN_EP_NET_KEEP_ALIVE_PROBE_COUNT] (default 9)\n      --main-ep-net-keep-alive-probe-enable               main entrypoint: Enable keep alive probes [env: MAIN_EP_NET_KEEP_ALIVE_PROBE_ENABLE]\n      --main-ep-net-keep-alive-probe-idle string          main entrypoint: Time that the connection must be idle before the first keep-alive probe is sent [env: MAIN_EP_NET_KEEP_ALIVE_PROBE_IDLE] (default \"15s\")\n      --main-ep-net-keep-alive-probe-interval string      main entrypoint: Time between keep-alive probes [env: MAIN_EP_NET_KEEP_ALIVE_PROBE_INTERVAL] (default \"15s\")\n      --main-ep-tls-certfile string                       main entrypoint: Path to the certificate file [env: MAIN_EP_TLS_CERT_FILE]\n      --main-ep-tls-keyfile string                        main entrypoint: Path to the key file [env: MAIN_EP_TLS_KEY_FILE]\n      --name string                                       Application name [env: NAME]\n      --start-timeout string                              Start timeout [env: START_TIMEOUT] (default \"15s\")\n      --stop-timeout string                               Stop timeout [env: STOP_TIMEOUT] (default \"15s\")\n      --tags strings                                      Tags to attach to contexts (key=value pairs) [env: TAGS]\n      --version string                                    Application version [env: VERSION]\n"
+	expectedUsage := "      --drain-delay string                                Delay between marking the app not ready and draining services on shutdown [env: DRAIN_DELAY] (default \"0s\")\n      --drain-timeout string                              Drain timeout [env: DRAIN_TIMEOUT] (default \"15s\")\n      --grpc-ep-addr string                               gRPC entrypoint: TCP Address to listen on [env: GRPC_EP_ADDR] (default \":9090\")\n      --grpc-ep-grpc-connection-timeout string            gRPC entrypoint: Maximum duration for new connections to complete their handshake [env: GRPC_EP_GRPC_CONNECTION_TIMEOUT] (default \"2m0s\")\n      --grpc-ep-grpc-keep-alive-time string               gRPC entrypoint: Duration without activity after which the server pings the client [env: GRPC_EP_GRPC_KEEP_ALIVE_TIME] (default \"2h0m0s\")\n      --grpc-ep-grpc-keep-alive-timeout string            gRPC entrypoint: Duration the server waits for a ping acknowledgement before closing the connection [env: GRPC_EP_GRPC_KEEP_ALIVE_TIMEOUT] (default \"20s\")\n      --grpc-ep-grpc-max-concurrent-streams int           gRPC entrypoint: Maximum number of concurrent streams per connection (zero means no limit) [env: GRPC_EP_GRPC_MAX_CONCURRENT_STREAMS]\n      --grpc-ep-grpc-max-connection-age string            gRPC entrypoint: Maximum duration a connection may exist before being gracefully closed (zero means no limit) [env: GRPC_EP_GRPC_MAX_CONNECTION_AGE] (default \"0s\")\n      --grpc-ep-grpc-max-connection-idle string           gRPC entrypoint: Duration after which an idle connection is closed (zero means no limit) [env: GRPC_EP_GRPC_MAX_CONNECTION_IDLE] (default \"0s\")\n      --grpc-ep-grpc-max-recv-msg-size int                gRPC entrypoint: Maximum size in bytes of a message the server can receive [env: GRPC_EP_GRPC_MAX_RECV_MSG_SIZE] (default 4194304)\n      --grpc-ep-grpc-max-send-msg-size int                gRPC entrypoint: Maximum size in bytes of a message the server can send [env: GRPC_EP_GRPC_MAX_SEND_MSG_SIZE] (default 2147483647)\n      --grpc-ep-net-keep-alive string                     gRPC entrypoint: Keep alive period for network connections accepted by this entrypoint [env: GRPC_EP_NET_KEEP_ALIVE] (default \"-1s\")\n      --grpc-ep-net-keep-alive-probe-count int            gRPC entrypoint: Maximum number of keep-alive probes that can go unanswered before dropping a connection [env: GRPC_EP_NET_KEEP_ALIVE_PROBE_COUNT] (default 9)\n      --grpc-ep-net-keep-alive-probe-enable               gRPC entrypoint: Enable keep alive probes [env: GRPC_EP_NET_KEEP_ALIVE_PROBE_ENABLE]\n      --grpc-ep-net-keep-alive-probe-idle string          gRPC entrypoint: Time that the connection must be idle before the first keep-alive probe is sent [env: GRPC_EP_NET_KEEP_ALIVE_PROBE_IDLE] (default \"15s\")\n      --grpc-ep-net-keep-alive-probe-interval string      gRPC entrypoint: Time between keep-alive probes [env: GRPC_EP_NET_KEEP_ALIVE_PROBE_INTERVAL] (default \"15s\")\n      --grpc-ep-tls-certfile string                       gRPC entrypoint: Path to the certificate file [env: GRPC_EP_TLS_CERT_FILE]\n      --grpc-ep-tls-keyfile string                        gRPC entrypoint: Path to the key file [env: GRPC_EP_TLS_KEY_FILE]\n      --healthz-api-admin-path string                     healthz API: Path prefix on which the admin API will be served [env: HEALTHZ_API_ADMIN_PATH] (default \"/admin\")\n      --healthz-api-admin-token string                    healthz API: Bearer token required to call the admin API (empty disables authentication) [env: HEALTHZ_API_ADMIN_TOKEN]\n      --healthz-api-build-info-path string                healthz API: Path on which the build and runtime info will be served (empty disables it) [env: HEALTHZ_API_BUILD_INFO_PATH] (default \"/buildinfo\")\n      --healthz-api-check-failure-threshold int           healthz API: Default number of consecutive failures before a readiness check reports failing [env: HEALTHZ_API_CHECK_FAILURE_THRESHOLD] (default 1)\n      --healthz-api-check-interval string                 healthz API: Interval at which readiness checks run in the background with probes serving their cached results (zero runs checks on every probe) [env: HEALTHZ_API_CHECK_INTERVAL] (default \"0s\")\n      --healthz-api-check-timeout string                  healthz API: Default timeout of readiness checks [env: HEALTHZ_API_CHECK_TIMEOUT] (default \"2s\")\n      --healthz-api-enable-admin                          healthz API: Serve the admin API to inspect and control the app at runtime [env: HEALTHZ_API_ENABLE_ADMIN]\n      --healthz-api-enable-build-info-metric              healthz API: Expose the <app>_build_info metric [env: HEALTHZ_API_ENABLE_BUILD_INFO_METRIC] (default true)\n      --healthz-api-enable-pprof                          healthz API: Serve pprof profiling endpoints on /debug/pprof/ [env: HEALTHZ_API_ENABLE_PPROF]\n      --healthz-api-graph-path string                     healthz API: Path on which the service dependency graph will be served as JSON or Graphviz DOT with ?format=dot (empty disables it) [env: HEALTHZ_API_GRAPH_PATH]\n      --healthz-api-liveness-path string                  healthz API: Path on which the liveness probe will be served [env: HEALTHZ_API_LIVENESS_PATH] (default \"/live\")\n      --healthz-api-metrics-path string                   healthz API: Path on which the metrics will be served [env: HEALTHZ_API_METRICS_PATH] (default \"/metrics\")\n      --healthz-api-readiness-path string                 healthz API: Path on which the readiness probe will be served [env: HEALTHZ_API_READINESS_PATH] (default \"/ready\")\n      --healthz-api-startup-path string                   healthz API: Path on which the startup probe will be served [env: HEALTHZ_API_STARTUP_PATH] (default \"/startup\")\n      --healthz-ep-addr string                            healthz entrypoint: TCP Address to listen on [env: HEALTHZ_EP_ADDR] (default \":8081\")\n      --healthz-ep-http-idle-timeout string               healthz entrypoint: Maximum duration to wait for the next request when keep-alives are enabled (zero uses the value of read timeout) [env: HEALTHZ_EP_HTTP_IDLE_TIMEOUT] (default \"30s\")\n      --healthz-ep-http-max-header-bytes int              healthz entrypoint: Maximum number of bytes the server will read parsing the request header's keys and values [env: HEALTHZ_EP_HTTP_MAX_HEADER_BYTES] (default 1048576)\n      --healthz-ep-http-read-header-timeout string        healthz entrypoint: Maximum duration for reading request headers (zero uses the value of read timeout) [env: HEALTHZ_EP_HTTP_READ_HEADER_TIMEOUT] (default \"30s\")\n      --healthz-ep-http-read-timeout string               healthz entrypoint: Maximum duration for reading the entire request including the body (zero means no timeout) [env: HEALTHZ_EP_HTTP_READ_TIMEOUT] (default \"30s\")\n      --healthz-ep-http-write-timeout string              healthz entrypoint: Maximum duration before timing out writes of the response (zero means no timeout) [env: HEALTHZ_EP_HTTP_WRITE_TIMEOUT] (default \"30s\")\n      --healthz-ep-net-keep-alive string                  healthz entrypoint: Keep alive period for network connections accepted by this entrypoint [env: HEALTHZ_EP_NET_KEEP_ALIVE] (default \"-1s\")\n      --healthz-ep-net-keep-alive-probe-count int         healthz entrypoint: Maximum number of keep-alive probes that can go unanswered before dropping a connection [env: HEALTHZ_EP_NET_KEEP_ALIVE_PROBE_COUNT] (default 9)\n      --healthz-ep-net-keep-alive-probe-enable            healthz entrypoint: Enable keep alive probes [env: HEALTHZ_EP_NET_KEEP_ALIVE_PROBE_ENABLE]\n      --healthz-ep-net-keep-alive-probe-idle string       healthz entrypoint: Time that the connection must be idle before the first keep-alive probe is sent [env: HEALTHZ_EP_NET_KEEP_ALIVE_PROBE_IDLE] (default \"15s\")\n      --healthz-ep-net-keep-alive-probe-interval string   healthz entrypoint: Time between keep-alive probes [env: HEALTHZ_EP_NET_KEEP_ALIVE_PROBE_INTERVAL] (default \"15s\")\n      --healthz-ep-tls-certfile string                    healthz entrypoint: Path to the certificate file [env: HEALTHZ_EP_TLS_CERT_FILE]\n      --healthz-ep-tls-keyfile string                     healthz entrypoint: Path to the key file [env: HEALTHZ_EP_TLS_KEY_FILE]\n      --log-enable-caller                                 Enable caller [env: LOG_ENABLE_CALLER]\n      --log-enable-stacktrace                             Enable automatic stacktrace capturing [env: LOG_ENABLE_STACKTRACE]\n      --log-encoding-caller-encoder string                Encoding: Primitive representation for the log caller (e.g. 'full' [env: LOG_ENCODING_CALLER_ENCODER] (default \"short\")\n      --log-encoding-caller-key string                    Encoding: Key for the log caller (if empty [env: LOG_ENCODING_CALLER_KEY] (default \"caller\")\n      --log-encoding-console-separator string             Encoding: Field separator used by the console encoder [env: LOG_ENCODING_CONSOLE_SEPARATOR] (default \"\\t\")\n      --log-encoding-duration-encoder string              Encoding: Primitive representation for the log duration (e.g. 'string' [env: LOG_ENCODING_DURATION_ENCODER] (default \"s\")\n      --log-encoding-function-key string                  Encoding: Key for the log function (if empty [env: LOG_ENCODING_FUNCTION_KEY]\n      --log-encoding-level-encoder string                 Encoding: Primitive representation for the log level (e.g. 'capital' [env: LOG_ENCODING_LEVEL_ENCODER] (default \"capitalColor\")\n      --log-encoding-level-key string                     Encoding: Key for the log level (if empty [env: LOG_ENCODING_LEVEL_KEY] (default \"level\")\n      --log-encoding-line-ending string                   Encoding: Line ending [env: LOG_ENCODING_LINE_ENDING] (default \"\\n\")\n      --log-encoding-message-key string                   Encoding: Key for the log message (if empty [env: LOG_ENCODING_MESSAGE_KEY] (default \"msg\")\n      --log-encoding-name-encoder string                  Encoding: Primitive representation for the log logger name (e.g. 'full' [env: LOG_ENCODING_NAME_ENCODER] (default \"full\")\n      --log-encoding-name-key string                      Encoding: Key for the log logger name (if empty [env: LOG_ENCODING_NAME_KEY] (default \"logger\")\n      --log-encoding-skip-line-ending                     Encoding: Skip the line ending [env: LOG_ENCODING_SKIP_LINE_ENDING]\n      --log-encoding-stacktrace-key string                Encoding: Key for the log stacktrace (if empty [env: LOG_ENCODING_STACKTRACE_KEY] (default \"stacktrace\")\n      --log-encoding-time-encoder string                  Encoding: Primitive representation for the log timestamp (e.g. 'rfc3339nano' [env: LOG_ENCODING_TIME_ENCODER] (default \"rfc3339\")\n      --log-encoding-time-key string                      Encoding: Key for the log timestamp (if empty [env: LOG_ENCODING_TIME_KEY] (default \"ts\")\n      --log-err-output strings                            List of URLs to write internal logger errors to [env: LOG_ERROR_OUTPUT_PATHS] (default [stderr])\n      --log-format string                                 Log format [env: LOG_FORMAT] (default \"text\")\n      --log-level string                                  Minimum enabled logging level [env: LOG_LEVEL] (default \"info\")\n      --log-output strings                                List of URLs or file paths to write logging output to [env: LOG_OUTPUT_PATHS] (default [stderr])\n      --log-sampling-initial int                          Sampling: Number of log entries with the same level and message to log before dropping entries [env: LOG_SAMPLING_INITIAL] (default 100)\n      --log-sampling-thereafter int                       Sampling: After the initial number of entries [env: LOG_SAMPLING_THEREAFTER] (default 100)\n      --main-ep-addr string                               main entrypoint: TCP Address to listen on [env: MAIN_EP_ADDR] (default \":8080\")\n      --main-ep-http-idle-timeout string                  main entrypoint: Maximum duration to wait for the next request when keep-alives are enabled (zero uses the value of read timeout) [env: MAIN_EP_HTTP_IDLE_TIMEOUT] (default \"30s\")\n      --main-ep-http-max-header-bytes int                 main entrypoint: Maximum number of bytes the server will read parsing the request header's keys and values [env: MAIN_EP_HTTP_MAX_HEADER_BYTES] (default 1048576)\n      --main-ep-http-read-header-timeout string           main entrypoint: Maximum duration for reading request headers (zero uses the value of read timeout) [env: MAIN_EP_HTTP_READ_HEADER_TIMEOUT] (default \"30s\")\n      --main-ep-http-read-timeout string                  main entrypoint: Maximum duration for reading the entire request including the body (zero means no timeout) [env: MAIN_EP_HTTP_READ_TIMEOUT] (default \"30s\")\n      --main-ep-http-write-timeout string                 main entrypoint: Maximum duration before timing out writes of the response (zero means no timeout) [env: MAIN_EP_HTTP_WRITE_TIMEOUT] (default \"30s\")\n      --main-ep-net-keep-alive string                     main entrypoint: Keep alive period for network connections accepted by this entrypoint [env: MAIN_EP_NET_KEEP_ALIVE] (default \"-1s\")\n      --main-ep-net-keep-alive-probe-count int            main entrypoint: Maximum number of keep-alive probes that can go unanswered before dropping a connection [env: MAIN_EP_NET_KEEP_ALIVE_PROBE_COUNT] (default 9)\n      --main-ep-net-keep-alive-probe-enable               main entrypoint: Enable keep alive probes [env: MAIN_EP_NET_KEEP_ALIVE_PROBE_ENABLE]\n      --main-ep-net-keep-alive-probe-idle string          main entrypoint: Time that the connection must be idle before the first keep-alive probe is sent [env: MAIN_EP_NET_KEEP_ALIVE_PROBE_IDLE] (default \"15s\")\n      --main-ep-net-keep-alive-probe-interval string      main entrypoint: Time between keep-alive probes [env: MAIN_EP_NET_KEEP_ALIVE_PROBE_INTERVAL] (default \"15s\")\n      --main-ep-tls-certfile string                       main entrypoint: Path to the certificate file [env: MAIN_EP_TLS_CERT_FILE]\n      --main-ep-tls-keyfile string                        main entrypoint: Path to the key file [env: MAIN_EP_TLS_KEY_FILE]\n      --name string                                       Application name [env: NAME]\n      --start-timeout string                              Start timeout [env: START_TIMEOUT] (default \"15s\")\n      --stop-timeout string                               Stop timeout [env: STOP_TIMEOUT] (default \"15s\")\n      --tags strings                                      Tags to attach to contexts (key=value pairs) [env: TAGS]\n      --tracing-endpoint string                           tracing: Collector host:port spans are sent to by the otlphttp exporter [env: TRACING_ENDPOINT] (default \"localhost:4318\")\n      --tracing-exporter string                           tracing: Span exporter (one of none|stdout|file|otlphttp) [env: TRACING_EXPORTER] (default \"none\")\n      --tracing-file string                               tracing: Path of the file spans are written to by the file exporter [env: TRACING_FILE] (default \"traces.json\")\n      --tracing-insecure                                  tracing: Disable TLS for the otlphttp exporter [env: TRACING_INSECURE]\n      --tracing-sample-ratio float                        tracing: Ratio of root spans sampled (between 0 and 1) [env: TRACING_SAMPLE_RATIO] (default 1)\n      --version string                                    Application version [env: VERSION]\n"
 	assert.Equal(t, expectedUsage, set.FlagUsages())
 
 	env, err := cfg.Env()
@@ -0,0 +1,125 @@
+package app
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/pprof"
+	"runtime"
+	"runtime/debug"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// BuildInfo holds build and runtime information about the running binary
+type BuildInfo struct {
+	Name      string    `json:"name"`
+	Version   string    `json:"version"`
+	GoVersion string    `json:"goVersion"`
+	Path      string    `json:"path,omitempty"`
+	Main      *Module   `json:"main,omitempty"`
+	Revision  string    `json:"revision,omitempty"`
+	Modules   []*Module `json:"modules,omitempty"`
+	Runtime   *Runtime  `json:"runtime"`
+}
+
+// Module is a Go module compiled into the binary
+type Module struct {
+	Path    string `json:"path"`
+	Version string `json:"version"`
+	Sum     string `json:"sum,omitempty"`
+	Replace string `json:"replace,omitempty"`
+}
+
+// Runtime holds information about the Go runtime
+type Runtime struct {
+	GOOS         string `json:"goos"`
+	GOARCH       string `json:"goarch"`
+	NumCPU       int    `json:"numCPU"`
+	GOMAXPROCS   int    `json:"gomaxprocs"`
+	NumGoroutine int    `json:"numGoroutine"`
+}
+
+// BuildInfo returns the build information of the binary (as reported by debug.ReadBuildInfo)
+// together with the app name and version and the current runtime information
+func (app *App) BuildInfo() *BuildInfo {
+	info := &BuildInfo{
+		Name:      app.name,
+		Version:   app.version,
+		GoVersion: runtime.Version(),
+		Runtime: &Runtime{
+			GOOS:         runtime.GOOS,
+			GOARCH:       runtime.GOARCH,
+			NumCPU:       runtime.NumCPU(),
+			GOMAXPROCS:   runtime.GOMAXPROCS(0),
+			NumGoroutine: runtime.NumGoroutine(),
+		},
+	}
+
+	bi, ok := debug.ReadBuildInfo()
+	if !ok {
+		return info
+	}
+
+	info.GoVersion = bi.GoVersion
+	info.Path = bi.Path
+	info.Main = newModule(&bi.Main)
+	for _, setting := range bi.Settings {
+		if setting.Key == "vcs.revision" {
+			info.Revision = setting.Value
+		}
+	}
+	for _, dep := range bi.Deps {
+		info.Modules = append(info.Modules, newModule(dep))
+	}
+
+	return info
+}
+
+func newModule(m *debug.Module) *Module {
+	mod := &Module{
+		Path:    m.Path,
+		Version: m.Version,
+		Sum:     m.Sum,
+	}
+	if m.Replace != nil {
+		mod.Replace = m.Replace.Path
+		if m.Replace.Version != "" {
+			mod.Replace += "@" + m.Replace.Version
+		}
+	}
+	return mod
+}
+
+func (app *App) buildInfoHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(app.BuildInfo())
+	})
+}
+
+// registerBuildInfoMetric registers the <app>_build_info gauge
+// which always has value 1 and exposes the build information as labels
+func (app *App) registerBuildInfoMetric() {
+	info := app.BuildInfo()
+	gauge := prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: sanitizeMetricName(app.name),
+		Name:      "build_info",
+		Help:      "A metric with a constant '1' value labeled by the app version, the Go version and the VCS revision from which the app was built",
+		ConstLabels: prometheus.Labels{
+			"version":    app.version,
+			"go_version": info.GoVersion,
+			"revision":   info.Revision,
+		},
+	})
+	gauge.Set(1)
+	app.prometheus.MustRegister(gauge)
+}
+
+// setPprofHandlers serves the net/http/pprof endpoints under /debug/pprof/
+func (app *App) setPprofHandlers() {
+	app.healthzRouter.Path("/debug/pprof/cmdline").HandlerFunc(pprof.Cmdline)
+	app.healthzRouter.Path("/debug/pprof/profile").HandlerFunc(pprof.Profile)
+	app.healthzRouter.Path("/debug/pprof/symbol").HandlerFunc(pprof.Symbol)
+	app.healthzRouter.Path("/debug/pprof/trace").HandlerFunc(pprof.Trace)
+	app.healthzRouter.PathPrefix("/debug/pprof/").HandlerFunc(pprof.Index)
+}
@@ -0,0 +1,79 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"runtime"
+	"testing"
+
+	"github.com/nmvalera/go-utils/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDebugEndpoints(t *testing.T) {
+	app := newTestApp(t)
+	app.cfg.HealthzServer.BuildInfoPath = common.Ptr("/buildinfo")
+	app.cfg.HealthzServer.EnablePprof = common.Ptr(true)
+	// The metric is registered by NewApp when EnableBuildInfoMetric is set
+	app.registerBuildInfoMetric()
+
+	app.EnableHealthzEntrypoint()
+	require.NoError(t, app.Start(context.Background()))
+	defer func() { require.NoError(t, app.Stop(context.Background())) }()
+
+	baseURL := "http://" + app.healthz.Addr()
+
+	// Build info
+	resp, err := http.Get(baseURL + "/buildinfo")
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var info BuildInfo
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&info))
+	assert.Equal(t, "test", info.Name)
+	assert.Equal(t, "1.0.0", info.Version)
+	assert.Equal(t, runtime.Version(), info.GoVersion)
+	require.NotNil(t, info.Runtime)
+	assert.Equal(t, runtime.GOOS, info.Runtime.GOOS)
+	assert.Positive(t, info.Runtime.NumGoroutine)
+
+	// pprof
+	resp, err = http.Get(baseURL + "/debug/pprof/")
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	resp, err = http.Get(baseURL + "/debug/pprof/goroutine?debug=1")
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	// Build info metric
+	resp, err = http.Get(baseURL + "/metrics")
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Contains(t, string(body), "# TYPE test_build_info gauge")
+	assert.Regexp(t, `test_build_info\{go_version="go[^"]*",revision="[^"]*",version="1.0.0"\} 1`, string(body))
+}
+
+func TestDebugEndpointsDisabled(t *testing.T) {
+	app := newTestApp(t)
+	app.cfg.HealthzServer.BuildInfoPath = common.Ptr("")
+
+	app.EnableHealthzEntrypoint()
+	require.NoError(t, app.Start(context.Background()))
+	defer func() { require.NoError(t, app.Stop(context.Background())) }()
+
+	for _, path := range []string{"/buildinfo", "/debug/pprof/"} {
+		resp, err := http.Get("http://" + app.healthz.Addr() + path)
+		require.NoError(t, err)
+		_ = resp.Body.Close()
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode, path)
+	}
+}
@@ -0,0 +1,81 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/hellofresh/health-go/v5"
+	"github.com/nmvalera/go-utils/app/svc"
+	"github.com/nmvalera/go-utils/common"
+	"github.com/nmvalera/go-utils/log"
+	"go.uber.org/zap"
+)
+
+var errShuttingDown = errors.New("app is shutting down")
+
+// registerShutdownCheck registers a readiness check failing once the app is shutting down
+func (app *App) registerShutdownCheck() error {
+	// The check is cheap and must reflect the shutdown immediately, so it is never cached
+	return app.registerReadyCheck(health.Config{
+		Name: "system.shutdown",
+		Check: func(_ context.Context) error {
+			if app.draining.Load() {
+				return errShuttingDown
+			}
+			return nil
+		},
+	}, &checkOptions{interval: common.Ptr(time.Duration(0))}, nil)
+}
+
+// drain prepares the app for stopping:
+//  1. it marks the app as not ready, so orchestrators stop routing new traffic to it
+//  2. it waits for the drain delay, so the readiness change propagates
+//  3. it drains all Running services implementing svc.Drainable (dependents first),
+//     e.g. traffic entrypoints (main, gRPC...) stop accepting new connections and complete in-flight requests,
+//     while the healthz entrypoint is not drained so probes and metrics keep being served until services are stopped
+//
+// Drain errors are logged, they do not prevent the app from stopping
+func (app *App) drain(ctx context.Context) {
+	logger := log.LoggerFromContext(ctx)
+	app.draining.Store(true)
+
+	if delay := common.Val(app.cfg.DrainDelay); delay > 0 {
+		logger.Info("System not ready, waiting before draining...", zap.Duration("delay", delay))
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return
+		}
+	}
+
+	if app.cfg.DrainTimeout != nil {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, *app.cfg.DrainTimeout)
+		defer cancel()
+	}
+
+	services := app.servicesInDependencyOrder()
+	for i := len(services) - 1; i >= 0; i-- {
+		if app.healthz != nil && services[i].value == app.healthz {
+			continue
+		}
+		services[i].drain(ctx)
+	}
+}
+
+func (s *service) drain(ctx context.Context) {
+	d, ok := s.value.(svc.Drainable)
+	if !ok || s.Status() != Running {
+		return
+	}
+
+	ctx = s.context(ctx)
+	logger := log.LoggerFromContext(ctx)
+	logger.Info("Service draining...")
+	if err := d.Drain(ctx); err != nil {
+		logger.Error("Service failed to drain", zap.Error(err))
+		return
+	}
+	logger.Info("Service successfully drained")
+}
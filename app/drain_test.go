@@ -0,0 +1,111 @@
+package app
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/nmvalera/go-utils/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type drainableService struct {
+	mu     sync.Mutex
+	events *[]string
+	name   string
+}
+
+func (s *drainableService) record(event string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	*s.events = append(*s.events, s.name+"."+event)
+}
+
+func (s *drainableService) Start(_ context.Context) error { return nil }
+func (s *drainableService) Stop(_ context.Context) error {
+	s.record("stop")
+	return nil
+}
+func (s *drainableService) Drain(_ context.Context) error {
+	s.record("drain")
+	return nil
+}
+
+func TestDrain(t *testing.T) {
+	app := newTestApp(t)
+	app.cfg.DrainDelay = common.Ptr(200 * time.Millisecond)
+	app.cfg.DrainTimeout = common.Ptr(time.Second)
+
+	var events []string
+	Provide(app, "main", func() (*drainableService, error) {
+		app.EnableMainEntrypoint()
+		app.EnableHealthzEntrypoint()
+		Provide(app, "dep", func() (*drainableService, error) {
+			return &drainableService{events: &events, name: "dep"}, nil
+		})
+		return &drainableService{events: &events, name: "main"}, nil
+	})
+
+	require.NoError(t, app.Start(context.Background()))
+	healthzAddr, mainAddr := app.healthz.Addr(), app.main.Addr()
+
+	resp, err := http.Get("http://" + healthzAddr + "/ready")
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	stopped := make(chan error)
+	go func() {
+		stopped <- app.Stop(context.Background())
+	}()
+
+	// During the drain delay, the app is not ready but keeps serving
+	time.Sleep(50 * time.Millisecond)
+	client := &http.Client{Transport: &http.Transport{DisableKeepAlives: true}}
+	resp, err = client.Get("http://" + healthzAddr + "/ready")
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+
+	resp, err = client.Get("http://" + healthzAddr + "/live")
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	conn, err := net.Dial("tcp", mainAddr)
+	require.NoError(t, err)
+	_ = conn.Close()
+
+	require.NoError(t, <-stopped)
+
+	// Services are drained (dependents first) before being stopped
+	assert.Equal(t, []string{"main.drain", "dep.drain", "main.stop", "dep.stop"}, events)
+}
+
+func TestDrainSkipsHealthz(t *testing.T) {
+	app := newTestApp(t)
+
+	Provide(app, "main", func() (*drainableService, error) {
+		app.EnableMainEntrypoint()
+		app.EnableHealthzEntrypoint()
+		return &drainableService{events: new([]string), name: "main"}, nil
+	})
+
+	require.NoError(t, app.Start(context.Background()))
+	defer func() { require.NoError(t, app.Stop(context.Background())) }()
+
+	app.drain(context.Background())
+
+	// The main entrypoint is drained while the healthz entrypoint keeps serving probes and metrics
+	_, err := net.Dial("tcp", app.main.Addr())
+	require.Error(t, err)
+
+	resp, err := http.Get("http://" + app.healthz.Addr() + "/metrics")
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
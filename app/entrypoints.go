@@ -0,0 +1,91 @@
+package app
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/gorilla/mux"
+	"github.com/justinas/alice"
+	kkrthttp "github.com/nmvalera/go-utils/net/http"
+)
+
+// MainEntrypoint is the name of the main HTTP entrypoint (see EnableMainEntrypoint)
+const MainEntrypoint = "main"
+
+// reservedEntrypoints are names of entrypoints that are not named HTTP entrypoints
+var reservedEntrypoints = map[string]bool{
+	"healthz": true,
+	"grpc":    true,
+}
+
+// httpEntrypoint is a named HTTP entrypoint with its router and middleware chain
+type httpEntrypoint struct {
+	// ep is nil until the entrypoint is enabled
+	ep         *kkrthttp.Entrypoint
+	router     *mux.Router
+	middleware alice.Chain
+}
+
+// httpEntrypoint returns the named HTTP entrypoint, creating its router and middleware chain if needed
+// so services can register on it before it is enabled
+func (app *App) httpEntrypoint(name string) *httpEntrypoint {
+	ep, ok := app.httpEntrypoints[name]
+	if !ok {
+		ep = &httpEntrypoint{
+			router:     mux.NewRouter(),
+			middleware: alice.New(),
+		}
+		app.httpEntrypoints[name] = ep
+	}
+	return ep
+}
+
+// EnableEntrypoint enables a named HTTP entrypoint with its own router and middleware chain (e.g. "public", "admin")
+//
+// Services provided WithEntrypoint(name) register their routes (svc.API) and middleware (svc.Middleware) on it.
+// The config is typically a section of the application config (e.g. `AdminEp *kkrthttp.EntrypointConfig`).
+//
+// It panics if the name is empty or reserved ("healthz" and "grpc").
+func (app *App) EnableEntrypoint(name string, cfg *kkrthttp.EntrypointConfig) *kkrthttp.Entrypoint {
+	if name == "" || reservedEntrypoints[name] {
+		panic(fmt.Sprintf("invalid entrypoint name: %q", name))
+	}
+
+	ep := app.entrypoint(name, cfg)
+	app.httpEntrypoint(name).ep = ep
+
+	return ep
+}
+
+// Entrypoint returns the named HTTP entrypoint, or nil if it has not been enabled
+func (app *App) Entrypoint(name string) *kkrthttp.Entrypoint {
+	if ep, ok := app.httpEntrypoints[name]; ok {
+		return ep.ep
+	}
+	return nil
+}
+
+// setEntrypointHandlers sets the instrumented middleware chain and router as handler of every enabled HTTP entrypoint
+// It returns an error if services registered on a named entrypoint which has not been enabled
+func (app *App) setEntrypointHandlers() error {
+	names := make([]string, 0, len(app.httpEntrypoints))
+	for name := range app.httpEntrypoints {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		ep := app.httpEntrypoints[name]
+		if ep.ep == nil {
+			// services may register routes on main without serving them (e.g. in tests)
+			if name == MainEntrypoint {
+				continue
+			}
+			return fmt.Errorf("entrypoint %q has services registered but is not enabled (see EnableEntrypoint)", name)
+		}
+
+		ep.ep.SetHandler(app.instrumentMiddleware().Extend(ep.middleware).Then(ep.router))
+	}
+
+	return nil
+}
@@ -0,0 +1,97 @@
+package app
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/justinas/alice"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// routeService registers a route on its entrypoint and a middleware setting a header
+type routeService struct {
+	path   string
+	header string
+}
+
+func (s *routeService) RegisterHandler(router *mux.Router) {
+	router.Path(s.path).Methods(http.MethodGet).HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+}
+
+func (s *routeService) RegisterMiddleware(chain alice.Chain) alice.Chain {
+	return chain.Append(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("X-Entrypoint", s.header)
+			next.ServeHTTP(w, r)
+		})
+	})
+}
+
+func TestNamedEntrypoints(t *testing.T) {
+	app := newTestApp(t)
+
+	app.Provide("public-api", func() (any, error) {
+		app.EnableMainEntrypoint()
+		return &routeService{path: "/users", header: "main"}, nil
+	})
+	app.Provide("admin-api", func() (any, error) {
+		app.EnableEntrypoint("admin", app.cfg.MainEntrypoint)
+		return &routeService{path: "/settings", header: "admin"}, nil
+	}, WithEntrypoint("admin"))
+	app.Provide("top", func() (any, error) {
+		app.Provide("public-api", func() (any, error) { return nil, nil })
+		app.Provide("admin-api", func() (any, error) { return nil, nil })
+		return nil, nil
+	})
+
+	require.NoError(t, app.Start(context.Background()))
+	defer func() { require.NoError(t, app.Stop(context.Background())) }()
+
+	require.Same(t, app.main, app.Entrypoint(MainEntrypoint))
+	admin := app.Entrypoint("admin")
+	require.NotNil(t, admin)
+	require.NotEqual(t, app.main.Addr(), admin.Addr())
+
+	for _, test := range []struct {
+		addr   string
+		path   string
+		status int
+		header string
+	}{
+		{addr: app.main.Addr(), path: "/users", status: http.StatusOK, header: "main"},
+		{addr: app.main.Addr(), path: "/settings", status: http.StatusNotFound, header: "main"},
+		{addr: admin.Addr(), path: "/settings", status: http.StatusOK, header: "admin"},
+		{addr: admin.Addr(), path: "/users", status: http.StatusNotFound, header: "admin"},
+	} {
+		resp, err := http.Get("http://" + test.addr + test.path)
+		require.NoError(t, err)
+		_ = resp.Body.Close()
+		assert.Equal(t, test.status, resp.StatusCode, test.path)
+		assert.Equal(t, test.header, resp.Header.Get("X-Entrypoint"), test.path)
+	}
+}
+
+func TestNamedEntrypointNotEnabled(t *testing.T) {
+	app := newTestApp(t)
+
+	app.Provide("admin-api", func() (any, error) {
+		app.EnableMainEntrypoint()
+		return &routeService{path: "/settings"}, nil
+	}, WithEntrypoint("admin"))
+
+	err := app.Start(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `entrypoint "admin"`)
+}
+
+func TestEnableEntrypointReservedName(t *testing.T) {
+	app := newTestApp(t)
+	for _, name := range []string{"", "healthz", "grpc"} {
+		assert.Panics(t, func() { app.EnableEntrypoint(name, app.cfg.MainEntrypoint) }, name)
+	}
+}
@@ -0,0 +1,149 @@
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Graph is a snapshot of the dependency graph of the services of an App
+type Graph struct {
+	Name     string         `json:"name"`
+	Version  string         `json:"version"`
+	Services []*ServiceInfo `json:"services"`
+}
+
+// ServiceInfo is a snapshot of a service in the dependency graph
+type ServiceInfo struct {
+	ID            string        `json:"id"`
+	Component     string        `json:"component"`
+	Status        ServiceStatus `json:"status"`
+	Error         string        `json:"error,omitempty"`
+	StartDuration time.Duration `json:"-"`
+	StopDuration  time.Duration `json:"-"`
+
+	// Dependencies are the ids of the services this service depends on
+	Dependencies []string `json:"dependencies"`
+}
+
+func (info *ServiceInfo) MarshalJSON() ([]byte, error) {
+	type Alias ServiceInfo
+	return json.Marshal(&struct {
+		*Alias
+		StartDuration string `json:"startDuration,omitempty"`
+		StopDuration  string `json:"stopDuration,omitempty"`
+	}{
+		Alias:         (*Alias)(info),
+		StartDuration: formatDuration(info.StartDuration),
+		StopDuration:  formatDuration(info.StopDuration),
+	})
+}
+
+func formatDuration(d time.Duration) string {
+	if d == 0 {
+		return ""
+	}
+	return d.String()
+}
+
+// Graph returns a snapshot of the dependency graph of the app services
+// Services are sorted by id
+func (app *App) Graph() *Graph {
+	g := &Graph{
+		Name:     app.name,
+		Version:  app.version,
+		Services: make([]*ServiceInfo, 0, len(app.services)),
+	}
+	for _, s := range app.services {
+		g.Services = append(g.Services, s.info())
+	}
+	sort.Slice(g.Services, func(i, j int) bool { return g.Services[i].ID < g.Services[j].ID })
+
+	return g
+}
+
+func (s *service) info() *ServiceInfo {
+	s.mux.RLock()
+	defer s.mux.RUnlock()
+
+	info := &ServiceInfo{
+		ID:            s.id,
+		Component:     s.name,
+		Status:        s.Status(),
+		StartDuration: s.startDuration,
+		StopDuration:  s.stopDuration,
+		Dependencies:  make([]string, 0, len(s.deps)),
+	}
+	if err := s.runError(); err != nil {
+		info.Error = err.Error()
+	}
+	for id := range s.deps {
+		info.Dependencies = append(info.Dependencies, id)
+	}
+	sort.Strings(info.Dependencies)
+
+	return info
+}
+
+// WriteDOT writes the graph in Graphviz DOT format
+// Edges go from a service to the services it depends on
+func (g *Graph) WriteDOT(w io.Writer) error {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "digraph %s {\n", dotQuote(g.Name))
+	b.WriteString("  node [shape=box, style=filled];\n")
+	for _, s := range g.Services {
+		label := fmt.Sprintf("%s\n%s", s.ID, s.Status)
+		if s.Component != s.ID {
+			label = fmt.Sprintf("%s\n(%s)\n%s", s.ID, s.Component, s.Status)
+		}
+		fmt.Fprintf(&b, "  %s [label=%s, fillcolor=%q];\n", dotQuote(s.ID), dotQuote(label), s.Status.color())
+	}
+	for _, s := range g.Services {
+		for _, dep := range s.Dependencies {
+			fmt.Fprintf(&b, "  %s -> %s;\n", dotQuote(s.ID), dotQuote(dep))
+		}
+	}
+	b.WriteString("}\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// dotQuote quotes s as a DOT string, escaping quotes and backslashes and turning line breaks into DOT line breaks
+func dotQuote(s string) string {
+	s = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s)
+	return `"` + s + `"`
+}
+
+func (s ServiceStatus) color() string {
+	switch s {
+	case Running:
+		return "palegreen"
+	case Error:
+		return "lightcoral"
+	case Starting, Stopping:
+		return "khaki"
+	default:
+		return "lightgrey"
+	}
+}
+
+// graphHandler serves the dependency graph as JSON or, if the format query parameter is "dot", as Graphviz DOT
+func (app *App) graphHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		g := app.Graph()
+		if r.URL.Query().Get("format") == "dot" {
+			w.Header().Set("Content-Type", "text/vnd.graphviz; charset=utf-8")
+			_ = g.WriteDOT(w)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(g)
+	})
+}
@@ -0,0 +1,115 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/nmvalera/go-utils/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServiceStatusString(t *testing.T) {
+	assert.Equal(t, "Running", Running.String())
+	assert.Equal(t, "Error", Error.String())
+	assert.Equal(t, "ServiceStatus(42)", ServiceStatus(42).String())
+
+	b, err := json.Marshal(Stopped)
+	require.NoError(t, err)
+	assert.Equal(t, `"Stopped"`, string(b))
+}
+
+func TestGraph(t *testing.T) {
+	app := newTestApp(t)
+	app.cfg.HealthzServer.GraphPath = common.Ptr("/graph")
+
+	Provide(app, "main", func() (*fallibleService, error) {
+		app.EnableHealthzEntrypoint()
+		Provide(app, "dep", func() (string, error) {
+			return "dep", nil
+		}, WithComponentName("dep-component"))
+		return new(fallibleService), nil
+	})
+
+	g := app.Graph()
+	require.Len(t, g.Services, 3)
+	assert.Equal(t, "dep", g.Services[0].ID)
+	assert.Equal(t, "dep-component", g.Services[0].Component)
+	assert.Equal(t, Constructed, g.Services[0].Status)
+	assert.Equal(t, "main", g.Services[1].ID)
+	assert.Equal(t, []string{"dep", "system.healthz.entrypoint"}, g.Services[1].Dependencies)
+	assert.Equal(t, "system.healthz.entrypoint", g.Services[2].ID)
+
+	require.NoError(t, app.Start(context.Background()))
+	defer func() { require.NoError(t, app.Stop(context.Background())) }()
+
+	// JSON output
+	resp, err := http.Get("http://" + app.healthz.Addr() + "/graph")
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var body map[string]any
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.Equal(t, "test", body["name"])
+	services := body["services"].([]any)
+	require.Len(t, services, 3)
+	mainInfo := services[1].(map[string]any)
+	assert.Equal(t, "main", mainInfo["id"])
+	assert.Equal(t, "Running", mainInfo["status"])
+	assert.NotEmpty(t, mainInfo["startDuration"])
+	assert.NotContains(t, mainInfo, "stopDuration")
+	assert.NotContains(t, mainInfo, "error")
+
+	// DOT output
+	resp, err = http.Get("http://" + app.healthz.Addr() + "/graph?format=dot")
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+	dot, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Contains(t, string(dot), `digraph "test" {`)
+	assert.Contains(t, string(dot), `"dep" [label="dep\n(dep-component)\nRunning", fillcolor="palegreen"];`)
+	assert.Contains(t, string(dot), `"main" -> "dep";`)
+	assert.Contains(t, string(dot), `"main" -> "system.healthz.entrypoint";`)
+}
+
+func TestWriteDOTEscaping(t *testing.T) {
+	g := &Graph{
+		Name: `my "app"`,
+		Services: []*ServiceInfo{
+			{ID: `svc"1`, Component: `comp\1`, Status: Running, Dependencies: []string{"dep"}},
+		},
+	}
+
+	var b strings.Builder
+	require.NoError(t, g.WriteDOT(&b))
+	assert.Contains(t, b.String(), `digraph "my \"app\"" {`)
+	assert.Contains(t, b.String(), `"svc\"1" [label="svc\"1\n(comp\\1)\nRunning", fillcolor="palegreen"];`)
+	assert.Contains(t, b.String(), `"svc\"1" -> "dep";`)
+}
+
+func TestGraphError(t *testing.T) {
+	app := newTestApp(t)
+
+	Provide(app, "main", func() (string, error) {
+		Provide(app, "dep", func() (string, error) {
+			return "", errors.New("constructor error")
+		})
+		return "main", nil
+	})
+
+	g := app.Graph()
+	require.Len(t, g.Services, 2)
+	assert.Equal(t, Error, g.Services[0].Status)
+	assert.Equal(t, `service "dep": constructor error`, g.Services[0].Error)
+
+	var buf bytes.Buffer
+	require.NoError(t, g.WriteDOT(&buf))
+	assert.Contains(t, buf.String(), `"dep" [label="dep\nError", fillcolor="lightcoral"];`)
+}
@@ -0,0 +1,65 @@
+package app
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hellofresh/health-go/v5"
+	kkrtgrpc "github.com/nmvalera/go-utils/net/grpc"
+	"google.golang.org/grpc"
+)
+
+// EnableGRPCEntrypoint enables the gRPC entrypoint of the app, configured with GRPCEntrypoint in Config
+//
+// Services implementing svc.GRPCAPI are registered on it, together with the standard gRPC health service
+// (grpc.health.v1.Health) reporting the readiness of the app.
+func (app *App) EnableGRPCEntrypoint() {
+	app.grpc = provide(app, "system.grpc.entrypoint", func() (*kkrtgrpc.Entrypoint, error) {
+		return app.cfg.GRPCEntrypoint.Entrypoint()
+	})
+
+	if app.grpcMetrics == nil {
+		app.grpcMetrics = kkrtgrpc.NewServerMetrics()
+		app.grpcMetrics.SetMetrics(sanitizeMetricName(app.name), "")
+		app.prometheus.MustRegister(app.grpcMetrics)
+	}
+}
+
+func (app *App) GRPCEntrypoint() *kkrtgrpc.Entrypoint {
+	return app.grpc
+}
+
+// setGRPCHandler sets the interceptors and registers the gRPC services on the gRPC entrypoint
+func (app *App) setGRPCHandler() {
+	if app.grpc == nil {
+		return
+	}
+
+	app.grpc.AddServerOptions(
+		grpc.ChainUnaryInterceptor(
+			kkrtgrpc.ContextUnaryServerInterceptor(app.grpc.Context),
+			kkrtgrpc.LoggingUnaryServerInterceptor(),
+			app.grpcMetrics.UnaryServerInterceptor(),
+		),
+		grpc.ChainStreamInterceptor(
+			kkrtgrpc.ContextStreamServerInterceptor(app.grpc.Context),
+			kkrtgrpc.LoggingStreamServerInterceptor(),
+			app.grpcMetrics.StreamServerInterceptor(),
+		),
+	)
+
+	healthServer := kkrtgrpc.NewHealthServer(app.readinessCheck)
+	healthServer.AddServices(app.grpcServices.ServiceNames()...)
+	healthServer.Register(app.grpc)
+
+	app.grpcServices.RegisterTo(app.grpc)
+}
+
+// readinessCheck returns an error if any critical readiness check of the app fails
+// (a degraded app is still serving, as on the readiness probe)
+func (app *App) readinessCheck(ctx context.Context) error {
+	if check := app.readyHealth.Measure(ctx); check.Status == health.StatusUnavailable {
+		return fmt.Errorf("app not ready: %v", check.Failures)
+	}
+	return nil
+}
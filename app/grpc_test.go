@@ -0,0 +1,73 @@
+package app
+
+import (
+	"context"
+	"testing"
+
+	kkrtgrpc "github.com/nmvalera/go-utils/net/grpc"
+	kkrthttp "github.com/nmvalera/go-utils/net/http"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// testGRPCService is a svc.GRPCAPI exposing a "test.Echo" service with a single unary method Ping
+type testGRPCService struct{}
+
+func (s *testGRPCService) RegisterGRPCService(registrar grpc.ServiceRegistrar) {
+	registrar.RegisterService(&grpc.ServiceDesc{
+		ServiceName: "test.Echo",
+		HandlerType: (*any)(nil),
+		Methods: []grpc.MethodDesc{{
+			MethodName: "Ping",
+			Handler: func(_ any, ctx context.Context, dec func(any) error, interceptor grpc.UnaryServerInterceptor) (any, error) {
+				in := new(emptypb.Empty)
+				if err := dec(in); err != nil {
+					return nil, err
+				}
+				return interceptor(ctx, in, &grpc.UnaryServerInfo{FullMethod: "/test.Echo/Ping"}, func(context.Context, any) (any, error) {
+					return &emptypb.Empty{}, nil
+				})
+			},
+		}},
+	}, s)
+}
+
+func TestGRPCEntrypoint(t *testing.T) {
+	ctx := context.Background()
+
+	app := newTestApp(t)
+	app.cfg.GRPCEntrypoint = &kkrtgrpc.EntrypointConfig{
+		GRPC: &kkrtgrpc.ServerConfig{},
+		Net:  &kkrthttp.ListenConfig{},
+	}
+
+	app.Provide("grpc-api", func() (any, error) {
+		app.EnableGRPCEntrypoint()
+		return &testGRPCService{}, nil
+	})
+	require.NoError(t, app.Start(ctx))
+	defer func() { require.NoError(t, app.Stop(ctx)) }()
+
+	conn, err := grpc.NewClient(app.GRPCEntrypoint().Addr(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	defer func() { _ = conn.Close() }()
+
+	require.NoError(t, conn.Invoke(ctx, "/test.Echo/Ping", &emptypb.Empty{}, new(emptypb.Empty)))
+
+	// Health service reports the readiness of the app
+	client := healthpb.NewHealthClient(conn)
+	for _, service := range []string{"", "test.Echo"} {
+		resp, err := client.Check(ctx, &healthpb.HealthCheckRequest{Service: service})
+		require.NoError(t, err)
+		assert.Equal(t, healthpb.HealthCheckResponse_SERVING, resp.Status, service)
+	}
+
+	metrics := gatherMetrics(t, app)
+	require.Contains(t, metrics, "test_grpc_server_handled_total")
+	// Ping and health Check
+	assert.Len(t, metrics["test_grpc_server_handled_total"].GetMetric(), 2)
+}
@@ -0,0 +1,325 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/hellofresh/health-go/v5"
+	"github.com/nmvalera/go-utils/common"
+)
+
+// checkTimeoutMargin is added to the timeout of readiness checks when registered on health-go,
+// so the timeout of the check itself (which records the failure) expires first
+const checkTimeoutMargin = time.Second
+
+var errCheckTimeout = errors.New(string(health.StatusTimeout))
+
+// Readiness statuses of the detailed readiness output
+const (
+	HealthStatusOK          = "ok"
+	HealthStatusDegraded    = "degraded"
+	HealthStatusUnavailable = "unavailable"
+	HealthStatusFailing     = "failing"
+)
+
+// healthCheck is a readiness check
+//
+// It reports a failure only after failureThreshold consecutive failures (unless it has not succeeded yet).
+// If interval is set, the check runs periodically in the background and probes return its cached result.
+//
+// gate (if set) is evaluated on every probe, before the cached result is used (e.g. to check the service is Running).
+type healthCheck struct {
+	name             string
+	check            health.CheckFunc
+	gate             func() error
+	timeout          time.Duration
+	interval         time.Duration
+	failureThreshold int
+	critical         bool
+
+	// onRecord (if set) is called every time a result is recorded
+	onRecord func()
+
+	mux                 sync.Mutex
+	hasResult           bool
+	healthy             bool
+	reported            error
+	consecutiveFailures int
+	lastCheck           time.Time
+	lastDuration        time.Duration
+}
+
+// CheckResult is the result of a readiness check in the detailed readiness output
+type CheckResult struct {
+	Status              string    `json:"status"`
+	Critical            bool      `json:"critical"`
+	Error               string    `json:"error,omitempty"`
+	ConsecutiveFailures int       `json:"consecutiveFailures,omitempty"`
+	LastCheck           time.Time `json:"lastCheck,omitzero"`
+	Duration            string    `json:"duration,omitempty"`
+}
+
+// HealthReport is the detailed readiness output
+//
+// Status is "unavailable" if a critical check fails, "degraded" if only non-critical checks fail and "ok" otherwise.
+type HealthReport struct {
+	Status    string                  `json:"status"`
+	Timestamp time.Time               `json:"timestamp"`
+	Checks    map[string]*CheckResult `json:"checks"`
+}
+
+// Check is the check function registered on health-go
+func (c *healthCheck) Check(ctx context.Context) error {
+	if c.gate != nil {
+		if err := c.gate(); err != nil {
+			c.reset()
+			return err
+		}
+	}
+
+	if c.interval > 0 {
+		c.mux.Lock()
+		if c.hasResult {
+			defer c.mux.Unlock()
+			return c.reported
+		}
+		c.mux.Unlock()
+	}
+
+	// No result is cached yet (e.g. the service just started) so we run the check synchronously
+	return c.run(ctx)
+}
+
+// run runs the check and records its result
+func (c *healthCheck) run(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	start := time.Now()
+	resCh := make(chan error, 1)
+	go func() {
+		resCh <- c.check(ctx)
+	}()
+
+	var err error
+	select {
+	case err = <-resCh:
+	case <-ctx.Done():
+		err = errCheckTimeout
+	}
+
+	return c.record(start, err)
+}
+
+func (c *healthCheck) record(start time.Time, err error) error {
+	if c.onRecord != nil {
+		defer c.onRecord()
+	}
+
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	c.hasResult = true
+	c.lastCheck = start
+	c.lastDuration = time.Since(start)
+
+	if err == nil {
+		c.healthy = true
+		c.consecutiveFailures = 0
+		c.reported = nil
+		return nil
+	}
+
+	c.consecutiveFailures++
+	if !c.healthy || c.consecutiveFailures >= c.failureThreshold {
+		c.healthy = false
+		c.reported = err
+	}
+
+	return c.reported
+}
+
+// reset drops the recorded results, so the next probe runs the check synchronously
+func (c *healthCheck) reset() {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	c.hasResult = false
+	c.healthy = false
+	c.reported = nil
+	c.consecutiveFailures = 0
+}
+
+// passing returns true if the last recorded result of the check is successful and its gate passes
+func (c *healthCheck) passing() bool {
+	if c.gate != nil && c.gate() != nil {
+		return false
+	}
+
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	return c.hasResult && c.reported == nil
+}
+
+// loop runs the check every interval until ctx is done
+func (c *healthCheck) loop(ctx context.Context) {
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if c.gate != nil && c.gate() != nil {
+				c.reset()
+				continue
+			}
+			_ = c.run(ctx)
+		}
+	}
+}
+
+func (c *healthCheck) result(failure string, failed bool) *CheckResult {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	res := &CheckResult{
+		Status:              HealthStatusOK,
+		Critical:            c.critical,
+		ConsecutiveFailures: c.consecutiveFailures,
+	}
+	if failed {
+		res.Status = HealthStatusFailing
+		res.Error = failure
+	}
+	if c.hasResult {
+		res.LastCheck = c.lastCheck
+		res.Duration = c.lastDuration.String()
+	}
+	return res
+}
+
+// checkOptions are the options of a readiness check, zero values default to HealthzServer in Config
+type checkOptions struct {
+	interval         *time.Duration
+	failureThreshold *int
+}
+
+// registerReadyCheck registers a readiness check
+func (app *App) registerReadyCheck(cfg health.Config, opts *checkOptions, gate func() error) error {
+	c := &healthCheck{
+		name:             cfg.Name,
+		check:            cfg.Check,
+		gate:             gate,
+		timeout:          cfg.Timeout,
+		interval:         common.Val(app.cfg.HealthzServer.CheckInterval),
+		failureThreshold: max(common.Val(app.cfg.HealthzServer.CheckFailureThreshold), 1),
+		critical:         !cfg.SkipOnErr,
+		onRecord:         app.notifyReady,
+	}
+	if c.timeout == 0 {
+		c.timeout = common.Val(app.cfg.HealthzServer.CheckTimeout)
+	}
+	if c.timeout <= 0 {
+		c.timeout = 2 * time.Second
+	}
+	if opts != nil && opts.interval != nil {
+		c.interval = *opts.interval
+	}
+	if opts != nil && opts.failureThreshold != nil {
+		c.failureThreshold = max(*opts.failureThreshold, 1)
+	}
+
+	cfg.Check = c.Check
+	cfg.Timeout = c.timeout + checkTimeoutMargin
+	if err := app.readyHealth.Register(cfg); err != nil {
+		return err
+	}
+
+	app.readyChecksMux.Lock()
+	app.readyChecks = append(app.readyChecks, c)
+	app.readyChecksMux.Unlock()
+
+	return nil
+}
+
+// runReadyChecks runs the readiness checks with an interval in the background until ctx is done
+func (app *App) runReadyChecks(ctx context.Context) {
+	app.readyChecksMux.RLock()
+	defer app.readyChecksMux.RUnlock()
+	for _, c := range app.readyChecks {
+		if c.interval > 0 {
+			go c.loop(ctx)
+		}
+	}
+}
+
+// readyChecksPassing returns true if the last recorded results of all readiness checks are successful
+func (app *App) readyChecksPassing() bool {
+	app.readyChecksMux.RLock()
+	defer app.readyChecksMux.RUnlock()
+	for _, c := range app.readyChecks {
+		if !c.passing() {
+			return false
+		}
+	}
+	return true
+}
+
+// notifyReady notifies runReadyHooks that readiness may have changed
+func (app *App) notifyReady() {
+	select {
+	case app.readyEvents <- struct{}{}:
+	default:
+		// a notification is already pending
+	}
+}
+
+// HealthReport measures the readiness of the app and returns the result of every check
+func (app *App) HealthReport(ctx context.Context) *HealthReport {
+	measure := app.readyHealth.Measure(ctx)
+
+	report := &HealthReport{
+		Timestamp: measure.Timestamp,
+		Checks:    make(map[string]*CheckResult),
+	}
+	switch measure.Status {
+	case health.StatusOK:
+		report.Status = HealthStatusOK
+	case health.StatusPartiallyAvailable:
+		report.Status = HealthStatusDegraded
+	default:
+		report.Status = HealthStatusUnavailable
+	}
+
+	app.readyChecksMux.RLock()
+	defer app.readyChecksMux.RUnlock()
+	for _, c := range app.readyChecks {
+		failure, failed := measure.Failures[c.name]
+		report.Checks[c.name] = c.result(failure, failed)
+	}
+
+	return report
+}
+
+// readinessHandler serves the readiness probe
+// With the "detailed" query parameter, it serves the HealthReport of the app
+func (app *App) readinessHandler() http.Handler {
+	h := app.readyHealth.Handler()
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !r.URL.Query().Has("detailed") {
+			h.ServeHTTP(w, r)
+			return
+		}
+
+		report := app.HealthReport(r.Context())
+		w.Header().Set("Content-Type", "application/json")
+		if report.Status == HealthStatusUnavailable {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+		_ = json.NewEncoder(w).Encode(report)
+	})
+}
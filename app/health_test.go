@@ -0,0 +1,162 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hellofresh/health-go/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countingCheckable is a checkable service counting the calls to Ready
+type countingCheckable struct {
+	mux   sync.Mutex
+	err   error
+	block chan struct{}
+	calls int
+}
+
+func (s *countingCheckable) Ready(ctx context.Context) error {
+	s.mux.Lock()
+	s.calls++
+	err, block := s.err, s.block
+	s.mux.Unlock()
+
+	if block != nil {
+		select {
+		case <-block:
+		case <-ctx.Done():
+		}
+	}
+	return err
+}
+
+func (s *countingCheckable) setErr(err error) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	s.err = err
+}
+
+func (s *countingCheckable) callCount() int {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	return s.calls
+}
+
+func startHealthTestApp(t *testing.T, services map[string]*countingCheckable, opts map[string][]ServiceOption) *App {
+	app := newTestApp(t)
+	Provide(app, "top", func() (string, error) {
+		for id, s := range services {
+			Provide(app, id, func() (*countingCheckable, error) { return s, nil }, opts[id]...)
+		}
+		return "top", nil
+	})
+	require.NoError(t, app.Start(context.Background()))
+	t.Cleanup(func() { require.NoError(t, app.Stop(context.Background())) })
+	return app
+}
+
+func TestHealthCheckFailureThreshold(t *testing.T) {
+	checkable := new(countingCheckable)
+	app := startHealthTestApp(t,
+		map[string]*countingCheckable{"checkable": checkable},
+		map[string][]ServiceOption{"checkable": {WithFailureThreshold(2)}},
+	)
+	ctx := context.Background()
+
+	require.Equal(t, health.StatusOK, app.readyHealth.Measure(ctx).Status)
+
+	// The first failure is tolerated
+	checkable.setErr(errors.New("test error"))
+	assert.Equal(t, health.StatusOK, app.readyHealth.Measure(ctx).Status)
+	report := app.HealthReport(ctx)
+	assert.Equal(t, HealthStatusFailing, report.Checks["checkable"].Status)
+	assert.Equal(t, 2, report.Checks["checkable"].ConsecutiveFailures)
+	assert.Equal(t, HealthStatusUnavailable, report.Status)
+
+	// A success resets the failures
+	checkable.setErr(nil)
+	assert.Equal(t, health.StatusOK, app.readyHealth.Measure(ctx).Status)
+	checkable.setErr(errors.New("test error"))
+	assert.Equal(t, health.StatusOK, app.readyHealth.Measure(ctx).Status)
+	assert.Equal(t, health.StatusUnavailable, app.readyHealth.Measure(ctx).Status)
+}
+
+func TestHealthCheckInterval(t *testing.T) {
+	checkable := new(countingCheckable)
+	app := startHealthTestApp(t,
+		map[string]*countingCheckable{"checkable": checkable},
+		map[string][]ServiceOption{"checkable": {WithCheckInterval(50 * time.Millisecond)}},
+	)
+	ctx := context.Background()
+
+	// Probes serve the cached result
+	for range 10 {
+		require.Equal(t, health.StatusOK, app.readyHealth.Measure(ctx).Status)
+	}
+	assert.LessOrEqual(t, checkable.callCount(), 2)
+
+	// The check runs in the background
+	checkable.setErr(errors.New("test error"))
+	assert.Eventually(t, func() bool {
+		return app.readyHealth.Measure(ctx).Status == health.StatusUnavailable
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestHealthCheckNonCritical(t *testing.T) {
+	critical, nonCritical := new(countingCheckable), new(countingCheckable)
+	app := startHealthTestApp(t,
+		map[string]*countingCheckable{"critical": critical, "non-critical": nonCritical},
+		map[string][]ServiceOption{"non-critical": {WithCritical(false)}},
+	)
+
+	serve := func() (int, *HealthReport) {
+		rec := httptest.NewRecorder()
+		app.readinessHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/ready?detailed", http.NoBody))
+		report := new(HealthReport)
+		require.NoError(t, json.NewDecoder(rec.Body).Decode(report))
+		return rec.Code, report
+	}
+
+	code, report := serve()
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, HealthStatusOK, report.Status)
+	require.Contains(t, report.Checks, "system.shutdown")
+	require.Contains(t, report.Checks, "critical")
+	assert.True(t, report.Checks["critical"].Critical)
+	assert.False(t, report.Checks["non-critical"].Critical)
+	assert.NotEmpty(t, report.Checks["critical"].Duration)
+
+	nonCritical.setErr(errors.New("non critical error"))
+	code, report = serve()
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, HealthStatusDegraded, report.Status)
+	assert.Equal(t, HealthStatusFailing, report.Checks["non-critical"].Status)
+	assert.Equal(t, "non critical error", report.Checks["non-critical"].Error)
+
+	critical.setErr(errors.New("critical error"))
+	code, report = serve()
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, HealthStatusUnavailable, report.Status)
+}
+
+func TestHealthCheckTimeout(t *testing.T) {
+	checkable := &countingCheckable{block: make(chan struct{})}
+	defer close(checkable.block)
+
+	app := startHealthTestApp(t,
+		map[string]*countingCheckable{"checkable": checkable},
+		map[string][]ServiceOption{"checkable": {WithHealthConfig(&health.Config{Timeout: 20 * time.Millisecond})}},
+	)
+
+	report := app.HealthReport(context.Background())
+	assert.Equal(t, HealthStatusUnavailable, report.Status)
+	assert.Equal(t, string(health.StatusTimeout), report.Checks["checkable"].Error)
+}
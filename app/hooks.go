@@ -0,0 +1,162 @@
+package app
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/hellofresh/health-go/v5"
+	"github.com/nmvalera/go-utils/log"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/multierr"
+	"go.uber.org/zap"
+)
+
+// Hook is a function run on a lifecycle transition of the app (see OnStart, OnReady and OnStop)
+type Hook func(ctx context.Context) error
+
+// OnStart registers a hook run once all services have started, before Start returns (e.g. to warm caches)
+//
+// Hooks run in registration order. If a hook fails, the next hooks are not run, services are stopped and Start returns the error.
+func (app *App) OnStart(hook Hook) {
+	app.onStart = append(app.onStart, hook)
+}
+
+// OnReady registers a hook run the first time the app is ready after Start (i.e. all readiness checks succeed)
+//
+// Hooks run in registration order in the background, their errors are logged.
+func (app *App) OnReady(hook Hook) {
+	app.onReady = append(app.onReady, hook)
+}
+
+// OnStop registers a hook run on Stop once the app has been drained, before services are stopped (e.g. to flush buffers)
+//
+// Hooks run in registration order. Their errors are returned by Stop but do not prevent services from stopping.
+func (app *App) OnStop(hook Hook) {
+	app.onStop = append(app.onStop, hook)
+}
+
+func (app *App) runStartHooks(ctx context.Context) error {
+	for _, hook := range app.onStart {
+		if err := hook(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (app *App) runStopHooks(ctx context.Context) error {
+	var err error
+	for _, hook := range app.onStop {
+		err = multierr.Append(err, hook(ctx))
+	}
+	return err
+}
+
+// runReadyHooks waits for the app to be ready, then runs the OnReady hooks
+// It gives up if the app is stopped before being ready
+//
+// Readiness is measured once, then evaluated from the results recorded by the readiness checks
+// (on probes or in the background) every time one is recorded or a service status changes,
+// so checks do not run more often than without hooks.
+func (app *App) runReadyHooks(ctx context.Context) {
+	if len(app.onReady) == 0 {
+		return
+	}
+
+	ready := app.readyHealth.Measure(ctx).Status == health.StatusOK
+	for !ready {
+		select {
+		case <-app.readyEvents:
+			ready = app.readyChecksPassing()
+		case <-ctx.Done():
+			return
+		}
+	}
+
+	logger := log.LoggerFromContext(ctx)
+	logger.Info("System ready")
+	for _, hook := range app.onReady {
+		if err := hook(ctx); err != nil {
+			logger.Error("Ready hook failed", zap.Error(err))
+		}
+	}
+}
+
+// ServiceStatusEvent is emitted when the status of a service changes (see SubscribeServiceStatus)
+type ServiceStatusEvent struct {
+	// Service is the id of the service
+	Service  string
+	Previous ServiceStatus
+	Status   ServiceStatus
+	Time     time.Time
+}
+
+// statusSubscriptions holds the channels of the ServiceStatusEvent subscribers
+type statusSubscriptions struct {
+	mux  sync.RWMutex
+	subs map[chan ServiceStatusEvent]struct{}
+}
+
+// SubscribeServiceStatus returns a channel receiving a ServiceStatusEvent on every service status change,
+// and a function to unsubscribe which closes the channel
+//
+// Events are sent without blocking the services: if the channel buffer is full, events are dropped.
+func (app *App) SubscribeServiceStatus(buffer int) (events <-chan ServiceStatusEvent, unsubscribe func()) {
+	ch := make(chan ServiceStatusEvent, buffer)
+
+	app.statusSubs.mux.Lock()
+	if app.statusSubs.subs == nil {
+		app.statusSubs.subs = make(map[chan ServiceStatusEvent]struct{})
+	}
+	app.statusSubs.subs[ch] = struct{}{}
+	app.statusSubs.mux.Unlock()
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			app.statusSubs.mux.Lock()
+			delete(app.statusSubs.subs, ch)
+			app.statusSubs.mux.Unlock()
+			close(ch)
+		})
+	}
+}
+
+// publishServiceStatus notifies subscribers and updates the status metric after a service status changed
+func (app *App) publishServiceStatus(s *service, previous, status ServiceStatus) {
+	app.setServiceStatusMetric(s.id, previous, status)
+	app.notifyReady()
+
+	event := ServiceStatusEvent{
+		Service:  s.id,
+		Previous: previous,
+		Status:   status,
+		Time:     time.Now(),
+	}
+
+	app.statusSubs.mux.RLock()
+	defer app.statusSubs.mux.RUnlock()
+	for ch := range app.statusSubs.subs {
+		select {
+		case ch <- event:
+		default:
+		}
+	}
+}
+
+func (app *App) newServiceStatusMetric() *prometheus.GaugeVec {
+	return prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: sanitizeMetricName(app.name),
+		Name:      "service_status",
+		Help:      "Status of the service (1 for the current status, 0 otherwise)",
+	}, []string{"service", "status"})
+}
+
+func (app *App) setServiceStatusMetric(id string, previous, status ServiceStatus) {
+	if app.serviceStatus == nil {
+		return
+	}
+	app.serviceStatus.WithLabelValues(id, previous.String()).Set(0)
+	app.serviceStatus.WithLabelValues(id, status.String()).Set(1)
+}
@@ -0,0 +1,167 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type eventRecorder struct {
+	mu     sync.Mutex
+	events []string
+}
+
+func (r *eventRecorder) record(event string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, event)
+}
+
+func (r *eventRecorder) hook(event string, err error) Hook {
+	return func(_ context.Context) error {
+		r.record(event)
+		return err
+	}
+}
+
+func (r *eventRecorder) get() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.events...)
+}
+
+type hookedService struct {
+	recorder *eventRecorder
+}
+
+func (s *hookedService) Start(_ context.Context) error {
+	s.recorder.record("service.start")
+	return nil
+}
+
+func (s *hookedService) Stop(_ context.Context) error {
+	s.recorder.record("service.stop")
+	return nil
+}
+
+func TestHooks(t *testing.T) {
+	app := newTestApp(t)
+	recorder := new(eventRecorder)
+
+	Provide(app, "main", func() (*hookedService, error) {
+		return &hookedService{recorder: recorder}, nil
+	})
+	app.OnStart(recorder.hook("start.1", nil))
+	app.OnStart(recorder.hook("start.2", nil))
+	app.OnReady(recorder.hook("ready", nil))
+	app.OnStop(recorder.hook("stop", nil))
+
+	require.NoError(t, app.Start(context.Background()))
+	require.Eventually(t, func() bool { return len(recorder.get()) == 4 }, time.Second, 10*time.Millisecond)
+	require.NoError(t, app.Stop(context.Background()))
+
+	assert.Equal(t, []string{"service.start", "start.1", "start.2", "ready", "stop", "service.stop"}, recorder.get())
+}
+
+func TestHooksErrors(t *testing.T) {
+	app := newTestApp(t)
+	recorder := new(eventRecorder)
+
+	Provide(app, "main", func() (*hookedService, error) {
+		return &hookedService{recorder: recorder}, nil
+	})
+	app.OnStart(recorder.hook("start.1", nil))
+	app.OnStop(recorder.hook("stop.1", errors.New("stop error")))
+	app.OnStop(recorder.hook("stop.2", nil))
+
+	require.NoError(t, app.Start(context.Background()))
+	require.EqualError(t, app.Stop(context.Background()), "stop error")
+
+	// Failing stop hooks do not prevent next hooks and services from stopping
+	assert.Equal(t, []string{"service.start", "start.1", "stop.1", "stop.2", "service.stop"}, recorder.get())
+}
+
+func TestStartHookError(t *testing.T) {
+	app := newTestApp(t)
+	recorder := new(eventRecorder)
+
+	Provide(app, "main", func() (*hookedService, error) {
+		return &hookedService{recorder: recorder}, nil
+	})
+	app.OnStart(recorder.hook("start.1", errors.New("start error")))
+	app.OnStart(recorder.hook("start.2", nil))
+
+	require.EqualError(t, app.Start(context.Background()), "start error")
+
+	// Started services are stopped and the run context is canceled
+	assert.Equal(t, []string{"service.start", "start.1", "service.stop"}, recorder.get())
+	assert.Equal(t, Stopped, app.services["main"].Status())
+	assert.Error(t, app.runCtx.Err())
+}
+
+func TestSubscribeServiceStatus(t *testing.T) {
+	app := newTestApp(t)
+
+	Provide(app, "main", func() (*hookedService, error) {
+		return &hookedService{recorder: new(eventRecorder)}, nil
+	})
+	assert.Equal(t, float64(1), testutil.ToFloat64(app.serviceStatus.WithLabelValues("main", "Constructed")))
+
+	events, unsubscribe := app.SubscribeServiceStatus(10)
+
+	require.NoError(t, app.Start(context.Background()))
+	assert.Equal(t, float64(1), testutil.ToFloat64(app.serviceStatus.WithLabelValues("main", "Running")))
+	assert.Equal(t, float64(0), testutil.ToFloat64(app.serviceStatus.WithLabelValues("main", "Constructed")))
+
+	require.NoError(t, app.Stop(context.Background()))
+	assert.Equal(t, float64(1), testutil.ToFloat64(app.serviceStatus.WithLabelValues("main", "Stopped")))
+	assert.Equal(t, float64(0), testutil.ToFloat64(app.serviceStatus.WithLabelValues("main", "Running")))
+
+	unsubscribe()
+	unsubscribe()
+
+	var transitions [][2]ServiceStatus
+	for event := range events {
+		assert.Equal(t, "main", event.Service)
+		assert.False(t, event.Time.IsZero())
+		transitions = append(transitions, [2]ServiceStatus{event.Previous, event.Status})
+	}
+	assert.Equal(t, [][2]ServiceStatus{
+		{Constructed, Starting},
+		{Starting, Running},
+		{Running, Stopping},
+		{Stopping, Stopped},
+	}, transitions)
+}
+
+func TestReadyHooksDoNotPollChecks(t *testing.T) {
+	app := newTestApp(t)
+	recorder := new(eventRecorder)
+
+	checkable := new(countingCheckable)
+	checkable.setErr(errors.New("not ready"))
+	Provide(app, "checkable", func() (*countingCheckable, error) { return checkable, nil })
+	app.OnReady(recorder.hook("ready", nil))
+
+	ctx := context.Background()
+	require.NoError(t, app.Start(ctx))
+	defer func() { require.NoError(t, app.Stop(ctx)) }()
+
+	// Readiness is measured once, then the check runs only on probes
+	require.Eventually(t, func() bool { return checkable.callCount() == 1 }, time.Second, 5*time.Millisecond)
+	time.Sleep(300 * time.Millisecond)
+	assert.Equal(t, 1, checkable.callCount())
+	assert.Empty(t, recorder.get())
+
+	// The hooks run once a probe records the app is ready
+	checkable.setErr(nil)
+	require.NotNil(t, app.HealthReport(ctx))
+	require.Eventually(t, func() bool { return len(recorder.get()) == 1 }, time.Second, 5*time.Millisecond)
+	assert.Equal(t, 2, checkable.callCount())
+}
@@ -0,0 +1,161 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// ErrServiceNotFound is returned when retrieving a service which has not been provided
+var ErrServiceNotFound = errors.New("service not found")
+
+// Key identifies a service providing a value of type T
+//
+// It allows to provide and retrieve a service without repeating its id and type,
+// and ensures at compile time that the retrieved value has the provided type.
+//
+// Example:
+// var DBKey = app.NewKey[*sql.DB]("database")
+//
+// db := app.ProvideKey(a, DBKey, newDB)
+// ...
+// db, err := app.Get(a, DBKey)
+type Key[T any] struct {
+	id string
+}
+
+// NewKey returns a key identifying the service with the given id
+// If id is empty, the service is identified by the name of type T (e.g. "*sql.DB"),
+// in which case T can not be the empty interface (any)
+func NewKey[T any](id string) Key[T] {
+	if id == "" {
+		id = typeID[T]()
+	}
+	return Key[T]{id: id}
+}
+
+// ID returns the id of the service
+func (k Key[T]) ID() string {
+	if k.id == "" {
+		return typeID[T]()
+	}
+	return k.id
+}
+
+func (k Key[T]) String() string {
+	return k.ID()
+}
+
+// typeID returns the name of type T, which identifies the services provided without id
+// It panics if T is the empty interface, as all such services would collide on the "interface {}" id
+func typeID[T any]() string {
+	t := reflect.TypeFor[T]()
+	if t.Kind() == reflect.Interface && t.NumMethod() == 0 {
+		panic(fmt.Sprintf("invalid empty service id for type %v (an id is required as the type does not identify the service)", t))
+	}
+	return t.String()
+}
+
+// ProvideKey provides the service identified by key (see Provide)
+func ProvideKey[T any](app *App, key Key[T], constructor func() (T, error), opts ...ServiceOption) T {
+	return Provide(app, key.ID(), constructor, opts...)
+}
+
+// Get returns the value of the service identified by key without constructing it
+//
+// It returns ErrServiceNotFound if the service has not been provided yet.
+// When called from a constructor, the service becomes a dependency of the service under construction.
+func Get[T any](app *App, key Key[T]) (T, error) {
+	var zero T
+
+	s, ok := app.services[key.ID()]
+	if !ok {
+		return zero, fmt.Errorf("%w: %q", ErrServiceNotFound, key.ID())
+	}
+	app.addCurrentDep(s)
+
+	if s.value == nil {
+		if s.err != nil {
+			return zero, s.err
+		}
+		return zero, nil
+	}
+
+	val, ok := s.value.(T)
+	if !ok {
+		return zero, fmt.Errorf("service %q provides a %T, not a %v", key.ID(), s.value, reflect.TypeFor[T]())
+	}
+	return val, nil
+}
+
+// Implementing returns the values of all provided services implementing T (typically an interface), ordered by service id
+//
+// Only services provided before the call are returned.
+// When called from a constructor, the returned services become dependencies of the service under construction.
+func Implementing[T any](app *App) []T {
+	var vals []T
+	for _, s := range app.userServices() {
+		if val, ok := s.value.(T); ok {
+			app.addCurrentDep(s)
+			vals = append(vals, val)
+		}
+	}
+	return vals
+}
+
+// Group returns the values of all services in the group (see WithGroup), ordered by service id
+//
+// Only services provided before the call are returned.
+// It returns an error if the value of a service in the group is not a T.
+// When called from a constructor, the services of the group become dependencies of the service under construction.
+func Group[T any](app *App, group string) ([]T, error) {
+	var vals []T
+	for _, s := range app.userServices() {
+		if !s.inGroup(group) {
+			continue
+		}
+		app.addCurrentDep(s)
+
+		if s.value == nil {
+			// the service failed to construct, its error is reported on the dependent service
+			continue
+		}
+		val, ok := s.value.(T)
+		if !ok {
+			return nil, fmt.Errorf("service %q in group %q provides a %T, not a %v", s.id, group, s.value, reflect.TypeFor[T]())
+		}
+		vals = append(vals, val)
+	}
+	return vals, nil
+}
+
+// userServices returns all services except system ones, ordered by id
+func (app *App) userServices() []*service {
+	services := make([]*service, 0, len(app.services))
+	for id, s := range app.services {
+		if strings.HasPrefix(id, "system.") || s == app.current {
+			continue
+		}
+		services = append(services, s)
+	}
+	sort.Slice(services, func(i, j int) bool { return services[i].id < services[j].id })
+	return services
+}
+
+// addCurrentDep adds the service as a dependency of the service under construction, if any
+func (app *App) addCurrentDep(s *service) {
+	if app.current != nil && app.current != s {
+		app.current.addDep(s)
+	}
+}
+
+func (s *service) inGroup(group string) bool {
+	for _, g := range s.groups {
+		if g == group {
+			return true
+		}
+	}
+	return false
+}
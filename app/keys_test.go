@@ -0,0 +1,170 @@
+package app
+
+import (
+	"context"
+	"testing"
+
+	"github.com/nmvalera/go-utils/app/svc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type keyedService struct {
+	name string
+}
+
+type runnableKeyedService struct {
+	keyedService
+}
+
+func (s *runnableKeyedService) Start(_ context.Context) error { return nil }
+func (s *runnableKeyedService) Stop(_ context.Context) error  { return nil }
+
+func TestKey(t *testing.T) {
+	app := newTestApp(t)
+
+	key := NewKey[*keyedService]("")
+	assert.Equal(t, "*app.keyedService", key.ID())
+	assert.Equal(t, "*app.keyedService", Key[*keyedService]{}.ID())
+	assert.Equal(t, "named", NewKey[*keyedService]("named").String())
+
+	_, err := Get(app, key)
+	require.ErrorIs(t, err, ErrServiceNotFound)
+
+	provided := ProvideKey(app, key, func() (*keyedService, error) {
+		return &keyedService{name: "test"}, nil
+	})
+
+	got, err := Get(app, key)
+	require.NoError(t, err)
+	assert.Same(t, provided, got)
+
+	// A key with the same id but another type fails
+	_, err = Get(app, NewKey[string](key.ID()))
+	require.Error(t, err)
+}
+
+func TestProvideDefaultID(t *testing.T) {
+	app := newTestApp(t)
+
+	Provide(app, "", func() (*keyedService, error) {
+		return &keyedService{name: "test"}, nil
+	})
+
+	require.Contains(t, app.services, "*app.keyedService")
+	got, err := Get(app, NewKey[*keyedService](""))
+	require.NoError(t, err)
+	assert.Equal(t, "test", got.name)
+}
+
+func TestProvideEmptyIDWithEmptyInterface(t *testing.T) {
+	app := newTestApp(t)
+
+	assert.Panics(t, func() {
+		Provide(app, "", func() (any, error) {
+			return &keyedService{name: "test"}, nil
+		})
+	})
+	assert.Panics(t, func() { NewKey[any]("") })
+}
+
+func TestAppProvideEmptyID(t *testing.T) {
+	app := newTestApp(t)
+
+	assert.Panics(t, func() {
+		app.Provide("", func() (any, error) {
+			return &keyedService{name: "test"}, nil
+		})
+	})
+	assert.Empty(t, app.services, "No service should be registered under a derived id")
+}
+
+func TestGetAddsDependency(t *testing.T) {
+	app := newTestApp(t)
+
+	key := NewKey[*keyedService]("dep")
+	ProvideKey(app, key, func() (*keyedService, error) {
+		return &keyedService{name: "dep"}, nil
+	})
+
+	Provide(app, "main", func() (*keyedService, error) {
+		dep, err := Get(app, key)
+		if err != nil {
+			return nil, err
+		}
+		return &keyedService{name: "main-" + dep.name}, nil
+	})
+
+	require.NoError(t, app.Error())
+	assert.Contains(t, app.services["main"].deps, "dep")
+}
+
+func TestImplementing(t *testing.T) {
+	app := newTestApp(t)
+
+	Provide(app, "b", func() (*runnableKeyedService, error) {
+		return &runnableKeyedService{keyedService{name: "b"}}, nil
+	})
+	Provide(app, "a", func() (*runnableKeyedService, error) {
+		return &runnableKeyedService{keyedService{name: "a"}}, nil
+	})
+	Provide(app, "c", func() (*keyedService, error) {
+		return &keyedService{name: "c"}, nil
+	})
+
+	var runnables []svc.Runnable
+	Provide(app, "main", func() (*keyedService, error) {
+		runnables = Implementing[svc.Runnable](app)
+		return &keyedService{name: "main"}, nil
+	})
+
+	require.Len(t, runnables, 2)
+	assert.Equal(t, "a", runnables[0].(*runnableKeyedService).name)
+	assert.Equal(t, "b", runnables[1].(*runnableKeyedService).name)
+
+	deps := app.services["main"].deps
+	assert.Contains(t, deps, "a")
+	assert.Contains(t, deps, "b")
+	assert.NotContains(t, deps, "c")
+}
+
+func TestGroup(t *testing.T) {
+	app := newTestApp(t)
+
+	Provide(app, "handler-2", func() (*keyedService, error) {
+		return &keyedService{name: "handler-2"}, nil
+	}, WithGroup("handlers"))
+	Provide(app, "handler-1", func() (*keyedService, error) {
+		return &keyedService{name: "handler-1"}, nil
+	}, WithGroup("handlers", "other"))
+	Provide(app, "not-a-handler", func() (*keyedService, error) {
+		return &keyedService{name: "not-a-handler"}, nil
+	})
+
+	handlers, err := Group[*keyedService](app, "handlers")
+	require.NoError(t, err)
+	require.Len(t, handlers, 2)
+	assert.Equal(t, "handler-1", handlers[0].name)
+	assert.Equal(t, "handler-2", handlers[1].name)
+
+	others, err := Group[*keyedService](app, "other")
+	require.NoError(t, err)
+	assert.Len(t, others, 1)
+
+	empty, err := Group[*keyedService](app, "unknown")
+	require.NoError(t, err)
+	assert.Empty(t, empty)
+
+	_, err = Group[svc.Runnable](app, "handlers")
+	require.Error(t, err)
+}
+
+func TestWithGroupInvalid(t *testing.T) {
+	app := newTestApp(t)
+
+	Provide(app, "main", func() (*keyedService, error) {
+		return &keyedService{name: "main"}, nil
+	}, WithGroup(""))
+
+	require.Error(t, app.Error())
+}
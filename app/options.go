@@ -1,8 +1,13 @@
 package app
 
 import (
+	"fmt"
+	"time"
+
 	"github.com/hellofresh/health-go/v5"
 	"github.com/nmvalera/go-utils/tag"
+	"github.com/spf13/viper"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 )
 
@@ -25,6 +30,7 @@ func WithVersion(version string) Option {
 }
 
 // WithLogger sets the logger of the application.
+// Log level changes on config reload do not apply to a logger set with this option.
 func WithLogger(logger *zap.Logger) Option {
 	return func(a *App) error {
 		a.logger = logger
@@ -39,6 +45,36 @@ func WithReplaceGlobalLoggers(replace bool) Option {
 	}
 }
 
+// WithReloadableConfig enables reloading the configuration from the given viper at runtime (see App.Reload).
+//
+// When the app is started with Run, the configuration is reloaded on SIGHUP and,
+// if viper has been loaded from a config file, each time the file changes.
+func WithReloadableConfig(v *viper.Viper) Option {
+	return func(a *App) error {
+		a.viper = v
+		return nil
+	}
+}
+
+// WithTracerProvider sets the TracerProvider of the application, overriding Tracing in Config.
+// It is set as the global TracerProvider when the app starts.
+func WithTracerProvider(tp trace.TracerProvider) Option {
+	return func(a *App) error {
+		a.tracerProvider = tp
+		return nil
+	}
+}
+
+// WithRedactedConfigKeys redacts the values of the configuration fields whose key contains one of the given keys (case-insensitive)
+// when the configuration is served by the admin API, in addition to default sensitive keys (password, secret, token...).
+// Keys also match environment variables, ignoring separators (e.g. apiKey matches API_KEY), see RedactEnv.
+func WithRedactedConfigKeys(keys ...string) Option {
+	return func(a *App) error {
+		a.redactedKeys = append(a.redactedKeys, keys...)
+		return nil
+	}
+}
+
 type ServiceOption func(*service) error
 
 // WithHealthConfig sets the health config of the service.
@@ -64,6 +100,102 @@ func WithHealthConfig(cfg *health.Config) ServiceOption {
 	}
 }
 
+// WithCritical sets whether the readiness check of the service is critical (default true).
+//
+// A failing non-critical check does not make the app unavailable, it reports the app as degraded
+// (it is the same as SkipOnErr in WithHealthConfig).
+func WithCritical(critical bool) ServiceOption {
+	return func(s *service) error {
+		s.healthConfig.SkipOnErr = !critical
+		return nil
+	}
+}
+
+// WithCheckInterval runs the readiness check of the service in the background at the given interval,
+// probes serving its cached result, overriding HealthzServer.CheckInterval in Config (zero runs the check on every probe).
+//
+// It avoids running an expensive check (e.g. querying a node) on every probe.
+func WithCheckInterval(interval time.Duration) ServiceOption {
+	return func(s *service) error {
+		s.checkOptions.interval = &interval
+		return nil
+	}
+}
+
+// WithFailureThreshold sets the number of consecutive failures of the readiness check of the service
+// before it reports failing, overriding HealthzServer.CheckFailureThreshold in Config.
+//
+// A check which has not succeeded yet reports failures immediately.
+func WithFailureThreshold(threshold int) ServiceOption {
+	return func(s *service) error {
+		if threshold < 1 {
+			return fmt.Errorf("invalid failure threshold %d (must be at least 1)", threshold)
+		}
+		s.checkOptions.failureThreshold = &threshold
+		return nil
+	}
+}
+
+// WithStartTimeout sets the maximum duration of the service Start, overriding StartTimeout in Config.
+//
+// It allows a slow starting service (e.g. a node client waiting to be synced) to start without increasing the timeout of all services.
+func WithStartTimeout(timeout time.Duration) ServiceOption {
+	return func(s *service) error {
+		s.startTimeout = &timeout
+		return nil
+	}
+}
+
+// WithStopTimeout sets the maximum duration of the service Stop, overriding StopTimeout in Config.
+func WithStopTimeout(timeout time.Duration) ServiceOption {
+	return func(s *service) error {
+		s.stopTimeout = &timeout
+		return nil
+	}
+}
+
+// WithRestartPolicy sets the policy applied when the service reports a fatal error while running (see svc.Fallible).
+//
+// Supervision is opt-in: the errors of a service without restart policy are not watched by the app.
+func WithRestartPolicy(policy *RestartPolicy) ServiceOption {
+	return func(s *service) error {
+		if policy == nil {
+			policy = NeverRestart()
+		}
+		s.restartPolicy = policy
+		s.restartBackOff = policy.backOff()
+		return nil
+	}
+}
+
+// WithGroup adds the service to the given groups.
+// The services of a group can be retrieved with Group (e.g. all the HTTP handlers of the app).
+func WithGroup(groups ...string) ServiceOption {
+	return func(s *service) error {
+		for _, group := range groups {
+			if group == "" {
+				return fmt.Errorf("invalid empty group")
+			}
+		}
+		s.groups = append(s.groups, groups...)
+		return nil
+	}
+}
+
+// WithEntrypoint sets the HTTP entrypoint on which the service registers its routes (svc.API)
+// and its middleware (svc.Middleware), instead of the main entrypoint.
+//
+// The entrypoint must be enabled with EnableEntrypoint before the app starts.
+func WithEntrypoint(name string) ServiceOption {
+	return func(s *service) error {
+		if name == "" {
+			return fmt.Errorf("invalid empty entrypoint")
+		}
+		s.entrypoint = name
+		return nil
+	}
+}
+
 // WithTags sets the tags of the service.
 func WithTags(tags ...*tag.Tag) ServiceOption {
 	return func(s *service) error {
@@ -0,0 +1,195 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sort"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/nmvalera/go-utils/app/svc"
+	"github.com/nmvalera/go-utils/log"
+	"go.uber.org/multierr"
+	"go.uber.org/zap"
+)
+
+// Reload re-reads the configuration from the viper set with WithReloadableConfig and applies its dynamic parts:
+//   - log level
+//   - tags
+//
+// If the viper has been loaded from a config file, the file is read again first.
+//
+// Then it notifies all Running services implementing svc.Reloadable (dependencies first).
+// Other configuration changes (entrypoints, timeouts...) require a restart of the app.
+func (app *App) Reload(ctx context.Context) error {
+	if app.viper == nil {
+		return fmt.Errorf("config reload not enabled (see WithReloadableConfig)")
+	}
+
+	// viper is not safe for concurrent use so we serialize all reloads
+	app.reloadMux.Lock()
+	defer app.reloadMux.Unlock()
+
+	if app.viper.ConfigFileUsed() != "" {
+		if err := app.viper.ReadInConfig(); err != nil {
+			return fmt.Errorf("failed to read config file: %w", err)
+		}
+	}
+
+	cfg := new(Config)
+	if err := cfg.Unmarshal(app.viper); err != nil {
+		return fmt.Errorf("failed to reload config: %w", err)
+	}
+
+	if cfg.Log != nil && cfg.Log.Level != nil {
+		app.logLevel.SetLevel(cfg.Log.Level.ZapLevel())
+	}
+	app.setTags(cfg.Tags)
+	app.reloadedCfg.Store(cfg)
+
+	ctx = app.context(ctx)
+	log.LoggerFromContext(ctx).Info("Config reloaded")
+
+	var errs []error
+	for _, s := range app.servicesInDependencyOrder() {
+		if err := s.reload(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return multierr.Combine(errs...)
+}
+
+// reload reloads the config and logs possible errors
+func (app *App) reload(ctx context.Context) {
+	if err := app.Reload(ctx); err != nil {
+		log.LoggerFromContext(app.context(ctx)).Error("Failed to reload config", zap.Error(err))
+	}
+}
+
+// requestReload schedules a config reload (handled by Run)
+func (app *App) requestReload() {
+	select {
+	case app.reloads <- struct{}{}:
+	default:
+		// a reload is already pending
+	}
+}
+
+// watchConfig requests a config reload each time the viper config file changes
+// It returns a function to stop watching
+//
+// We do not use viper.WatchConfig as it reads the file from its own goroutine which would race with Reload
+func (app *App) watchConfig() (stop func()) {
+	stop = func() {}
+	if app.viper == nil || app.viper.ConfigFileUsed() == "" {
+		return stop
+	}
+
+	logger := log.LoggerFromContext(app.runCtx)
+
+	cfgFile, err := filepath.Abs(app.viper.ConfigFileUsed())
+	if err != nil {
+		logger.Error("Failed to watch config file", zap.Error(err))
+		return stop
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		logger.Error("Failed to watch config file", zap.Error(err))
+		return stop
+	}
+
+	// We watch the parent directory so we keep on receiving events when the file is replaced (e.g. editors)
+	if err := watcher.Add(filepath.Dir(cfgFile)); err != nil {
+		logger.Error("Failed to watch config file", zap.Error(err))
+		_ = watcher.Close()
+		return stop
+	}
+
+	// A k8s ConfigMap is mounted as a symlink to a file in a "..data" directory which is atomically swapped on update,
+	// so we also track the file the config file resolves to (as viper.WatchConfig does)
+	realCfgFile, _ := filepath.EvalSymlinks(cfgFile)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for {
+			select {
+			case event, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+				currentCfgFile, _ := filepath.EvalSymlinks(cfgFile)
+				written := filepath.Clean(event.Name) == cfgFile && (event.Has(fsnotify.Write) || event.Has(fsnotify.Create))
+				if written || (currentCfgFile != "" && currentCfgFile != realCfgFile) {
+					realCfgFile = currentCfgFile
+					app.requestReload()
+				}
+			case err, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+				logger.Error("Config file watcher error", zap.Error(err))
+			}
+		}
+	}()
+
+	return func() {
+		_ = watcher.Close()
+		<-done
+	}
+}
+
+// servicesInDependencyOrder returns all services ordered so that every service appears after its dependencies
+func (app *App) servicesInDependencyOrder() []*service {
+	ids := make([]string, 0, len(app.services))
+	for id := range app.services {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	var (
+		ordered []*service
+		visited = make(map[string]bool)
+		visit   func(*service)
+	)
+	visit = func(s *service) {
+		if visited[s.id] {
+			return
+		}
+		visited[s.id] = true
+
+		depIDs := make([]string, 0, len(s.deps))
+		for id := range s.deps {
+			depIDs = append(depIDs, id)
+		}
+		sort.Strings(depIDs)
+		for _, id := range depIDs {
+			visit(s.deps[id])
+		}
+		ordered = append(ordered, s)
+	}
+	for _, id := range ids {
+		visit(app.services[id])
+	}
+
+	return ordered
+}
+
+func (s *service) reload(ctx context.Context) error {
+	r, ok := s.value.(svc.Reloadable)
+	if !ok || s.Status() != Running {
+		return nil
+	}
+
+	ctx = s.context(ctx)
+	logger := log.LoggerFromContext(ctx)
+	if err := r.Reload(ctx); err != nil {
+		logger.Error("Service failed to reload", zap.Error(err))
+		return fmt.Errorf("service %q: %w", s.id, err)
+	}
+	logger.Debug("Service reloaded")
+
+	return nil
+}
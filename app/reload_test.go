@@ -0,0 +1,210 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/nmvalera/go-utils/config"
+	"github.com/nmvalera/go-utils/tag"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+)
+
+type reloadableService struct {
+	mu      sync.Mutex
+	reloads []context.Context
+	err     error
+}
+
+func (s *reloadableService) Reload(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.reloads = append(s.reloads, ctx)
+	return s.err
+}
+
+func (s *reloadableService) count() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.reloads)
+}
+
+func (s *reloadableService) Start(_ context.Context) error { return nil }
+func (s *reloadableService) Stop(_ context.Context) error  { return nil }
+
+func newReloadableTestApp(t *testing.T) (*App, *viper.Viper) {
+	v := config.NewViper()
+	require.NoError(t, AddFlags(v, pflag.NewFlagSet("test", pflag.ContinueOnError)))
+	v.Set("mainEp.addr", "127.0.0.1:0")
+	v.Set("healthzEp.addr", "127.0.0.1:0")
+
+	cfg := new(Config)
+	require.NoError(t, cfg.Unmarshal(v))
+
+	app, err := NewApp(cfg, WithName("test"), WithReloadableConfig(v))
+	require.NoError(t, err)
+	return app, v
+}
+
+func TestReload(t *testing.T) {
+	app, v := newReloadableTestApp(t)
+
+	dep, main := new(reloadableService), new(reloadableService)
+	Provide(app, "main", func() (*reloadableService, error) {
+		Provide(app, "dep", func() (*reloadableService, error) {
+			return dep, nil
+		})
+		return main, nil
+	})
+
+	// Services which are not running are not reloaded
+	require.NoError(t, app.Reload(context.Background()))
+	assert.Equal(t, 0, dep.count())
+
+	require.NoError(t, app.Start(context.Background()))
+	defer func() { require.NoError(t, app.Stop(context.Background())) }()
+
+	assert.False(t, app.logger.Core().Enabled(zapcore.DebugLevel))
+
+	v.Set("log.level", "debug")
+	v.Set("tags", "env:staging")
+	require.NoError(t, app.Reload(context.Background()))
+
+	// Log level is applied
+	assert.Equal(t, zapcore.DebugLevel, app.logLevel.Level())
+	assert.True(t, app.logger.Core().Enabled(zapcore.DebugLevel))
+
+	// Tags are applied
+	tags := tag.FromContext(app.Context(context.Background()))
+	assert.Contains(t, tags, tag.Key("env").String("staging"))
+	assert.Contains(t, tags, tag.Key("app").String("test"))
+
+	// The admin API serves the reloaded config
+	b, err := app.RedactedConfig()
+	require.NoError(t, err)
+	assert.Contains(t, string(b), `"level":"debug"`)
+
+	// Services are notified with the reloaded tags
+	require.Equal(t, 1, dep.count())
+	require.Equal(t, 1, main.count())
+	assert.Equal(t, "dep", getComponentTag(dep.reloads[0]))
+	assert.Contains(t, tag.FromContext(main.reloads[0]), tag.Key("env").String("staging"))
+
+	// Errors are reported
+	dep.err = errors.New("reload error")
+	err = app.Reload(context.Background())
+	require.Error(t, err)
+	assert.Equal(t, `service "dep": reload error`, err.Error())
+	assert.Equal(t, 2, main.count())
+}
+
+func TestReloadNotEnabled(t *testing.T) {
+	app := newTestApp(t)
+	require.Error(t, app.Reload(context.Background()))
+}
+
+func TestReloadOnSIGHUP(t *testing.T) {
+	app, v := newReloadableTestApp(t)
+
+	s := new(reloadableService)
+	Provide(app, "test", func() (*reloadableService, error) {
+		return s, nil
+	})
+
+	runErr := make(chan error)
+	go func() {
+		runErr <- app.Run(context.Background())
+	}()
+	require.Eventually(t, func() bool { return app.services["test"].Status() == Running }, time.Second, 5*time.Millisecond)
+
+	v.Set("log.level", "warn")
+	app.done <- syscall.SIGHUP
+	require.Eventually(t, func() bool { return s.count() == 1 }, time.Second, 5*time.Millisecond)
+	assert.Equal(t, zapcore.WarnLevel, app.logLevel.Level())
+
+	// SIGHUP does not stop the app
+	assert.Equal(t, Running, app.services["test"].Status())
+
+	app.done <- syscall.SIGTERM
+	require.NoError(t, <-runErr)
+}
+
+func TestReloadOnConfigFileChange(t *testing.T) {
+	cfgFile := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(cfgFile, []byte("log:\n  level: info\n"), 0o600))
+
+	app, v := newReloadableTestApp(t)
+	v.SetConfigFile(cfgFile)
+	require.NoError(t, v.ReadInConfig())
+
+	s := new(reloadableService)
+	Provide(app, "test", func() (*reloadableService, error) {
+		return s, nil
+	})
+
+	runErr := make(chan error)
+	go func() {
+		runErr <- app.Run(context.Background())
+	}()
+	require.Eventually(t, func() bool { return app.services["test"].Status() == Running }, time.Second, 5*time.Millisecond)
+
+	// The watcher is set up asynchronously by Run so we keep on writing until the change is picked up
+	require.Eventually(t, func() bool {
+		require.NoError(t, os.WriteFile(cfgFile, []byte("log:\n  level: error\n"), 0o600))
+		return app.logLevel.Level() == zapcore.ErrorLevel
+	}, 2*time.Second, 50*time.Millisecond)
+	assert.GreaterOrEqual(t, s.count(), 1)
+
+	app.done <- syscall.SIGTERM
+	require.NoError(t, <-runErr)
+}
+
+func TestReloadOnConfigMapChange(t *testing.T) {
+	// Reproduces the layout of a k8s ConfigMap volume, which is updated by atomically swapping the ..data symlink
+	dir := t.TempDir()
+	writeData := func(name, level string) {
+		require.NoError(t, os.Mkdir(filepath.Join(dir, name), 0o700))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name, "config.yaml"), []byte("log:\n  level: "+level+"\n"), 0o600))
+		require.NoError(t, os.Symlink(name, filepath.Join(dir, "..data_tmp")))
+		require.NoError(t, os.Rename(filepath.Join(dir, "..data_tmp"), filepath.Join(dir, "..data")))
+	}
+	writeData("..2024_01_01", "info")
+	cfgFile := filepath.Join(dir, "config.yaml")
+	require.NoError(t, os.Symlink(filepath.Join("..data", "config.yaml"), cfgFile))
+
+	app, v := newReloadableTestApp(t)
+	v.SetConfigFile(cfgFile)
+	require.NoError(t, v.ReadInConfig())
+
+	s := new(reloadableService)
+	Provide(app, "test", func() (*reloadableService, error) {
+		return s, nil
+	})
+
+	runErr := make(chan error)
+	go func() {
+		runErr <- app.Run(context.Background())
+	}()
+	require.Eventually(t, func() bool { return app.services["test"].Status() == Running }, time.Second, 5*time.Millisecond)
+
+	// The watcher is set up asynchronously by Run so we keep on updating until the change is picked up
+	i := 0
+	require.Eventually(t, func() bool {
+		i++
+		writeData(fmt.Sprintf("..2024_01_02_%d", i), "error")
+		return app.logLevel.Level() == zapcore.ErrorLevel
+	}, 2*time.Second, 50*time.Millisecond)
+
+	app.done <- syscall.SIGTERM
+	require.NoError(t, <-runErr)
+}
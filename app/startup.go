@@ -0,0 +1,96 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hellofresh/health-go/v5"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// registerStartupCheck registers a startup check succeeding once all services have reached Running
+//
+// Once succeeded the check keeps on succeeding, as orchestrators only use startup probes
+// to know when to start running liveness and readiness probes
+func (app *App) registerStartupCheck() error {
+	return app.startupHealth.Register(health.Config{
+		Name: "system.startup",
+		Check: func(_ context.Context) error {
+			if app.started.Load() {
+				return nil
+			}
+
+			var pending []string
+			for id, s := range app.services {
+				if s.Status() != Running {
+					pending = append(pending, id)
+				}
+			}
+			if len(pending) > 0 {
+				sort.Strings(pending)
+				return fmt.Errorf("services not running yet: %s", strings.Join(pending, ", "))
+			}
+
+			app.started.Store(true)
+			return nil
+		},
+	})
+}
+
+// registerServiceMetrics registers the app level metrics about services
+func (app *App) registerServiceMetrics() {
+	app.serviceStartDuration = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: sanitizeMetricName(app.name),
+		Name:      "service_start_duration_seconds",
+		Help:      "Duration of the last start of the service in seconds",
+	}, []string{"service"})
+	app.prometheus.MustRegister(app.serviceStartDuration)
+
+	app.serviceStatus = app.newServiceStatusMetric()
+	app.prometheus.MustRegister(app.serviceStatus)
+}
+
+// startTimeout returns the maximum duration App.Start can take,
+// which is the largest of the app StartTimeout and of the services specific start timeouts
+func (app *App) startTimeout() time.Duration {
+	timeout := *app.cfg.StartTimeout
+	for _, s := range app.services {
+		if s.startTimeout != nil && *s.startTimeout > timeout {
+			timeout = *s.startTimeout
+		}
+	}
+	return timeout
+}
+
+// stopTimeout returns the maximum duration App.Stop can take,
+// which is the largest of the app StopTimeout and of the services specific stop timeouts
+func (app *App) stopTimeout() time.Duration {
+	timeout := *app.cfg.StopTimeout
+	for _, s := range app.services {
+		if s.stopTimeout != nil && *s.stopTimeout > timeout {
+			timeout = *s.stopTimeout
+		}
+	}
+	return timeout
+}
+
+// withStartTimeout returns the context to pass to the service Start
+// bounded by the service start timeout if set, or by the app StartTimeout otherwise
+func (s *service) withStartTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if s.startTimeout != nil {
+		return context.WithTimeout(ctx, *s.startTimeout)
+	}
+	return context.WithTimeout(ctx, *s.app.cfg.StartTimeout)
+}
+
+// withStopTimeout returns the context to pass to the service Stop
+// bounded by the service stop timeout if set, or by the app StopTimeout otherwise
+func (s *service) withStopTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if s.stopTimeout != nil {
+		return context.WithTimeout(ctx, *s.stopTimeout)
+	}
+	return context.WithTimeout(ctx, *s.app.cfg.StopTimeout)
+}
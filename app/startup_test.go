@@ -0,0 +1,123 @@
+package app
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/nmvalera/go-utils/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type slowService struct {
+	release   chan struct{}
+	startTime time.Duration
+}
+
+func (s *slowService) Start(ctx context.Context) error {
+	var timer <-chan time.Time
+	if s.startTime > 0 {
+		timer = time.After(s.startTime)
+	}
+	select {
+	case <-s.release:
+		return nil
+	case <-timer:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (s *slowService) Stop(_ context.Context) error { return nil }
+
+func TestStartupProbe(t *testing.T) {
+	app := newTestApp(t)
+	app.cfg.HealthzServer.StartupPath = common.Ptr("/startup")
+
+	slow := &slowService{release: make(chan struct{})}
+	Provide(app, "main", func() (string, error) {
+		app.EnableHealthzEntrypoint()
+		Provide(app, "slow", func() (*slowService, error) {
+			return slow, nil
+		})
+		return "main", nil
+	})
+
+	started := make(chan error)
+	go func() {
+		started <- app.Start(context.Background())
+	}()
+
+	// The healthz entrypoint is up while the slow service is still starting
+	var status int
+	require.Eventually(t, func() bool {
+		addr := app.healthz.Addr()
+		if addr == "" {
+			return false
+		}
+		resp, err := http.Get("http://" + addr + "/startup")
+		if err != nil {
+			return false
+		}
+		_ = resp.Body.Close()
+		status = resp.StatusCode
+		return true
+	}, time.Second, 10*time.Millisecond)
+	assert.Equal(t, http.StatusServiceUnavailable, status)
+
+	close(slow.release)
+	require.NoError(t, <-started)
+	defer func() { require.NoError(t, app.Stop(context.Background())) }()
+
+	resp, err := http.Get("http://" + app.healthz.Addr() + "/startup")
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
+
+func TestServiceStartTimeout(t *testing.T) {
+	app := newTestApp(t)
+	app.cfg.StartTimeout = common.Ptr(50 * time.Millisecond)
+
+	Provide(app, "main", func() (string, error) {
+		Provide(app, "slow", func() (*slowService, error) {
+			return &slowService{startTime: 150 * time.Millisecond}, nil
+		}, WithStartTimeout(time.Second))
+		return "main", nil
+	})
+
+	require.NoError(t, app.Start(context.Background()))
+	defer func() { require.NoError(t, app.Stop(context.Background())) }()
+
+	// Start duration is exposed as a metric
+	families := gatherMetrics(t, app)
+	require.Contains(t, families, "test_service_start_duration_seconds")
+	var startDuration float64
+	for _, m := range families["test_service_start_duration_seconds"].GetMetric() {
+		if m.GetLabel()[0].GetValue() == "slow" {
+			startDuration = m.GetGauge().GetValue()
+		}
+	}
+	assert.GreaterOrEqual(t, startDuration, 0.15)
+}
+
+func TestServiceStartTimeoutExceeded(t *testing.T) {
+	app := newTestApp(t)
+	app.cfg.StartTimeout = common.Ptr(time.Second)
+
+	Provide(app, "main", func() (string, error) {
+		Provide(app, "slow", func() (*slowService, error) {
+			return &slowService{startTime: time.Second}, nil
+		}, WithStartTimeout(50*time.Millisecond))
+		return "main", nil
+	})
+
+	begin := time.Now()
+	err := app.Start(context.Background())
+	require.Error(t, err)
+	assert.Equal(t, "service \"main\"\n>service \"slow\": context deadline exceeded", err.Error())
+	assert.Less(t, time.Since(begin), 500*time.Millisecond)
+}
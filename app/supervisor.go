@@ -0,0 +1,270 @@
+package app
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+	"github.com/nmvalera/go-utils/app/svc"
+	"github.com/nmvalera/go-utils/log"
+	"go.uber.org/zap"
+)
+
+// RestartPolicy defines how the App reacts when a running service reports a fatal error (see svc.Fallible)
+type RestartPolicy struct {
+	// Restart indicates whether the service is restarted on failure
+	// If false, a failure of the service triggers an orderly shutdown of the app
+	Restart bool
+
+	// MaxRestarts is the maximum number of restarts of the service
+	// Once reached, the next failure triggers an orderly shutdown of the app (0 means unlimited)
+	MaxRestarts int
+
+	// BackOff configures the exponential backoff delay between a failure and the restart
+	BackOff []backoff.ExponentialBackOffOpts
+
+	// ResetAfter is the duration after which a running service is considered healthy
+	// A failure occurring after the service has been running for ResetAfter resets the backoff delay
+	// and the restarts count (defaults to DefaultRestartResetAfter)
+	ResetAfter time.Duration
+}
+
+// DefaultRestartResetAfter is the default duration after which a running service is considered healthy
+const DefaultRestartResetAfter = time.Minute
+
+// NeverRestart returns a policy that shuts down the app when the service fails
+func NeverRestart() *RestartPolicy {
+	return &RestartPolicy{}
+}
+
+// RestartOnFailure returns a policy that restarts the service when it fails
+// waiting for an exponential backoff delay before each restart
+//
+// If maxRestarts is reached, the next failure triggers an orderly shutdown of the app (0 means unlimited)
+func RestartOnFailure(maxRestarts int, opts ...backoff.ExponentialBackOffOpts) *RestartPolicy {
+	return &RestartPolicy{
+		Restart:     true,
+		MaxRestarts: maxRestarts,
+		BackOff:     opts,
+	}
+}
+
+func (p *RestartPolicy) backOff() backoff.BackOff {
+	if p == nil || !p.Restart {
+		return &backoff.StopBackOff{}
+	}
+
+	// By default the backoff never expires, only MaxRestarts limits the number of restarts
+	opts := append([]backoff.ExponentialBackOffOpts{backoff.WithMaxElapsedTime(0)}, p.BackOff...)
+	var b backoff.BackOff = backoff.NewExponentialBackOff(opts...)
+	if p.MaxRestarts > 0 {
+		b = backoff.WithMaxRetries(b, uint64(p.MaxRestarts))
+	}
+	return b
+}
+
+func (p *RestartPolicy) resetAfter() time.Duration {
+	if p.ResetAfter > 0 {
+		return p.ResetAfter
+	}
+	return DefaultRestartResetAfter
+}
+
+// supervise watches the errors published by a running Fallible service
+// until the service fails or is stopped
+//
+// Only services with a restart policy are supervised (see WithRestartPolicy)
+func (s *service) supervise() {
+	f, ok := s.value.(svc.Fallible)
+	if !ok || s.restartPolicy == nil {
+		return
+	}
+
+	s.mux.Lock()
+	done := make(chan struct{})
+	s.runDone = done
+	s.runSince = time.Now()
+	s.mux.Unlock()
+
+	errs := f.Errors()
+	go func() {
+		for {
+			select {
+			case err, ok := <-errs:
+				if !ok {
+					return
+				}
+				if err != nil {
+					s.handleFailure(err)
+					return
+				}
+			case <-done:
+				return
+			}
+		}
+	}()
+}
+
+// endSupervision stops watching the errors of the service
+func (s *service) endSupervision() {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	if s.runDone != nil {
+		close(s.runDone)
+		s.runDone = nil
+	}
+}
+
+// handleFailure applies the restart policy of the service after it reported a fatal error
+func (s *service) handleFailure(err error) {
+	logger := log.LoggerFromContext(s.context(s.app.runCtx))
+
+	s.mux.Lock()
+	if s.Status() != Running {
+		// The service is being stopped, so the error is expected
+		s.mux.Unlock()
+		return
+	}
+	s.runErr = err
+	s.setStatus(Error)
+	healthy := time.Since(s.runSince) >= s.restartPolicy.resetAfter()
+	s.mux.Unlock()
+
+	logger.Error("Service failed", zap.Error(err))
+
+	if healthy {
+		// The service ran long enough to be considered healthy, so previous failures are forgotten
+		s.restartBackOff.Reset()
+	}
+
+	delay := s.restartBackOff.NextBackOff()
+	if delay == backoff.Stop {
+		logger.Error("Service will not be restarted, shutting down...")
+		s.app.shutdown(&ServiceError{svc: s, directErr: err})
+		return
+	}
+
+	logger.Warn("Service restarting...", zap.Duration("delay", delay))
+	select {
+	case <-time.After(delay):
+	case <-s.app.runCtx.Done():
+		return
+	}
+
+	restarted, restartErr := s.app.restart(s)
+	if restartErr != nil {
+		logger.Error("Service failed to restart, shutting down...", zap.Error(restartErr))
+		s.app.shutdown(restartErr)
+		return
+	}
+	if restarted {
+		logger.Info("Service successfully restarted")
+	}
+}
+
+// dependents returns the service and all the services depending on it (directly or transitively)
+// ordered so that every service appears before the services it depends on
+func (s *service) dependents() []*service {
+	var (
+		ordered []*service
+		visited = make(map[string]bool)
+		visit   func(*service)
+	)
+	visit = func(srvc *service) {
+		if visited[srvc.id] {
+			return
+		}
+		visited[srvc.id] = true
+		for _, dep := range srvc.depsOf {
+			visit(dep)
+		}
+		ordered = append(ordered, srvc)
+	}
+	visit(s)
+
+	return ordered
+}
+
+// restart stops the service and its dependents, then starts them again
+// It does nothing and returns false if the app is stopping
+//
+// The restart is canceled when the app stops, so it never delays the app shutdown
+func (app *App) restart(s *service) (bool, error) {
+	app.supervisorMux.Lock()
+	if app.stopping {
+		app.supervisorMux.Unlock()
+		return false, nil
+	}
+	app.restarts.Add(1)
+	ctx := app.restartCtx
+	app.supervisorMux.Unlock()
+	defer app.restarts.Done()
+
+	affected := s.dependents()
+
+	stopCtx, stopCancel := context.WithTimeout(ctx, app.stopTimeout())
+	defer stopCancel()
+	for _, srvc := range affected {
+		if err := srvc.stopSelf(stopCtx); err != nil {
+			// The service is about to be started again, so we only log the error
+			log.LoggerFromContext(srvc.context(stopCtx)).Warn("Service failed to stop before restart", zap.Error(err))
+		}
+		srvc.reset()
+	}
+
+	startCtx, startCancel := context.WithTimeout(ctx, app.startTimeout())
+	defer startCancel()
+	for i := len(affected) - 1; i >= 0; i-- {
+		if err := affected[i].start(startCtx); err != nil {
+			if ctx.Err() != nil {
+				// The app is stopping
+				return false, nil
+			}
+			return false, err
+		}
+	}
+
+	return true, nil
+}
+
+// stopRestarts prevents any further restart of failed services and waits for pending restarts to be canceled
+func (app *App) stopRestarts() {
+	app.supervisorMux.Lock()
+	app.stopping = true
+	if app.restartCancel != nil {
+		app.restartCancel()
+	}
+	app.supervisorMux.Unlock()
+
+	app.restarts.Wait()
+}
+
+// shutdown requests an orderly shutdown of the app (handled by Run)
+func (app *App) shutdown(err error) {
+	select {
+	case app.fatal <- err:
+	default:
+		// a shutdown is already pending
+	}
+}
+
+// reset resets the service, so it can be started again
+func (s *service) reset() {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	s.startOnce = sync.Once{}
+	s.stopOnce = sync.Once{}
+	s.stopChan = make(chan struct{})
+	s.runErr = nil
+	s.setStatus(Constructed)
+}
+
+// runError returns the error that put the service in Error state
+func (s *service) runError() error {
+	if s.err != nil {
+		return s.err
+	}
+	return s.runErr
+}
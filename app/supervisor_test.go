@@ -0,0 +1,256 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+	"github.com/nmvalera/go-utils/app/svc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fallibleService struct {
+	svc.ErrorReporter
+
+	starts atomic.Int32
+	stops  atomic.Int32
+}
+
+func (s *fallibleService) Start(_ context.Context) error {
+	s.starts.Add(1)
+	return nil
+}
+
+func (s *fallibleService) Stop(_ context.Context) error {
+	s.stops.Add(1)
+	return nil
+}
+
+func TestRestartOnFailure(t *testing.T) {
+	app := newTestApp(t)
+
+	dep, main := new(fallibleService), new(fallibleService)
+	Provide(app, "main", func() (*fallibleService, error) {
+		Provide(app, "dep", func() (*fallibleService, error) {
+			return dep, nil
+		}, WithRestartPolicy(RestartOnFailure(0, backoff.WithInitialInterval(time.Millisecond))))
+		return main, nil
+	}, WithRestartPolicy(NeverRestart()))
+
+	require.NoError(t, app.Start(context.Background()))
+
+	// The failure of dep restarts dep and main which depends on it
+	dep.ReportError(errors.New("connection lost"))
+	require.Eventually(t, func() bool {
+		return dep.starts.Load() == 2 && main.starts.Load() == 2 &&
+			app.services["dep"].Status() == Running && app.services["main"].Status() == Running
+	}, time.Second, 5*time.Millisecond)
+	assert.Equal(t, int32(1), dep.stops.Load())
+	assert.Equal(t, int32(1), main.stops.Load())
+
+	// The service is supervised again after restart
+	dep.ReportError(errors.New("connection lost again"))
+	require.Eventually(t, func() bool {
+		return dep.starts.Load() == 3 && main.starts.Load() == 3 && app.services["main"].Status() == Running
+	}, time.Second, 5*time.Millisecond)
+
+	// A failure of main does not restart dep, and shuts down the app
+	main.ReportError(errors.New("main failed"))
+	select {
+	case err := <-app.fatal:
+		assert.ErrorContains(t, err, "main failed")
+	case <-time.After(time.Second):
+		t.Fatal("app shutdown not requested")
+	}
+	assert.Equal(t, int32(3), dep.starts.Load())
+
+	require.NoError(t, app.Stop(context.Background()))
+	assert.Equal(t, int32(3), dep.stops.Load())
+	assert.Equal(t, int32(3), main.stops.Load())
+}
+
+func TestNeverRestart(t *testing.T) {
+	app := newTestApp(t)
+
+	s := new(fallibleService)
+	Provide(app, "test", func() (*fallibleService, error) {
+		return s, nil
+	}, WithRestartPolicy(NeverRestart()))
+
+	runErr := make(chan error)
+	go func() {
+		runErr <- app.Run(context.Background())
+	}()
+
+	require.Eventually(t, func() bool { return app.services["test"].Status() == Running }, time.Second, 5*time.Millisecond)
+	s.ReportError(errors.New("fatal error"))
+
+	select {
+	case err := <-runErr:
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "fatal error")
+	case <-time.After(time.Second):
+		t.Fatal("app did not shut down")
+	}
+
+	// The app shuts down in order, so the failed service is stopped
+	assert.Equal(t, int32(1), s.starts.Load())
+	assert.Equal(t, int32(1), s.stops.Load())
+	assert.Equal(t, Stopped, app.services["test"].Status())
+}
+
+func TestRestartMaxRestarts(t *testing.T) {
+	app := newTestApp(t)
+
+	s := new(fallibleService)
+	Provide(app, "test", func() (*fallibleService, error) {
+		return s, nil
+	}, WithRestartPolicy(RestartOnFailure(1, backoff.WithInitialInterval(time.Millisecond))))
+
+	require.NoError(t, app.Start(context.Background()))
+
+	s.ReportError(errors.New("first failure"))
+	require.Eventually(t, func() bool {
+		return s.starts.Load() == 2 && app.services["test"].Status() == Running
+	}, time.Second, 5*time.Millisecond)
+
+	s.ReportError(errors.New("second failure"))
+	select {
+	case err := <-app.fatal:
+		assert.ErrorContains(t, err, "second failure")
+	case <-time.After(time.Second):
+		t.Fatal("app shutdown not requested")
+	}
+	assert.Equal(t, Error, app.services["test"].Status())
+
+	require.NoError(t, app.Stop(context.Background()))
+	assert.Equal(t, int32(2), s.starts.Load())
+	assert.Equal(t, int32(2), s.stops.Load())
+}
+
+func TestRestartResetAfterHealthyRun(t *testing.T) {
+	app := newTestApp(t)
+
+	s := new(fallibleService)
+	policy := RestartOnFailure(1, backoff.WithInitialInterval(time.Millisecond))
+	policy.ResetAfter = 20 * time.Millisecond
+	Provide(app, "test", func() (*fallibleService, error) {
+		return s, nil
+	}, WithRestartPolicy(policy))
+
+	require.NoError(t, app.Start(context.Background()))
+
+	// Failures separated by healthy runs do not count towards MaxRestarts
+	for i := int32(2); i <= 3; i++ {
+		require.Eventually(t, func() bool { return app.services["test"].Status() == Running }, time.Second, 5*time.Millisecond)
+		time.Sleep(30 * time.Millisecond)
+		s.ReportError(errors.New("failure"))
+		require.Eventually(t, func() bool {
+			return s.starts.Load() == i && app.services["test"].Status() == Running
+		}, time.Second, 5*time.Millisecond)
+	}
+	assert.Empty(t, app.fatal)
+
+	require.NoError(t, app.Stop(context.Background()))
+}
+
+func TestNotSupervisedWithoutPolicy(t *testing.T) {
+	app := newTestApp(t)
+
+	s := new(fallibleService)
+	Provide(app, "test", func() (*fallibleService, error) {
+		return s, nil
+	})
+
+	require.NoError(t, app.Start(context.Background()))
+
+	s.ReportError(errors.New("not supervised"))
+	time.Sleep(20 * time.Millisecond)
+	assert.Empty(t, app.fatal)
+	assert.Equal(t, Running, app.services["test"].Status())
+
+	require.NoError(t, app.Stop(context.Background()))
+}
+
+func TestFailureIgnoredWhileStopping(t *testing.T) {
+	app := newTestApp(t)
+
+	s := new(fallibleService)
+	Provide(app, "test", func() (*fallibleService, error) {
+		return s, nil
+	}, WithRestartPolicy(NeverRestart()))
+
+	require.NoError(t, app.Start(context.Background()))
+	require.NoError(t, app.Stop(context.Background()))
+
+	s.ReportError(errors.New("error after stop"))
+	time.Sleep(20 * time.Millisecond)
+	assert.Empty(t, app.fatal)
+	assert.Equal(t, Stopped, app.services["test"].Status())
+}
+
+// orderedService records its name in order when stopped, after stopDelay
+type orderedService struct {
+	fallibleService
+
+	name      string
+	stopDelay time.Duration
+	order     chan<- string
+}
+
+func (s *orderedService) Stop(ctx context.Context) error {
+	time.Sleep(s.stopDelay)
+	s.order <- s.name
+	return s.fallibleService.Stop(ctx)
+}
+
+func TestStopWaitsForFailedDependent(t *testing.T) {
+	app := newTestApp(t)
+
+	order := make(chan string, 5)
+	newService := func(name string, stopDelay time.Duration) *orderedService {
+		return &orderedService{name: name, stopDelay: stopDelay, order: order}
+	}
+
+	// dep is a dependency of failing (through slow) and of other
+	// so dep is stopped from other while slow is still stopping
+	failing := newService("failing", 0)
+	Provide(app, "main", func() (*orderedService, error) {
+		Provide(app, "slow", func() (*orderedService, error) {
+			Provide(app, "failing", func() (*orderedService, error) {
+				Provide(app, "dep", func() (*orderedService, error) {
+					return newService("dep", 0), nil
+				})
+				return failing, nil
+			}, WithRestartPolicy(RestartOnFailure(0, backoff.WithInitialInterval(time.Hour))))
+			return newService("slow", 50*time.Millisecond), nil
+		})
+		Provide(app, "other", func() (*orderedService, error) {
+			// dep is already provided, so the constructor is not called
+			Provide(app, "dep", func() (*orderedService, error) { return nil, nil })
+			return newService("other", 0), nil
+		})
+		return newService("main", 0), nil
+	})
+
+	require.NoError(t, app.Start(context.Background()))
+
+	// failing waits for its restart
+	failing.ReportError(errors.New("connection lost"))
+	require.Eventually(t, func() bool { return app.services["failing"].Status() == Error }, time.Second, 5*time.Millisecond)
+
+	require.NoError(t, app.Stop(context.Background()))
+	close(order)
+
+	var stopped []string
+	for name := range order {
+		stopped = append(stopped, name)
+	}
+	require.Len(t, stopped, 5)
+	assert.Equal(t, "dep", stopped[4], "dep should be stopped after its failed dependent")
+	assert.Equal(t, Stopped, app.services["failing"].Status())
+}
@@ -0,0 +1,34 @@
+package svc
+
+import (
+	"sync"
+)
+
+// ErrorReporter is an embeddable struct that implements Fallible.
+// Services can embed this to easily report fatal runtime errors to the App.
+type ErrorReporter struct {
+	once sync.Once
+	errs chan error
+}
+
+func (r *ErrorReporter) init() {
+	r.once.Do(func() {
+		r.errs = make(chan error, 1)
+	})
+}
+
+// ReportError reports a fatal runtime error
+// It never blocks: if an error is already pending, the new error is dropped
+func (r *ErrorReporter) ReportError(err error) {
+	r.init()
+	select {
+	case r.errs <- err:
+	default:
+	}
+}
+
+// Errors returns the channel on which errors are reported
+func (r *ErrorReporter) Errors() <-chan error {
+	r.init()
+	return r.errs
+}
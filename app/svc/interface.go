@@ -7,6 +7,7 @@ import (
 	"github.com/justinas/alice"
 	"github.com/nmvalera/go-utils/tag"
 	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc"
 )
 
 // API is a service that exposes API routes
@@ -14,6 +15,13 @@ type API interface {
 	RegisterHandler(mux *mux.Router)
 }
 
+// GRPCAPI is a service that exposes gRPC services
+type GRPCAPI interface {
+	// RegisterGRPCService registers the gRPC services on the gRPC entrypoint of the App
+	// (e.g. pb.RegisterGreeterServer(registrar, s))
+	RegisterGRPCService(registrar grpc.ServiceRegistrar)
+}
+
 // Healthz is a service that exposes healthz routes
 type Healthz interface {
 	RegisterHealthzHandler(mux *mux.Router)
@@ -25,7 +33,8 @@ type Runnable interface {
 	// It SHOULD return an error if the service can not start successfully
 	// In case the context is canceled or times out, the service SHOULD return an error ASAP
 	//
-	// App ensures that Start is called only once
+	// App ensures that Start is called only once, unless the service is restarted by its restart policy
+	// (in which case Start is called again after Stop)
 	// App ensures that all service's dependencies have been successfully started before calling Start
 	Start(context.Context) error
 
@@ -33,10 +42,32 @@ type Runnable interface {
 	// It SHOULD attempt to gracefully stop and clean its internal state and return an error if it can not do so
 	// In case the context is canceled or times out, the service should return an error ASAP
 	//
-	// App ensures that Stop is called only once
+	// App ensures that Stop is called only once per successful Start
 	Stop(context.Context) error
 }
 
+// Drainable is a Runnable service that can stop accepting new work before being stopped
+type Drainable interface {
+	// Drain stops accepting new work (e.g. new connections) and waits for in-flight work to complete
+	// In case the context is canceled or times out, the service SHOULD return an error ASAP
+	//
+	// Drain is called by the App on shutdown, after the drain delay and before calling Stop
+	// App calls Drain on dependents before calling it on their dependencies
+	Drain(ctx context.Context) error
+}
+
+// Fallible is a Runnable service whose long living task(s) can fail after Start returned successfully
+//
+// The App supervises Fallible services provided with a restart policy: when an error is published on the channel,
+// the App applies the restart policy of the service (restart the service and its dependents, or shut down the app)
+type Fallible interface {
+	// Errors returns a channel on which the service publishes fatal runtime errors
+	// nil errors are ignored
+	//
+	// Errors is called after each successful Start
+	Errors() <-chan error
+}
+
 // Checkable is a service that can expose its health status
 type Checkable interface {
 	// Ready should return nil if the service is ready to accept traffic
@@ -75,6 +106,15 @@ type RunContextAware interface {
 	SetRunContext(ctx context.Context)
 }
 
+// Reloadable is a service that can apply configuration changes at runtime
+type Reloadable interface {
+	// Reload is called by the App after its configuration has been reloaded (see App.Reload)
+	// The context holds the logger and the reloaded tags
+	//
+	// Reload is called only when the service is Running, after its dependencies have been reloaded
+	Reload(ctx context.Context) error
+}
+
 // Middleware is a service that exposes a middleware to be set on an App
 type Middleware interface {
 	RegisterMiddleware(chain alice.Chain) alice.Chain
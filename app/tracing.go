@@ -0,0 +1,57 @@
+package app
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.uber.org/zap"
+)
+
+// setTracerProvider creates the TracerProvider from Tracing in Config
+// unless a TracerProvider has been set with WithTracerProvider
+func (app *App) setTracerProvider() error {
+	if app.tracerProvider != nil || !app.cfg.Tracing.Enabled() {
+		return nil
+	}
+
+	tp, err := app.cfg.Tracing.TracerProvider(
+		sdktrace.WithResource(resource.NewSchemaless(
+			semconv.ServiceName(app.name),
+			semconv.ServiceVersion(app.version),
+		)),
+	)
+	if err != nil {
+		return err
+	}
+	app.tracerProvider = tp
+	app.shutdownTracerProvider = tp.Shutdown
+
+	return nil
+}
+
+// replaceTracerProvider sets the app TracerProvider as the global one,
+// so spans created by the instrumented packages (see tracing.StartSpan) are exported with it
+func (app *App) replaceTracerProvider() {
+	if app.tracerProvider == nil {
+		return
+	}
+	otel.SetTracerProvider(app.tracerProvider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+}
+
+// shutdownTracing flushes remaining spans and releases the exporter of the TracerProvider created from Config
+func (app *App) shutdownTracing(ctx context.Context) {
+	if app.shutdownTracerProvider == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), app.stopTimeout())
+	defer cancel()
+	if err := app.shutdownTracerProvider(ctx); err != nil {
+		app.logger.Error("Failed to shutdown tracer provider", zap.Error(err))
+	}
+}
@@ -0,0 +1,74 @@
+package app
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+type tracedService struct{}
+
+func (s *tracedService) Start(_ context.Context) error { return nil }
+func (s *tracedService) Stop(_ context.Context) error  { return nil }
+
+func (s *tracedService) RegisterHandler(router *mux.Router) {
+	router.Path("/test").Methods(http.MethodGet).HandlerFunc(func(rw http.ResponseWriter, _ *http.Request) {
+		rw.WriteHeader(http.StatusOK)
+	})
+}
+
+func TestTracing(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	app := newTestApp(t)
+	require.NoError(t, WithTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))(app))
+
+	Provide(app, "main", func() (*tracedService, error) {
+		app.EnableMainEntrypoint()
+		return new(tracedService), nil
+	})
+
+	require.NoError(t, app.Start(context.Background()))
+
+	resp, err := http.Get("http://" + app.main.Addr() + "/test")
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+
+	require.NoError(t, app.Stop(context.Background()))
+
+	spans := make(map[string]sdktrace.ReadOnlySpan)
+	for _, span := range recorder.Ended() {
+		// The main entrypoint service is also started and stopped, we only keep the spans of the main service
+		isService := span.Name() == "service.start" || span.Name() == "service.stop"
+		if isService && !hasAttribute(span, attribute.String("component", "main")) {
+			continue
+		}
+		spans[span.Name()] = span
+	}
+	require.Contains(t, spans, "app.start")
+	require.Contains(t, spans, "service.start")
+	require.Contains(t, spans, "app.stop")
+	require.Contains(t, spans, "service.stop")
+	require.Contains(t, spans, "HTTP GET")
+
+	assert.True(t, hasAttribute(spans["app.start"], attribute.String("app", "test")))
+	assert.True(t, hasAttribute(spans["app.start"], attribute.String("version", "1.0.0")))
+	assert.Equal(t, spans["app.start"].SpanContext().SpanID(), spans["service.start"].Parent().SpanID())
+	assert.Equal(t, spans["app.stop"].SpanContext().SpanID(), spans["service.stop"].Parent().SpanID())
+	assert.True(t, hasAttribute(spans["HTTP GET"], attribute.String("url.path", "/test")))
+}
+
+func hasAttribute(span sdktrace.ReadOnlySpan, attr attribute.KeyValue) bool {
+	for _, a := range span.Attributes() {
+		if a == attr {
+			return true
+		}
+	}
+	return false
+}
@@ -1,44 +1,31 @@
 package main
 
 import (
-	"context"
-	"fmt"
+	"os"
 
 	"github.com/nmvalera/go-utils/app"
-	"github.com/nmvalera/go-utils/config"
-	"github.com/spf13/pflag"
+	"github.com/nmvalera/go-utils/app/cli"
 )
 
 type MyService struct {
 }
 
 func main() {
-	v := config.NewViper()
-	_ = app.AddFlags(v, pflag.NewFlagSet("test", pflag.ContinueOnError))
-
-	cfg := new(app.Config)
-	err := cfg.Unmarshal(v)
-	if err != nil {
-		panic(fmt.Sprintf("Failed to unmarshal config: %v", err))
-	}
-
-	a, err := app.NewApp(
-		cfg,
-		app.WithName("my-app"),
-		app.WithVersion("1.0.0"),
+	cmd := cli.NewCommand(
+		"my-app",
+		func() *struct{} { return &struct{}{} },
+		func(a *app.App, _ *struct{}) error {
+			app.Provide(a, "my-service", func() (*MyService, error) {
+				a.EnableMainEntrypoint()
+				a.EnableHealthzEntrypoint()
+				return &MyService{}, nil
+			})
+			return nil
+		},
+		cli.WithVersion("1.0.0"),
 	)
-	if err != nil {
-		panic(fmt.Sprintf("Failed to create app: %v", err))
-	}
-
-	app.Provide(a, "my-service", func() (*MyService, error) {
-		a.EnableMainEntrypoint()
-		a.EnableHealthzEntrypoint()
-		return &MyService{}, nil
-	})
 
-	err = a.Run(context.Background())
-	if err != nil {
-		panic(fmt.Sprintf("Failed to run app: %v", err))
+	if err := cmd.Execute(); err != nil {
+		os.Exit(1)
 	}
 }
@@ -0,0 +1,327 @@
+package job
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"runtime/debug"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/nmvalera/go-utils/app/svc"
+	"github.com/nmvalera/go-utils/log"
+	"github.com/nmvalera/go-utils/tag"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+)
+
+// Func is the function run by a Job
+type Func func(ctx context.Context) error
+
+// OverlapPolicy defines what happens when a run is due while the previous one is still running
+type OverlapPolicy int
+
+const (
+	// SkipIfRunning skips the run if the previous one is still running (default)
+	SkipIfRunning OverlapPolicy = iota
+	// AllowConcurrent starts the run even if the previous one is still running
+	AllowConcurrent
+)
+
+// Job is a service running a function on a Schedule
+//
+// It implements svc.Runnable and svc.Metricable so it can be provided to an app.App, e.g.
+//
+//	app.Provide("cleanup", func() (*job.Job, error) {
+//		return job.New(job.Every(time.Minute), cleanup, job.WithRunTimeout(30*time.Second)), nil
+//	})
+//
+// Runs are executed with the app run context, so they hold the app logger and tags.
+// Errors and panics of a run are logged and counted, they do not stop the job.
+type Job struct {
+	schedule Schedule
+	fn       Func
+
+	jitter     time.Duration
+	overlap    OverlapPolicy
+	runTimeout time.Duration
+	runOnStart bool
+
+	// mux protects stop, which is nil while the job is not started
+	mux      sync.Mutex
+	stop     chan struct{}
+	loopDone chan struct{}
+	runs     sync.WaitGroup
+	running  atomic.Bool
+
+	// runsCtx is the parent context of the runs, it is canceled if Stop times out
+	runsCtx    context.Context
+	cancelRuns context.CancelFunc
+
+	lastSuccess prometheus.Gauge
+	duration    prometheus.Histogram
+	runsTotal   prometheus.Counter
+	failures    prometheus.Counter
+	skipped     prometheus.Counter
+
+	*svc.RunContext
+}
+
+type Option func(*Job)
+
+// WithJitter delays each run by a random duration in [0, jitter)
+// It avoids many instances of an app running the same job at the exact same time
+func WithJitter(jitter time.Duration) Option {
+	return func(j *Job) {
+		j.jitter = jitter
+	}
+}
+
+// WithOverlapPolicy sets the policy applied when a run is due while the previous one is still running
+func WithOverlapPolicy(policy OverlapPolicy) Option {
+	return func(j *Job) {
+		j.overlap = policy
+	}
+}
+
+// WithRunTimeout sets the maximum duration of each run (zero means no timeout)
+func WithRunTimeout(timeout time.Duration) Option {
+	return func(j *Job) {
+		j.runTimeout = timeout
+	}
+}
+
+// WithRunOnStart runs the job right after Start, instead of waiting for the first activation of the schedule
+func WithRunOnStart() Option {
+	return func(j *Job) {
+		j.runOnStart = true
+	}
+}
+
+// New creates a Job running fn on the given schedule
+func New(schedule Schedule, fn Func, opts ...Option) *Job {
+	j := &Job{
+		schedule:   schedule,
+		fn:         fn,
+		RunContext: &svc.RunContext{},
+	}
+	for _, opt := range opts {
+		opt(j)
+	}
+
+	// Metrics are replaced when the job is provided to an app (see SetMetrics)
+	j.SetMetrics("", "")
+
+	return j
+}
+
+// Start starts scheduling the runs of the job
+func (j *Job) Start(ctx context.Context) error {
+	log.LoggerFromContext(ctx).Info("Job starting...")
+
+	runCtx := j.Context()
+	if j.RunContext.Context() == context.Background() {
+		// Not provided to an app, runs keep the values (logger, tags) of the start context
+		runCtx = context.WithoutCancel(ctx)
+	}
+	j.mux.Lock()
+	defer j.mux.Unlock()
+	if j.stop != nil {
+		return fmt.Errorf("job already started")
+	}
+
+	j.runsCtx, j.cancelRuns = context.WithCancel(runCtx)
+	j.stop = make(chan struct{})
+	j.loopDone = make(chan struct{})
+	go j.loop(j.stop, j.loopDone)
+
+	return nil
+}
+
+// Stop stops scheduling runs and waits for in-flight runs to complete
+// If the context is done before in-flight runs complete, their context is canceled
+// and Stop returns the context error without waiting for them (a run may ignore its context)
+//
+// Stopping a job which is not started does nothing
+func (j *Job) Stop(ctx context.Context) error {
+	j.mux.Lock()
+	stop, loopDone := j.stop, j.loopDone
+	j.stop = nil
+	j.mux.Unlock()
+	if stop == nil {
+		return nil
+	}
+
+	logger := log.LoggerFromContext(ctx)
+	logger.Info("Job stopping...")
+
+	close(stop)
+	<-loopDone
+
+	done := make(chan struct{})
+	go func() {
+		j.runs.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		j.cancelRuns()
+		return nil
+	case <-ctx.Done():
+		j.cancelRuns()
+		logger.Warn("Job stopped before in-flight runs completed", zap.Error(ctx.Err()))
+		return ctx.Err()
+	}
+}
+
+func (j *Job) loop(stop, loopDone chan struct{}) {
+	defer close(loopDone)
+
+	if j.runOnStart {
+		j.trigger()
+	}
+
+	next := j.schedule.Next(time.Now())
+	for !next.IsZero() {
+		delay := time.Until(next)
+		if j.jitter > 0 {
+			delay += rand.N(j.jitter)
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-stop:
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		j.trigger()
+
+		// Schedule from the previous activation time to avoid drifting,
+		// unless activations have been missed (e.g. the host was suspended)
+		next = j.schedule.Next(next)
+		if now := time.Now(); !next.IsZero() && next.Before(now) {
+			next = j.schedule.Next(now)
+		}
+	}
+
+	log.LoggerFromContext(j.runsCtx).Warn("Job schedule has no next activation")
+	<-stop
+}
+
+// trigger starts a run in the background, applying the overlap policy
+func (j *Job) trigger() {
+	if j.overlap == SkipIfRunning && !j.running.CompareAndSwap(false, true) {
+		j.skipped.Inc()
+		log.LoggerFromContext(j.runsCtx).Warn("Job run skipped as the previous one is still running")
+		return
+	}
+
+	j.runs.Add(1)
+	go func() {
+		defer j.runs.Done()
+		if j.overlap == SkipIfRunning {
+			defer j.running.Store(false)
+		}
+		j.run()
+	}()
+}
+
+func (j *Job) run() {
+	ctx := j.runsCtx
+	if j.runTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, j.runTimeout)
+		defer cancel()
+	}
+
+	logger := log.LoggerFromContext(ctx)
+	logger.Debug("Job run starting...")
+
+	begin := time.Now()
+	err := j.safeRun(ctx)
+	duration := time.Since(begin)
+
+	j.runsTotal.Inc()
+	j.duration.Observe(duration.Seconds())
+	if err != nil {
+		j.failures.Inc()
+		logger.Error("Job run failed", zap.Error(err), zap.Duration("duration", duration))
+		return
+	}
+
+	j.lastSuccess.SetToCurrentTime()
+	logger.Debug("Job run succeeded", zap.Duration("duration", duration))
+}
+
+// safeRun runs the job function converting a panic into an error
+func (j *Job) safeRun(ctx context.Context) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("job panicked: %v\n%s", r, debug.Stack())
+		}
+	}()
+
+	return j.fn(ctx)
+}
+
+// SetMetrics sets the metrics of the job, tags are attached to all metrics as const labels
+func (j *Job) SetMetrics(system, subsystem string, tags ...*tag.Tag) {
+	constLabels := prometheus.Labels(tag.Labels(tags...))
+
+	j.lastSuccess = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace:   system,
+		Subsystem:   subsystem,
+		Name:        "last_success_timestamp_seconds",
+		Help:        "Unix timestamp of the last successful run of the job",
+		ConstLabels: constLabels,
+	})
+	j.duration = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Namespace:   system,
+		Subsystem:   subsystem,
+		Name:        "run_duration_seconds",
+		Help:        "Duration of the runs of the job in seconds",
+		ConstLabels: constLabels,
+		Buckets:     prometheus.DefBuckets,
+	})
+	j.runsTotal = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace:   system,
+		Subsystem:   subsystem,
+		Name:        "runs_total",
+		Help:        "Total number of runs of the job",
+		ConstLabels: constLabels,
+	})
+	j.failures = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace:   system,
+		Subsystem:   subsystem,
+		Name:        "failures_total",
+		Help:        "Total number of failed runs of the job (including panics and timeouts)",
+		ConstLabels: constLabels,
+	})
+	j.skipped = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace:   system,
+		Subsystem:   subsystem,
+		Name:        "skipped_total",
+		Help:        "Total number of runs skipped because the previous run was still running",
+		ConstLabels: constLabels,
+	})
+}
+
+func (j *Job) Describe(ch chan<- *prometheus.Desc) {
+	j.lastSuccess.Describe(ch)
+	j.duration.Describe(ch)
+	j.runsTotal.Describe(ch)
+	j.failures.Describe(ch)
+	j.skipped.Describe(ch)
+}
+
+func (j *Job) Collect(ch chan<- prometheus.Metric) {
+	j.lastSuccess.Collect(ch)
+	j.duration.Collect(ch)
+	j.runsTotal.Collect(ch)
+	j.failures.Collect(ch)
+	j.skipped.Collect(ch)
+}
@@ -0,0 +1,184 @@
+package job
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/nmvalera/go-utils/tag"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJob(t *testing.T) {
+	var count atomic.Int32
+	j := New(Every(20*time.Millisecond), func(_ context.Context) error {
+		if count.Add(1) == 2 {
+			return errors.New("test error")
+		}
+		return nil
+	})
+
+	require.NoError(t, j.Start(context.Background()))
+	require.Eventually(t, func() bool { return count.Load() >= 3 }, time.Second, 5*time.Millisecond)
+	require.NoError(t, j.Stop(context.Background()))
+
+	runs := count.Load()
+	assert.Equal(t, float64(runs), testutil.ToFloat64(j.runsTotal))
+	assert.Equal(t, float64(1), testutil.ToFloat64(j.failures))
+	assert.Positive(t, testutil.ToFloat64(j.lastSuccess))
+
+	// No more runs after Stop
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, runs, count.Load())
+
+	// Stopping again does nothing
+	require.NoError(t, j.Stop(context.Background()))
+}
+
+func TestJobStopNotStarted(t *testing.T) {
+	j := New(Every(time.Hour), func(_ context.Context) error { return nil })
+	require.NoError(t, j.Stop(context.Background()))
+}
+
+func TestJobRunOnStart(t *testing.T) {
+	ran := make(chan struct{}, 1)
+	j := New(Every(time.Hour), func(_ context.Context) error {
+		ran <- struct{}{}
+		return nil
+	}, WithRunOnStart())
+
+	require.NoError(t, j.Start(context.Background()))
+	defer func() { require.NoError(t, j.Stop(context.Background())) }()
+
+	select {
+	case <-ran:
+	case <-time.After(time.Second):
+		t.Fatal("job did not run on start")
+	}
+}
+
+func TestJobOverlapPolicy(t *testing.T) {
+	for _, test := range []struct {
+		policy      OverlapPolicy
+		concurrent  bool
+		skippedRuns bool
+	}{
+		{policy: SkipIfRunning, concurrent: false, skippedRuns: true},
+		{policy: AllowConcurrent, concurrent: true, skippedRuns: false},
+	} {
+		var running, maxRunning atomic.Int32
+		j := New(Every(10*time.Millisecond), func(ctx context.Context) error {
+			n := running.Add(1)
+			defer running.Add(-1)
+			for {
+				m := maxRunning.Load()
+				if n <= m || maxRunning.CompareAndSwap(m, n) {
+					break
+				}
+			}
+			select {
+			case <-time.After(50 * time.Millisecond):
+			case <-ctx.Done():
+			}
+			return nil
+		}, WithOverlapPolicy(test.policy))
+
+		require.NoError(t, j.Start(context.Background()))
+		time.Sleep(120 * time.Millisecond)
+		require.NoError(t, j.Stop(context.Background()))
+
+		assert.Equal(t, test.concurrent, maxRunning.Load() > 1)
+		assert.Equal(t, test.skippedRuns, testutil.ToFloat64(j.skipped) > 0)
+	}
+}
+
+func TestJobRunTimeoutAndPanic(t *testing.T) {
+	var count atomic.Int32
+	j := New(Every(10*time.Millisecond), func(ctx context.Context) error {
+		if count.Add(1) == 1 {
+			panic("test panic")
+		}
+		<-ctx.Done()
+		return ctx.Err()
+	}, WithRunTimeout(10*time.Millisecond))
+
+	require.NoError(t, j.Start(context.Background()))
+	require.Eventually(t, func() bool { return testutil.ToFloat64(j.failures) >= 2 }, time.Second, 5*time.Millisecond)
+	require.NoError(t, j.Stop(context.Background()))
+
+	assert.Zero(t, testutil.ToFloat64(j.lastSuccess))
+}
+
+func TestJobStopTimeout(t *testing.T) {
+	started := make(chan struct{})
+	var canceled atomic.Bool
+	j := New(Every(time.Hour), func(ctx context.Context) error {
+		close(started)
+		<-ctx.Done()
+		canceled.Store(true)
+		return ctx.Err()
+	}, WithRunOnStart())
+
+	require.NoError(t, j.Start(context.Background()))
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	require.ErrorIs(t, j.Stop(ctx), context.DeadlineExceeded)
+	assert.Eventually(t, canceled.Load, time.Second, 5*time.Millisecond, "The run context should be canceled")
+}
+
+func TestJobStopTimeoutRunIgnoringContext(t *testing.T) {
+	started, release := make(chan struct{}), make(chan struct{})
+	defer close(release)
+	j := New(Every(time.Hour), func(_ context.Context) error {
+		close(started)
+		<-release
+		return nil
+	}, WithRunOnStart())
+
+	require.NoError(t, j.Start(context.Background()))
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	stopped := make(chan error, 1)
+	go func() { stopped <- j.Stop(ctx) }()
+
+	select {
+	case err := <-stopped:
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+	case <-time.After(time.Second):
+		t.Fatal("Stop should return once its context is done")
+	}
+}
+
+func TestJobMetrics(t *testing.T) {
+	j := New(Every(time.Hour), func(_ context.Context) error { return nil })
+	j.SetMetrics("app", "cleanup", tag.Key("component").String("cleanup"))
+
+	reg := prometheus.NewRegistry()
+	require.NoError(t, reg.Register(j))
+
+	families, err := reg.Gather()
+	require.NoError(t, err)
+	for _, family := range families {
+		require.Len(t, family.GetMetric()[0].GetLabel(), 1)
+		assert.Equal(t, "component", family.GetMetric()[0].GetLabel()[0].GetName(), "Tags should be attached as const labels")
+	}
+
+	count, err := testutil.GatherAndCount(reg,
+		"app_cleanup_last_success_timestamp_seconds",
+		"app_cleanup_run_duration_seconds",
+		"app_cleanup_runs_total",
+		"app_cleanup_failures_total",
+		"app_cleanup_skipped_total",
+	)
+	require.NoError(t, err)
+	assert.Equal(t, 5, count)
+}
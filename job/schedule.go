@@ -0,0 +1,277 @@
+package job
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schedule computes the activation times of a Job
+type Schedule interface {
+	// Next returns the next activation time strictly after t
+	// It returns the zero time if there is no activation time after t
+	Next(t time.Time) time.Time
+}
+
+type everySchedule struct {
+	interval time.Duration
+}
+
+// Every returns a Schedule activating at a fixed interval
+// It panics if interval is not positive
+func Every(interval time.Duration) Schedule {
+	if interval <= 0 {
+		panic("non-positive interval for job.Every")
+	}
+	return &everySchedule{interval: interval}
+}
+
+func (s *everySchedule) Next(t time.Time) time.Time {
+	return t.Add(s.interval)
+}
+
+// cronSchedule is a Schedule defined by a cron expression
+// Each field is a bit set of the allowed values
+type cronSchedule struct {
+	minute, hour, dom, month, dow uint64
+
+	// domStar and dowStar indicate the day of month (resp. day of week) field is "*"
+	domStar, dowStar bool
+
+	loc *time.Location
+}
+
+var cronDescriptors = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+type cronField struct {
+	name     string
+	min, max int
+	names    map[string]int
+}
+
+var (
+	minuteField = cronField{name: "minute", min: 0, max: 59}
+	hourField   = cronField{name: "hour", min: 0, max: 23}
+	domField    = cronField{name: "day of month", min: 1, max: 31}
+	monthField  = cronField{name: "month", min: 1, max: 12, names: map[string]int{
+		"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+		"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+	}}
+	// 7 is accepted for Sunday and mapped to 0
+	dowField = cronField{name: "day of week", min: 0, max: 7, names: map[string]int{
+		"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+	}}
+)
+
+// ParseCron parses a standard cron expression with 5 fields: minute, hour, day of month, month and day of week
+//
+// Fields support "*", values, ranges ("1-5"), lists ("1,3,5"), steps ("*/15", "0-30/10")
+// and names for months ("JAN") and days of week ("MON").
+// As in standard cron, if both day of month and day of week are restricted, a time matches if either field matches.
+//
+// It also supports the descriptors "@yearly", "@annually", "@monthly", "@weekly", "@daily", "@midnight", "@hourly"
+// and "@every <duration>" (e.g. "@every 1h30m").
+//
+// Times are computed in the location of the time passed to Next, unless the expression
+// is prefixed with "CRON_TZ=<location> " (e.g. "CRON_TZ=Europe/Paris 0 9 * * MON-FRI").
+func ParseCron(expr string) (Schedule, error) {
+	expr = strings.TrimSpace(expr)
+
+	var loc *time.Location
+	if strings.HasPrefix(expr, "CRON_TZ=") || strings.HasPrefix(expr, "TZ=") {
+		tz, rest, _ := strings.Cut(expr, " ")
+		_, name, _ := strings.Cut(tz, "=")
+		var err error
+		loc, err = time.LoadLocation(name)
+		if err != nil {
+			return nil, fmt.Errorf("invalid cron location %q: %w", name, err)
+		}
+		expr = strings.TrimSpace(rest)
+	}
+
+	if strings.HasPrefix(expr, "@every ") {
+		interval, err := time.ParseDuration(strings.TrimSpace(strings.TrimPrefix(expr, "@every ")))
+		if err != nil {
+			return nil, fmt.Errorf("invalid cron expression %q: %w", expr, err)
+		}
+		if interval <= 0 {
+			return nil, fmt.Errorf("invalid cron expression %q: non-positive interval", expr)
+		}
+		return Every(interval), nil
+	}
+
+	if descriptor, ok := cronDescriptors[strings.ToLower(expr)]; ok {
+		expr = descriptor
+	}
+
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("invalid cron expression %q: expected 5 fields, got %d", expr, len(fields))
+	}
+
+	s := &cronSchedule{
+		domStar: fields[2] == "*",
+		dowStar: fields[4] == "*",
+		loc:     loc,
+	}
+
+	var err error
+	for i, f := range []struct {
+		field *cronField
+		bits  *uint64
+	}{
+		{&minuteField, &s.minute},
+		{&hourField, &s.hour},
+		{&domField, &s.dom},
+		{&monthField, &s.month},
+		{&dowField, &s.dow},
+	} {
+		*f.bits, err = f.field.parse(fields[i])
+		if err != nil {
+			return nil, fmt.Errorf("invalid cron expression %q: %w", expr, err)
+		}
+	}
+
+	// Sunday can be written 7
+	if s.dow&(1<<7) != 0 {
+		s.dow = s.dow&^(1<<7) | 1
+	}
+
+	return s, nil
+}
+
+// MustParseCron is like ParseCron but panics if the expression can not be parsed
+func MustParseCron(expr string) Schedule {
+	s, err := ParseCron(expr)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+func (f *cronField) parse(expr string) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(expr, ",") {
+		b, err := f.parsePart(part)
+		if err != nil {
+			return 0, err
+		}
+		bits |= b
+	}
+	return bits, nil
+}
+
+func (f *cronField) parsePart(part string) (uint64, error) {
+	rng, stepStr, hasStep := strings.Cut(part, "/")
+
+	step := 1
+	if hasStep {
+		var err error
+		step, err = strconv.Atoi(stepStr)
+		if err != nil || step <= 0 {
+			return 0, fmt.Errorf("invalid step %q in %s field", stepStr, f.name)
+		}
+	}
+
+	var start, end int
+	switch {
+	case rng == "*":
+		start, end = f.min, f.max
+	case strings.Contains(rng, "-"):
+		startStr, endStr, _ := strings.Cut(rng, "-")
+		var err error
+		if start, err = f.parseValue(startStr); err != nil {
+			return 0, err
+		}
+		if end, err = f.parseValue(endStr); err != nil {
+			return 0, err
+		}
+		if start > end {
+			return 0, fmt.Errorf("invalid range %q in %s field", rng, f.name)
+		}
+	default:
+		var err error
+		if start, err = f.parseValue(rng); err != nil {
+			return 0, err
+		}
+		end = start
+		// "a/step" is a shorthand for "a-max/step"
+		if hasStep {
+			end = f.max
+		}
+	}
+
+	var bits uint64
+	for v := start; v <= end; v += step {
+		bits |= 1 << uint(v)
+	}
+	return bits, nil
+}
+
+func (f *cronField) parseValue(s string) (int, error) {
+	if v, ok := f.names[strings.ToLower(s)]; ok {
+		return v, nil
+	}
+
+	v, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value %q in %s field", s, f.name)
+	}
+	if v < f.min || v > f.max {
+		return 0, fmt.Errorf("value %d out of range [%d, %d] in %s field", v, f.min, f.max, f.name)
+	}
+	return v, nil
+}
+
+// maxCronYears bounds the search of the next activation time (e.g. "0 0 30 2 *" never activates)
+const maxCronYears = 5
+
+func (s *cronSchedule) Next(t time.Time) time.Time {
+	origLoc := t.Location()
+	loc := origLoc
+	if s.loc != nil {
+		loc = s.loc
+	}
+
+	t = t.In(loc).Truncate(time.Minute).Add(time.Minute)
+	yearLimit := t.Year() + maxCronYears
+	for t.Year() <= yearLimit {
+		if s.month&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
+			continue
+		}
+		if !s.dayMatches(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+			continue
+		}
+		if s.hour&(1<<uint(t.Hour())) == 0 {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc)
+			continue
+		}
+		if s.minute&(1<<uint(t.Minute())) == 0 {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t.In(origLoc)
+	}
+
+	return time.Time{}
+}
+
+func (s *cronSchedule) dayMatches(t time.Time) bool {
+	domMatch := s.dom&(1<<uint(t.Day())) != 0
+	dowMatch := s.dow&(1<<uint(t.Weekday())) != 0
+	if s.domStar || s.dowStar {
+		return domMatch && dowMatch
+	}
+	return domMatch || dowMatch
+}
@@ -0,0 +1,76 @@
+package job
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCronNext(t *testing.T) {
+	// Wednesday
+	from := time.Date(2025, time.January, 15, 10, 30, 15, 0, time.UTC)
+
+	tests := []struct {
+		expr     string
+		expected time.Time
+	}{
+		{"* * * * *", time.Date(2025, time.January, 15, 10, 31, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2025, time.January, 15, 10, 45, 0, 0, time.UTC)},
+		{"0 * * * *", time.Date(2025, time.January, 15, 11, 0, 0, 0, time.UTC)},
+		{"0 9 * * *", time.Date(2025, time.January, 16, 9, 0, 0, 0, time.UTC)},
+		{"0 9 * * MON-FRI", time.Date(2025, time.January, 16, 9, 0, 0, 0, time.UTC)},
+		{"0 9 * * sat,sun", time.Date(2025, time.January, 18, 9, 0, 0, 0, time.UTC)},
+		{"0 0 * * 7", time.Date(2025, time.January, 19, 0, 0, 0, 0, time.UTC)},
+		{"0 0 1 * *", time.Date(2025, time.February, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 0 29 2 *", time.Date(2028, time.February, 29, 0, 0, 0, 0, time.UTC)},
+		{"30 10-12/2 * * *", time.Date(2025, time.January, 15, 12, 30, 0, 0, time.UTC)},
+		{"5/20 10 * * *", time.Date(2025, time.January, 15, 10, 45, 0, 0, time.UTC)},
+		// Day of month and day of week are OR-ed when both are restricted
+		{"0 0 1 * FRI", time.Date(2025, time.January, 17, 0, 0, 0, 0, time.UTC)},
+		{"@daily", time.Date(2025, time.January, 16, 0, 0, 0, 0, time.UTC)},
+		{"@hourly", time.Date(2025, time.January, 15, 11, 0, 0, 0, time.UTC)},
+		{"@monthly", time.Date(2025, time.February, 1, 0, 0, 0, 0, time.UTC)},
+		{"@yearly", time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC)},
+		{"@every 1h30m", time.Date(2025, time.January, 15, 12, 0, 15, 0, time.UTC)},
+		{"CRON_TZ=Asia/Tokyo 0 9 * * *", time.Date(2025, time.January, 16, 0, 0, 0, 0, time.UTC)},
+		// February 30th never happens
+		{"0 0 30 2 *", time.Time{}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.expr, func(t *testing.T) {
+			s, err := ParseCron(test.expr)
+			require.NoError(t, err)
+			assert.True(t, test.expected.Equal(s.Next(from)), "expected %v, got %v", test.expected, s.Next(from))
+		})
+	}
+}
+
+func TestParseCronErrors(t *testing.T) {
+	for _, expr := range []string{
+		"",
+		"* * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"*/0 * * * *",
+		"10-5 * * * *",
+		"* * * FOO *",
+		"@every",
+		"@every -1s",
+		"CRON_TZ=Invalid/Zone * * * * *",
+	} {
+		_, err := ParseCron(expr)
+		assert.Error(t, err, expr)
+	}
+}
+
+func TestEvery(t *testing.T) {
+	from := time.Date(2025, time.January, 15, 10, 30, 15, 0, time.UTC)
+	assert.Equal(t, from.Add(time.Minute), Every(time.Minute).Next(from))
+	assert.Panics(t, func() { Every(0) })
+}
@@ -0,0 +1,158 @@
+package jsonrpc
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+)
+
+// WithAutoBatch automatically coalesces concurrent JSON-RPC calls into batches
+//
+// Calls are collected during window, starting with the first pending call, and then sent in a single batch
+// (a single pending call is sent as is). If maxSize > 0, pending calls are sent as soon as maxSize of them are collected.
+//
+// Requests of a batch are sent with batch-local IDs, so callers do not need unique IDs.
+// If the batch fails as a whole (e.g. transport failure), all its calls fail with the batch error.
+func WithAutoBatch(window time.Duration, maxSize int) ClientDecorator {
+	return func(c Client) Client {
+		b := &autoBatcher{
+			client:  c,
+			window:  window,
+			maxSize: maxSize,
+		}
+		return &batchClient{
+			call: b.call,
+			batch: func(ctx context.Context, elems []*BatchElem) error {
+				return BatchCall(ctx, c, elems)
+			},
+		}
+	}
+}
+
+// autoBatchCall is a call pending in an autoBatcher
+type autoBatchCall struct {
+	ctx  context.Context
+	elem *BatchElem
+	done chan error // receives the error of the batch, if any
+}
+
+type autoBatcher struct {
+	client  Client
+	window  time.Duration
+	maxSize int
+
+	mux     sync.Mutex
+	pending []*autoBatchCall
+	timer   *time.Timer
+}
+
+func (b *autoBatcher) call(ctx context.Context, req *Request, res any) error {
+	// The result is buffered, so it can not be written into res after call returned (e.g. on context cancellation)
+	raw := new(json.RawMessage)
+	call := &autoBatchCall{
+		ctx:  ctx,
+		elem: &BatchElem{Req: req, Result: raw},
+		done: make(chan error, 1),
+	}
+
+	b.mux.Lock()
+	b.pending = append(b.pending, call)
+	switch {
+	case b.maxSize > 0 && len(b.pending) >= b.maxSize:
+		calls := b.takePending()
+		b.mux.Unlock()
+		go b.send(calls)
+	case len(b.pending) == 1:
+		b.timer = time.AfterFunc(b.window, b.flush)
+		b.mux.Unlock()
+	default:
+		b.mux.Unlock()
+	}
+
+	select {
+	case err := <-call.done:
+		if err != nil {
+			return err
+		}
+		if call.elem.Error != nil {
+			return call.elem.Error
+		}
+		if res == nil || len(*raw) == 0 {
+			return nil
+		}
+		return json.Unmarshal(*raw, res)
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// takePending returns the pending calls and resets the batch, it MUST be called with the lock held
+func (b *autoBatcher) takePending() []*autoBatchCall {
+	calls := b.pending
+	b.pending = nil
+	if b.timer != nil {
+		b.timer.Stop()
+		b.timer = nil
+	}
+	return calls
+}
+
+// flush sends the pending calls once the window has elapsed
+func (b *autoBatcher) flush() {
+	b.mux.Lock()
+	calls := b.takePending()
+	b.mux.Unlock()
+
+	if len(calls) > 0 {
+		b.send(calls)
+	}
+}
+
+func (b *autoBatcher) send(calls []*autoBatchCall) {
+	if len(calls) == 1 {
+		call := calls[0]
+		call.elem.Error = b.client.Call(call.ctx, call.elem.Req, call.elem.Result)
+		call.done <- nil
+		return
+	}
+
+	// Responses of a batch are correlated by ID, so the requests are sent with batch-local IDs
+	// and the responses are mapped back to the calls by position
+	elems := make([]*BatchElem, len(calls))
+	for i, call := range calls {
+		req := *call.elem.Req
+		req.ID = i
+		elems[i] = &BatchElem{Req: &req, Result: call.elem.Result}
+	}
+
+	ctx, cancel := batchContext(calls)
+	defer cancel()
+
+	err := BatchCall(ctx, b.client, elems)
+	for i, call := range calls {
+		call.elem.Error = elems[i].Error
+		call.done <- err
+	}
+}
+
+// batchContext returns the context to send a batch with
+//
+// The batch is not canceled by the context of any single call, it inherits the values of the first call
+// and the latest deadline of the calls (if all calls have one).
+func batchContext(calls []*autoBatchCall) (context.Context, context.CancelFunc) {
+	ctx := context.WithoutCancel(calls[0].ctx)
+
+	var latest time.Time
+	for _, call := range calls {
+		deadline, ok := call.ctx.Deadline()
+		if !ok {
+			return ctx, func() {}
+		}
+		if deadline.After(latest) {
+			latest = deadline
+		}
+	}
+
+	return context.WithDeadline(ctx, latest)
+}
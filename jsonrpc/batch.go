@@ -0,0 +1,116 @@
+package jsonrpc
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// BatchElem is a JSON-RPC call of a batch
+type BatchElem struct {
+	Req *Request
+
+	// Result is where the JSON-RPC result is unmarshalled into
+	// It MUST be a pointer or nil, in which case the result is ignored
+	Result any
+
+	// Error is set after the batch call if the call failed
+	// (JSON-RPC error, missing response or result that could not be unmarshalled)
+	Error error
+}
+
+// BatchCall performs the JSON-RPC calls of the given elements with the given client
+//
+// If the client is a BatchClient the calls are sent in a single batch, otherwise they are performed concurrently.
+func BatchCall(ctx context.Context, c Client, elems []*BatchElem) error {
+	if bc, ok := c.(BatchClient); ok {
+		return bc.BatchCall(ctx, elems)
+	}
+
+	var wg sync.WaitGroup
+	for _, elem := range elems {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			elem.Error = c.Call(ctx, elem.Req, elem.Result)
+		}()
+	}
+	wg.Wait()
+
+	return nil
+}
+
+// batchClient is a BatchClient made of functions, it allows decorators to support batches
+type batchClient struct {
+	call  ClientFunc
+	batch BatchClientFunc
+}
+
+func (c *batchClient) Call(ctx context.Context, req *Request, res any) error {
+	return c.call(ctx, req, res)
+}
+
+func (c *batchClient) BatchCall(ctx context.Context, elems []*BatchElem) error {
+	return c.batch(ctx, elems)
+}
+
+// NormalizeBatchIDs normalizes the IDs of the requests of the given elements (see NormalizeID)
+// It returns an error if an ID is missing or duplicated, as responses of a batch are correlated by ID.
+func NormalizeBatchIDs(elems []*BatchElem) error {
+	seen := make(map[any]bool, len(elems))
+	for _, elem := range elems {
+		if elem.Req.ID == nil {
+			return fmt.Errorf("missing request ID for method %q", elem.Req.Method)
+		}
+
+		id, err := NormalizeID(elem.Req.ID)
+		if err != nil {
+			return err
+		}
+		if seen[id] {
+			return fmt.Errorf("duplicated request ID %v", id)
+		}
+		seen[id] = true
+		elem.Req.ID = id
+	}
+	return nil
+}
+
+// SetBatchResponses sets the results and errors of the given elements from the response messages of the batch
+// Responses are correlated to elements by ID, elements without a response fail.
+func SetBatchResponses(elems []*BatchElem, msgs []*ResponseMsg) {
+	resps := make(map[any]*ResponseMsg, len(msgs))
+	for _, msg := range msgs {
+		id, err := NormalizeID(msg.ID)
+		if err != nil {
+			continue
+		}
+		resps[id] = msg
+	}
+
+	for _, elem := range elems {
+		msg, ok := resps[elem.Req.ID]
+		if !ok {
+			elem.Error = fmt.Errorf("missing JSON-RPC response for request ID %v", elem.Req.ID)
+			continue
+		}
+		elem.Error = msg.Unmarshal(elem.Result)
+	}
+}
+
+// NormalizeID validates an ID as received on the wire and translates it to a normalized ID appropriate for keying
+// (numbers are decoded as float64 from JSON)
+func NormalizeID(id any) (any, error) {
+	switch v := id.(type) {
+	case string, float64, nil:
+		return v, nil
+	case int64:
+		return float64(v), nil
+	case int:
+		return float64(v), nil
+	case uint32:
+		return float64(v), nil
+	default:
+		return nil, fmt.Errorf("invalid id type: %T (must be one of string, float64, int64, int, uint32)", id)
+	}
+}
@@ -0,0 +1,179 @@
+package jsonrpc_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/nmvalera/go-utils/jsonrpc"
+	jsonrpcmock "github.com/nmvalera/go-utils/jsonrpc/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestBatchCallFallback(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// A client that does not support batches performs the calls concurrently
+	mockCli := jsonrpcmock.NewMockClient(ctrl)
+	elems := []*jsonrpc.BatchElem{
+		{Req: &jsonrpc.Request{ID: 0, Method: "ok"}},
+		{Req: &jsonrpc.Request{ID: 1, Method: "fail"}},
+	}
+	mockCli.EXPECT().Call(gomock.Any(), elems[0].Req, nil).Return(nil)
+	mockCli.EXPECT().Call(gomock.Any(), elems[1].Req, nil).Return(errors.New("test error"))
+
+	err := jsonrpc.BatchCall(context.Background(), mockCli, elems)
+	require.NoError(t, err)
+	assert.NoError(t, elems[0].Error)
+	assert.EqualError(t, elems[1].Error, "test error")
+}
+
+func TestSetBatchResponses(t *testing.T) {
+	res0, res1 := new(string), new(string)
+	elems := []*jsonrpc.BatchElem{
+		{Req: &jsonrpc.Request{ID: 0}, Result: res0},
+		{Req: &jsonrpc.Request{ID: "a"}, Result: res1},
+		{Req: &jsonrpc.Request{ID: uint32(2)}},
+	}
+	require.NoError(t, jsonrpc.NormalizeBatchIDs(elems))
+
+	jsonrpc.SetBatchResponses(elems, []*jsonrpc.ResponseMsg{
+		{ID: "a", Result: json.RawMessage(`"world"`)},
+		{ID: float64(0), Error: json.RawMessage(`{"code":-32601,"message":"method not found"}`)},
+	})
+
+	require.IsType(t, &jsonrpc.ErrorMsg{}, elems[0].Error)
+	assert.Equal(t, -32601, elems[0].Error.(*jsonrpc.ErrorMsg).Code)
+	assert.NoError(t, elems[1].Error)
+	assert.Equal(t, "world", *res1)
+	assert.Error(t, elems[2].Error, "Element without response should fail")
+}
+
+func TestNormalizeBatchIDs(t *testing.T) {
+	err := jsonrpc.NormalizeBatchIDs([]*jsonrpc.BatchElem{{Req: &jsonrpc.Request{Method: "test"}}})
+	assert.Error(t, err, "Missing ID should error")
+
+	err = jsonrpc.NormalizeBatchIDs([]*jsonrpc.BatchElem{
+		{Req: &jsonrpc.Request{ID: 1}},
+		{Req: &jsonrpc.Request{ID: uint32(1)}},
+	})
+	assert.Error(t, err, "Duplicated ID should error")
+}
+
+func TestDecoratorsBatchCall(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCli := jsonrpcmock.NewMockBatchClient(ctrl)
+	c := jsonrpc.WithIncrementalID()(jsonrpc.WithVersion("2.0")(mockCli))
+	require.Implements(t, (*jsonrpc.BatchClient)(nil), c)
+
+	elems := []*jsonrpc.BatchElem{
+		{Req: &jsonrpc.Request{Method: "test"}},
+		{Req: &jsonrpc.Request{Method: "test"}},
+	}
+	mockCli.EXPECT().BatchCall(gomock.Any(), elems).Return(nil)
+
+	err := jsonrpc.BatchCall(context.Background(), c, elems)
+	require.NoError(t, err)
+	for i, elem := range elems {
+		assert.Equal(t, "2.0", elem.Req.Version)
+		assert.Equal(t, uint32(i), elem.Req.ID)
+	}
+}
+
+func TestWithAutoBatch(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCli := jsonrpcmock.NewMockBatchClient(ctrl)
+	c := jsonrpc.WithIncrementalID()(jsonrpc.WithAutoBatch(50*time.Millisecond, 3)(mockCli))
+
+	respond := func(_ context.Context, elems []*jsonrpc.BatchElem) error {
+		if err := jsonrpc.NormalizeBatchIDs(elems); err != nil {
+			return err
+		}
+		msgs := make([]*jsonrpc.ResponseMsg, 0, len(elems))
+		for _, elem := range elems {
+			msgs = append(msgs, &jsonrpc.ResponseMsg{ID: elem.Req.ID, Result: json.RawMessage(`"` + elem.Req.Method + `"`)})
+		}
+		jsonrpc.SetBatchResponses(elems, msgs)
+		return nil
+	}
+
+	callConcurrently := func(t *testing.T, n int) []string {
+		var wg sync.WaitGroup
+		results := make([]string, n)
+		for i := range n {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				err := c.Call(context.Background(), &jsonrpc.Request{Method: string(rune('a' + i))}, &results[i])
+				assert.NoError(t, err)
+			}()
+		}
+		wg.Wait()
+		return results
+	}
+
+	t.Run("Window", func(t *testing.T) {
+		mockCli.EXPECT().BatchCall(gomock.Any(), gomock.Len(2)).DoAndReturn(respond)
+		assert.Equal(t, []string{"a", "b"}, callConcurrently(t, 2))
+	})
+
+	t.Run("MaxSize", func(t *testing.T) {
+		mockCli.EXPECT().BatchCall(gomock.Any(), gomock.Len(3)).DoAndReturn(respond)
+		assert.Equal(t, []string{"a", "b", "c"}, callConcurrently(t, 3))
+	})
+
+	t.Run("SingleCall", func(t *testing.T) {
+		mockCli.EXPECT().Call(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ context.Context, _ *jsonrpc.Request, res any) error {
+				*(res.(*json.RawMessage)) = json.RawMessage(`"single"`)
+				return nil
+			},
+		)
+		assert.Equal(t, []string{"single"}, callConcurrently(t, 1))
+	})
+
+	t.Run("ConstantID", func(t *testing.T) {
+		// Calls are batched with batch-local IDs, so callers do not need unique IDs
+		c := jsonrpc.WithAutoBatch(50*time.Millisecond, 2)(mockCli)
+		mockCli.EXPECT().BatchCall(gomock.Any(), gomock.Len(2)).DoAndReturn(respond)
+
+		var wg sync.WaitGroup
+		for _, method := range []string{"a", "b"} {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				req := &jsonrpc.Request{ID: 1, Method: method}
+				var res string
+				assert.NoError(t, c.Call(context.Background(), req, &res))
+				assert.Equal(t, method, res)
+				assert.Equal(t, 1, req.ID, "The ID of the caller should not be modified")
+			}()
+		}
+		wg.Wait()
+	})
+
+	t.Run("BatchError", func(t *testing.T) {
+		mockCli.EXPECT().BatchCall(gomock.Any(), gomock.Len(2)).Return(errors.New("transport error"))
+
+		var wg sync.WaitGroup
+		for range 2 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				err := c.Call(context.Background(), &jsonrpc.Request{Method: "test"}, nil)
+				assert.EqualError(t, err, "transport error")
+			}()
+		}
+		wg.Wait()
+	})
+}
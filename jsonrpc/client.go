@@ -17,3 +17,20 @@ type ClientFunc func(ctx context.Context, req *Request, res any) error
 func (f ClientFunc) Call(ctx context.Context, req *Request, res any) error {
 	return f(ctx, req, res)
 }
+
+// BatchClient is a Client capable of sending multiple JSON-RPC requests in a single batch
+type BatchClient interface {
+	Client
+
+	// BatchCall performs the JSON-RPC calls of the given elements in a single batch
+	//
+	// It returns an error only if the batch failed as a whole (e.g. transport failure),
+	// otherwise the error of each call is set on its element.
+	BatchCall(ctx context.Context, elems []*BatchElem) error
+}
+
+type BatchClientFunc func(ctx context.Context, elems []*BatchElem) error
+
+func (f BatchClientFunc) BatchCall(ctx context.Context, elems []*BatchElem) error {
+	return f(ctx, elems)
+}
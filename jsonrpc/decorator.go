@@ -18,10 +18,18 @@ type ClientDecorator func(Client) Client
 // WithVersion automatically set JSON-RPC request version
 func WithVersion(v string) ClientDecorator {
 	return func(c Client) Client {
-		return ClientFunc(func(ctx context.Context, req *Request, res any) error {
-			req.Version = v
-			return c.Call(ctx, req, res)
-		})
+		return &batchClient{
+			call: func(ctx context.Context, req *Request, res any) error {
+				req.Version = v
+				return c.Call(ctx, req, res)
+			},
+			batch: func(ctx context.Context, elems []*BatchElem) error {
+				for _, elem := range elems {
+					elem.Req.Version = v
+				}
+				return BatchCall(ctx, c, elems)
+			},
+		}
 	}
 }
 
@@ -29,69 +37,123 @@ func WithVersion(v string) ClientDecorator {
 func WithIncrementalID() ClientDecorator {
 	var idCounter uint32
 	return func(c Client) Client {
-		return ClientFunc(func(ctx context.Context, req *Request, res any) error {
-			req.ID = atomic.AddUint32(&idCounter, 1) - 1
-			return c.Call(ctx, req, res)
-		})
+		return &batchClient{
+			call: func(ctx context.Context, req *Request, res any) error {
+				req.ID = atomic.AddUint32(&idCounter, 1) - 1
+				return c.Call(ctx, req, res)
+			},
+			batch: func(ctx context.Context, elems []*BatchElem) error {
+				for _, elem := range elems {
+					elem.Req.ID = atomic.AddUint32(&idCounter, 1) - 1
+				}
+				return BatchCall(ctx, c, elems)
+			},
+		}
 	}
 }
 
 // WithExponentialBackOffRetry automatically retries JSON-RPC calls
+//
+// For batches, only failures of the batch as a whole (e.g. transport failures) are retried.
 func WithExponentialBackOffRetry(opts ...backoff.ExponentialBackOffOpts) ClientDecorator {
 	pool := &sync.Pool{
 		New: func() any {
 			return backoff.NewExponentialBackOff(opts...)
 		},
 	}
+
+	retry := func(ctx context.Context, op func(attempt int) error) error {
+		bckff := pool.Get().(*backoff.ExponentialBackOff)
+		defer func() {
+			bckff.Reset()
+			pool.Put(bckff)
+		}()
+
+		attempt := 0
+		return backoff.RetryNotify(
+			func() error {
+				return op(attempt)
+			},
+			backoff.WithContext(bckff, ctx),
+			func(err error, d time.Duration) {
+				attempt++
+				log.LoggerFromContext(ctx).Warn(
+					fmt.Sprintf("Call failed, retrying in %s...", d),
+					zap.Error(err),
+				)
+			},
+		)
+	}
+
 	return func(c Client) Client {
-		return ClientFunc(func(ctx context.Context, req *Request, res any) error {
-			bckff := pool.Get().(*backoff.ExponentialBackOff)
-			defer func() {
-				bckff.Reset()
-				pool.Put(bckff)
-			}()
+		return &batchClient{
+			call: func(ctx context.Context, req *Request, res any) error {
+				return retry(ctx, func(attempt int) error {
+					return c.Call(ctx, retryRequest(req, attempt), res)
+				})
+			},
+			batch: func(ctx context.Context, elems []*BatchElem) error {
+				return retry(ctx, func(attempt int) error {
+					if attempt == 0 {
+						return BatchCall(ctx, c, elems)
+					}
 
-			attempt := 0
-			attemptReq := req
-			return backoff.RetryNotify(
-				func() error {
-					return c.Call(ctx, attemptReq, res)
-				},
-				backoff.WithContext(bckff, ctx),
-				func(err error, d time.Duration) {
-					attempt++
-					// We need to increment the ID for each retry attempt
-					// so that we don't possibly overwrite the response of the previous attempt
-					attemptReq = &Request{
-						Method:  req.Method,
-						Version: req.Version,
-						Params:  req.Params,
-						ID:      fmt.Sprintf("%s#%d", req.ID, attempt),
+					attemptElems := make([]*BatchElem, len(elems))
+					for i, elem := range elems {
+						attemptElems[i] = &BatchElem{Req: retryRequest(elem.Req, attempt), Result: elem.Result}
+					}
+					if err := BatchCall(ctx, c, attemptElems); err != nil {
+						return err
 					}
-					log.LoggerFromContext(ctx).Warn(
-						fmt.Sprintf("Call failed, retrying in %s...", d),
-						zap.Error(err),
-					)
-				},
-			)
-		})
+					for i, elem := range elems {
+						elem.Error = attemptElems[i].Error
+					}
+					return nil
+				})
+			},
+		}
+	}
+}
+
+// retryRequest returns the request to send for the given retry attempt
+//
+// We need to increment the ID for each retry attempt
+// so that we don't possibly overwrite the response of the previous attempt
+func retryRequest(req *Request, attempt int) *Request {
+	if attempt == 0 {
+		return req
+	}
+	return &Request{
+		Method:  req.Method,
+		Version: req.Version,
+		Params:  req.Params,
+		ID:      fmt.Sprintf("%s#%d", req.ID, attempt),
 	}
 }
 
 // WithTimeout automatically sets a timeout for JSON-RPC calls
 func WithTimeout(d time.Duration) ClientDecorator {
-	return func(c Client) Client {
-		return ClientFunc(func(ctx context.Context, req *Request, res any) error {
-			deadline := time.Now().Add(d)
+	withTimeout := func(ctx context.Context, call func(context.Context) error) error {
+		deadline := time.Now().Add(d)
 
-			cancelCtx, cancel := context.WithDeadline(ctx, deadline)
-			defer cancel()
+		cancelCtx, cancel := context.WithDeadline(ctx, deadline)
+		defer cancel()
 
-			err := c.Call(cancelCtx, req, res)
-			if err != nil && time.Now().After(deadline) {
-				err = fmt.Errorf("jsonrpc: call timed out after %q: %w", d, err)
-			}
-			return err
-		})
+		err := call(cancelCtx)
+		if err != nil && time.Now().After(deadline) {
+			err = fmt.Errorf("jsonrpc: call timed out after %q: %w", d, err)
+		}
+		return err
+	}
+
+	return func(c Client) Client {
+		return &batchClient{
+			call: func(ctx context.Context, req *Request, res any) error {
+				return withTimeout(ctx, func(ctx context.Context) error { return c.Call(ctx, req, res) })
+			},
+			batch: func(ctx context.Context, elems []*BatchElem) error {
+				return withTimeout(ctx, func(ctx context.Context) error { return BatchCall(ctx, c, elems) })
+			},
+		}
 	}
 }
@@ -5,6 +5,18 @@ import (
 	"fmt"
 )
 
+// Standard JSON-RPC error codes
+const (
+	CodeParseError     = -32700
+	CodeInvalidRequest = -32600
+	CodeMethodNotFound = -32601
+	CodeInvalidParams  = -32602
+	CodeInternalError  = -32603
+
+	// CodeServerError is the code of implementation-defined server errors (reserved range is -32000 to -32099)
+	CodeServerError = -32000
+)
+
 // ErrorMsg is a struct allowing to encode/decode an error in a JSON-RPC response body
 type ErrorMsg struct {
 	Code    int              `json:"code"`
@@ -1,6 +1,7 @@
 package jsonrpchttp
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
@@ -77,6 +78,43 @@ func (c *Client) call(ctx context.Context, r *jsonrpc.Request, res interface{})
 	return nil
 }
 
+// BatchCall performs the JSON-RPC calls of the given elements in a single HTTP request with an array body
+//
+// Requests MUST have unique IDs (e.g. set with jsonrpc.WithIncrementalID) to correlate responses.
+func (c *Client) BatchCall(ctx context.Context, elems []*jsonrpc.BatchElem) error {
+	if len(elems) == 0 {
+		return nil
+	}
+
+	if err := jsonrpc.NormalizeBatchIDs(elems); err != nil {
+		return autorest.NewErrorWithError(err, "jsonrpchttp.Client", "BatchCall", nil, "PrepareRequest")
+	}
+
+	reqs := make([]*jsonrpc.Request, len(elems))
+	for i, elem := range elems {
+		reqs[i] = elem.Req
+	}
+
+	req, err := prepareBatchCallRequest(ctx, reqs)
+	if err != nil {
+		return autorest.NewErrorWithError(err, "jsonrpchttp.Client", "BatchCall", nil, "PrepareRequest")
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return autorest.NewErrorWithError(err, "jsonrpchttp.Client", "BatchCall", resp, "Do")
+	}
+
+	msgs, err := inspectBatchCallResponse(resp)
+	if err != nil {
+		return autorest.NewErrorWithError(err, "jsonrpchttp.Client", "BatchCall", resp, "Inspect Response")
+	}
+
+	jsonrpc.SetBatchResponses(elems, msgs)
+
+	return nil
+}
+
 // ByUnmarshallingResponse marshall JSON-RPC request message into http.Request body
 func prepareCallRequest(ctx context.Context, req *jsonrpc.Request) (*http.Request, error) {
 	return autorest.CreatePreparer(
@@ -87,6 +125,15 @@ func prepareCallRequest(ctx context.Context, req *jsonrpc.Request) (*http.Reques
 	).Prepare(newRequest(ctx))
 }
 
+func prepareBatchCallRequest(ctx context.Context, reqs []*jsonrpc.Request) (*http.Request, error) {
+	return autorest.CreatePreparer(
+		autorest.AsPost(),
+		autorest.WithPath("/"),
+		autorest.AsJSON(),
+		autorest.WithJSON(reqs),
+	).Prepare(newRequest(ctx))
+}
+
 func newRequest(ctx context.Context) *http.Request {
 	req, _ := http.NewRequestWithContext(ctx, "", "", http.NoBody)
 	return req
@@ -106,3 +153,37 @@ func inspectCallResponse(resp *http.Response, res interface{}) error {
 
 	return msg.Unmarshal(res)
 }
+
+// inspectBatchCallResponse decodes the response messages of a batch
+//
+// Servers respond with a single error message (instead of an array) if the batch is invalid as a whole
+func inspectBatchCallResponse(resp *http.Response) ([]*jsonrpc.ResponseMsg, error) {
+	var raw json.RawMessage
+	err := autorest.Respond(
+		resp,
+		autorest.WithErrorUnlessOK(),
+		autorest.ByUnmarshallingJSON(&raw),
+		autorest.ByClosing(),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	raw = bytes.TrimLeft(raw, " \t\r\n")
+	if len(raw) > 0 && raw[0] == '[' {
+		var msgs []*jsonrpc.ResponseMsg
+		if err := json.Unmarshal(raw, &msgs); err != nil {
+			return nil, fmt.Errorf("failed to decode into JSON-RPC response messages: %v", err)
+		}
+		return msgs, nil
+	}
+
+	msg := new(jsonrpc.ResponseMsg)
+	if err := json.Unmarshal(raw, msg); err != nil {
+		return nil, fmt.Errorf("failed to decode into JSON-RPC response message: %v", err)
+	}
+	if err := msg.Unmarshal(nil); err != nil {
+		return nil, err
+	}
+	return nil, fmt.Errorf("invalid JSON-RPC batch response: expected an array")
+}
@@ -14,6 +14,7 @@ import (
 
 func TestClientImplementsInterface(t *testing.T) {
 	assert.Implementsf(t, (*jsonrpc.Client)(nil), new(Client), "Client should implement jsonrpc.Client")
+	assert.Implementsf(t, (*jsonrpc.BatchClient)(nil), new(Client), "Client should implement jsonrpc.BatchClient")
 }
 
 func TestCall(t *testing.T) {
@@ -111,3 +112,64 @@ func testCallStatus400(t *testing.T, c *Client, mockCli *httptestutils.MockSende
 
 	require.Error(t, err)
 }
+
+func TestBatchCall(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCli := httptestutils.NewMockSender(ctrl)
+	c := NewClientFromClient(mockCli)
+
+	t.Run("StatusOK", func(t *testing.T) { testBatchCallStatusOK(t, c, mockCli) })
+	t.Run("StatusOKAndBatchError", func(t *testing.T) { testBatchCallStatusOKAndBatchError(t, c, mockCli) })
+	t.Run("MissingID", func(t *testing.T) { testBatchCallMissingID(t, c) })
+}
+
+func testBatchCallStatusOK(t *testing.T, c *Client, mockCli *httptestutils.MockSender) {
+	req := httptestutils.NewGockRequest()
+	req.Post("/").
+		JSON([]byte(`[{"jsonrpc":"2.0","method":"concat","params":["a","b"],"id":0},{"jsonrpc":"2.0","method":"concat","params":["c"],"id":1},{"jsonrpc":"2.0","method":"unknown","id":2}]`)).
+		Reply(200).
+		// Responses can be in any order and some can be missing
+		JSON([]byte(`[{"jsonrpc":"2.0","result":"c","id":1},{"jsonrpc":"2.0","result":"ab","id":0}]`))
+
+	mockCli.EXPECT().DoGock(req)
+
+	var res0, res1 string
+	elems := []*jsonrpc.BatchElem{
+		{Req: &jsonrpc.Request{Version: "2.0", Method: "concat", Params: []string{"a", "b"}, ID: 0}, Result: &res0},
+		{Req: &jsonrpc.Request{Version: "2.0", Method: "concat", Params: []string{"c"}, ID: 1}, Result: &res1},
+		{Req: &jsonrpc.Request{Version: "2.0", Method: "unknown", ID: 2}},
+	}
+	err := c.BatchCall(context.Background(), elems)
+	require.NoError(t, err)
+
+	require.NoError(t, elems[0].Error)
+	assert.Equal(t, "ab", res0)
+	require.NoError(t, elems[1].Error)
+	assert.Equal(t, "c", res1)
+	require.Error(t, elems[2].Error)
+}
+
+func testBatchCallStatusOKAndBatchError(t *testing.T, c *Client, mockCli *httptestutils.MockSender) {
+	req := httptestutils.NewGockRequest()
+	req.Post("/").
+		Reply(200).
+		JSON([]byte(`{"jsonrpc":"2.0","error":{"code":-32600,"message":"batch not supported"},"id":null}`))
+
+	mockCli.EXPECT().DoGock(req)
+
+	err := c.BatchCall(context.Background(), []*jsonrpc.BatchElem{
+		{Req: &jsonrpc.Request{Version: "2.0", Method: "concat", ID: 0}},
+	})
+	require.Error(t, err)
+	require.IsType(t, autorest.DetailedError{}, err)
+	assert.Equal(t, -32600, err.(autorest.DetailedError).Original.(*jsonrpc.ErrorMsg).Code)
+}
+
+func testBatchCallMissingID(t *testing.T, c *Client) {
+	err := c.BatchCall(context.Background(), []*jsonrpc.BatchElem{
+		{Req: &jsonrpc.Request{Version: "2.0", Method: "concat"}},
+	})
+	require.Error(t, err)
+}
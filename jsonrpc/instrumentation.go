@@ -2,12 +2,17 @@ package jsonrpc
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"time"
 
 	"github.com/nmvalera/go-utils/app/svc"
 	"github.com/nmvalera/go-utils/log"
 	"github.com/nmvalera/go-utils/tag"
+	"github.com/nmvalera/go-utils/tracing"
 	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 )
 
@@ -29,12 +34,19 @@ func (t *tagged) Call(ctx context.Context, req *Request, res interface{}) error
 	return t.client.Call(ctx, req, res)
 }
 
+func (t *tagged) BatchCall(ctx context.Context, elems []*BatchElem) error {
+	ctx = t.Context(ctx, tag.Key("req.batch_size").Int64(int64(len(elems))))
+	return BatchCall(ctx, t.client, elems)
+}
+
 // WithTags is a decorator that attaches following JSON-RPC specific tags to the Call context .
 // - req.method: JSON-RPC method
 // - req.version: JSON-RPC version
 // - req.params: JSON-RPC params
 // - req.id: JSON-RPC id
 //
+// For batches, it attaches the req.batch_size tag.
+//
 // It also makes the client Taggable
 func WithTags(client Client) Client {
 	return &tagged{
@@ -44,17 +56,30 @@ func WithTags(client Client) Client {
 
 func WithLog(namespaces ...string) ClientDecorator {
 	return func(c Client) Client {
-		return ClientFunc(func(ctx context.Context, req *Request, res interface{}) error {
-			logger := log.LoggerWithFieldsFromNamespaceContext(ctx, namespaces...)
-
-			logger.Debug("Call JSON-RPC")
-			err := c.Call(ctx, req, res)
-			if err != nil {
-				logger.Error("JSON-RPC call failed", zap.Error(err))
-			}
-
-			return err
-		})
+		return &batchClient{
+			call: func(ctx context.Context, req *Request, res interface{}) error {
+				logger := log.LoggerWithFieldsFromNamespaceContext(ctx, namespaces...)
+
+				logger.Debug("Call JSON-RPC")
+				err := c.Call(ctx, req, res)
+				if err != nil {
+					logger.Error("JSON-RPC call failed", zap.Error(err))
+				}
+
+				return err
+			},
+			batch: func(ctx context.Context, elems []*BatchElem) error {
+				logger := log.LoggerWithFieldsFromNamespaceContext(ctx, namespaces...)
+
+				logger.Debug("Batch call JSON-RPC", zap.Int("batch_size", len(elems)))
+				err := BatchCall(ctx, c, elems)
+				if err != nil {
+					logger.Error("JSON-RPC batch call failed", zap.Error(err))
+				}
+
+				return err
+			},
+		}
 	}
 }
 
@@ -107,6 +132,22 @@ func (m *metricable) Call(ctx context.Context, req *Request, res interface{}) er
 	return err
 }
 
+// BatchCall records the metrics of each call of the batch, with the duration of the whole batch
+func (m *metricable) BatchCall(ctx context.Context, elems []*BatchElem) error {
+	start := time.Now()
+	err := BatchCall(ctx, m.client, elems)
+	duration := time.Since(start).Seconds()
+	for _, elem := range elems {
+		m.duration.WithLabelValues(elem.Req.Method).Observe(duration)
+		m.counterTotal.WithLabelValues(elem.Req.Method).Inc()
+		if err != nil || elem.Error != nil {
+			m.counterErrors.WithLabelValues(elem.Req.Method).Inc()
+		}
+	}
+
+	return err
+}
+
 func (m *metricable) Describe(ch chan<- *prometheus.Desc) {
 	m.duration.Describe(ch)
 	m.counterTotal.Describe(ch)
@@ -118,3 +159,65 @@ func (m *metricable) Collect(ch chan<- prometheus.Metric) {
 	m.counterTotal.Collect(ch)
 	m.counterErrors.Collect(ch)
 }
+
+type traced struct {
+	client Client
+}
+
+// WithTracing is a decorator that creates a client span for every Call with following attributes
+// - rpc.system: "jsonrpc"
+// - rpc.method: JSON-RPC method
+// - rpc.jsonrpc.version: JSON-RPC version
+// - rpc.jsonrpc.request_id: JSON-RPC id
+// - rpc.jsonrpc.error_code and rpc.jsonrpc.error_message: JSON-RPC error (if the call failed with an ErrorMsg)
+//
+// Batches are recorded as a single "batch" span with the rpc.jsonrpc.batch_size attribute.
+//
+// Tags attached to the Call context are also recorded as span attributes (see tracing.StartSpan)
+func WithTracing(client Client) Client {
+	return &traced{
+		client: client,
+	}
+}
+
+func (t *traced) Call(ctx context.Context, req *Request, res interface{}) error {
+	ctx, span := tracing.StartSpan(
+		ctx,
+		req.Method,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("rpc.system", "jsonrpc"),
+			attribute.String("rpc.method", req.Method),
+			attribute.String("rpc.jsonrpc.version", req.Version),
+			attribute.String("rpc.jsonrpc.request_id", fmt.Sprintf("%v", req.ID)),
+		),
+	)
+
+	err := t.client.Call(ctx, req, res)
+	if errMsg := new(ErrorMsg); errors.As(err, &errMsg) {
+		span.SetAttributes(
+			attribute.Int("rpc.jsonrpc.error_code", errMsg.Code),
+			attribute.String("rpc.jsonrpc.error_message", errMsg.Message),
+		)
+	}
+	tracing.End(span, err)
+
+	return err
+}
+
+func (t *traced) BatchCall(ctx context.Context, elems []*BatchElem) error {
+	ctx, span := tracing.StartSpan(
+		ctx,
+		"batch",
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("rpc.system", "jsonrpc"),
+			attribute.Int("rpc.jsonrpc.batch_size", len(elems)),
+		),
+	)
+
+	err := BatchCall(ctx, t.client, elems)
+	tracing.End(span, err)
+
+	return err
+}
@@ -13,6 +13,12 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/mock/gomock"
 )
 
@@ -121,3 +127,33 @@ func TestWithMetrics(t *testing.T) {
 
 	assert.Len(t, metrics, 3)
 }
+
+func TestWithTracing(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+
+	tracedCli := jsonrpc.WithTracing(jsonrpc.ClientFunc(func(_ context.Context, _ *jsonrpc.Request, _ any) error {
+		return &jsonrpc.ErrorMsg{Code: -32601, Message: "method not found"}
+	}))
+
+	req := &jsonrpc.Request{
+		ID:      "test-id",
+		Method:  "test_method",
+		Version: "2.0",
+	}
+	err := tracedCli.Call(context.Background(), req, nil)
+	require.Error(t, err)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 1)
+	assert.Equal(t, "test_method", spans[0].Name())
+	assert.Equal(t, trace.SpanKindClient, spans[0].SpanKind())
+	assert.Equal(t, codes.Error, spans[0].Status().Code)
+	assert.Subset(t, spans[0].Attributes(), []attribute.KeyValue{
+		attribute.String("rpc.system", "jsonrpc"),
+		attribute.String("rpc.method", "test_method"),
+		attribute.String("rpc.jsonrpc.request_id", "test-id"),
+		attribute.Int("rpc.jsonrpc.error_code", -32601),
+		attribute.String("rpc.jsonrpc.error_message", "method not found"),
+	})
+}
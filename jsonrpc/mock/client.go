@@ -54,3 +54,55 @@ func (mr *MockClientMockRecorder) Call(ctx, req, res any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Call", reflect.TypeOf((*MockClient)(nil).Call), ctx, req, res)
 }
+
+// MockBatchClient is a mock of BatchClient interface.
+type MockBatchClient struct {
+	ctrl     *gomock.Controller
+	recorder *MockBatchClientMockRecorder
+	isgomock struct{}
+}
+
+// MockBatchClientMockRecorder is the mock recorder for MockBatchClient.
+type MockBatchClientMockRecorder struct {
+	mock *MockBatchClient
+}
+
+// NewMockBatchClient creates a new mock instance.
+func NewMockBatchClient(ctrl *gomock.Controller) *MockBatchClient {
+	mock := &MockBatchClient{ctrl: ctrl}
+	mock.recorder = &MockBatchClientMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBatchClient) EXPECT() *MockBatchClientMockRecorder {
+	return m.recorder
+}
+
+// BatchCall mocks base method.
+func (m *MockBatchClient) BatchCall(ctx context.Context, elems []*jsonrpc.BatchElem) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BatchCall", ctx, elems)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// BatchCall indicates an expected call of BatchCall.
+func (mr *MockBatchClientMockRecorder) BatchCall(ctx, elems any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BatchCall", reflect.TypeOf((*MockBatchClient)(nil).BatchCall), ctx, elems)
+}
+
+// Call mocks base method.
+func (m *MockBatchClient) Call(ctx context.Context, req *jsonrpc.Request, res any) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Call", ctx, req, res)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Call indicates an expected call of Call.
+func (mr *MockBatchClientMockRecorder) Call(ctx, req, res any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Call", reflect.TypeOf((*MockBatchClient)(nil).Call), ctx, req, res)
+}
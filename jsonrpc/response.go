@@ -11,7 +11,7 @@ type ResponseMsg struct {
 	Version string          `json:"jsonrpc"`
 	Result  json.RawMessage `json:"result,omitempty"`
 	Error   json.RawMessage `json:"error,omitempty"`
-	ID      interface{}     `json:"id,omitempty"`
+	ID      interface{}     `json:"id"`
 }
 
 // Unmarshal unmarshals a JSON-RPC response result into a given interface
@@ -0,0 +1,132 @@
+package jsonrpcserver
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/gorilla/websocket"
+	"github.com/nmvalera/go-utils/log"
+	"go.uber.org/zap"
+)
+
+// RegisterHandler mounts the server on its path, serving both HTTP and WebSocket (it implements svc.API)
+func (s *Server) RegisterHandler(router *mux.Router) {
+	router.Handle(s.path, s)
+}
+
+// ServeHTTP serves WebSocket upgrade requests with WebSocketHandler and other requests with HTTPHandler
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if websocket.IsWebSocketUpgrade(r) {
+		s.serveWebSocket(w, r)
+		return
+	}
+	s.serveHTTP(w, r)
+}
+
+// HTTPHandler returns a handler serving JSON-RPC messages posted over HTTP
+func (s *Server) HTTPHandler() http.Handler {
+	return http.HandlerFunc(s.serveHTTP)
+}
+
+// WebSocketHandler returns a handler upgrading the connection to WebSocket and serving JSON-RPC messages on it
+func (s *Server) WebSocketHandler() http.Handler {
+	return http.HandlerFunc(s.serveWebSocket)
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, s.maxMessageSize))
+	if err != nil {
+		if maxBytesErr := new(http.MaxBytesError); errors.As(err, &maxBytesErr) {
+			http.Error(w, http.StatusText(http.StatusRequestEntityTooLarge), http.StatusRequestEntityTooLarge)
+			return
+		}
+		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		return
+	}
+
+	resp := s.HandleMessage(r.Context(), body)
+	if resp == nil {
+		// The message only contains notifications
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(resp)
+}
+
+func (s *Server) serveWebSocket(w http.ResponseWriter, r *http.Request) {
+	conn, err := s.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		// Upgrade has already responded to the client
+		log.LoggerFromContext(r.Context()).Debug("WebSocket upgrade failed", zap.Error(err))
+		return
+	}
+	conn.SetReadLimit(s.maxMessageSize)
+
+	ctx, cancel := context.WithCancel(r.Context())
+
+	var (
+		writeMux sync.Mutex
+		inflight sync.WaitGroup
+	)
+	// Cancels the requests being handled, as their responses can not be sent anymore, and closes the connection
+	// before waiting for them, so none stays blocked writing its response
+	defer func() {
+		cancel()
+		conn.Close()
+		inflight.Wait()
+	}()
+
+	// Bounds the messages handled concurrently, so a client can not exhaust the server
+	sem := make(chan struct{}, max(s.maxConcurrentMessages, 1))
+
+	for {
+		msgType, msg, err := conn.ReadMessage()
+		if err != nil {
+			if !websocket.IsCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway) {
+				log.LoggerFromContext(ctx).Debug("WebSocket connection closed", zap.Error(err))
+			}
+			return
+		}
+
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			return
+		}
+
+		// Messages are handled concurrently, so a slow request does not block the connection
+		inflight.Add(1)
+		go func() {
+			defer func() {
+				<-sem
+				inflight.Done()
+			}()
+			resp := s.HandleMessage(ctx, msg)
+			if resp == nil {
+				return
+			}
+
+			writeMux.Lock()
+			defer writeMux.Unlock()
+			_ = conn.SetWriteDeadline(time.Now().Add(s.writeTimeout))
+			if err := conn.WriteMessage(msgType, resp); err != nil {
+				log.LoggerFromContext(ctx).Debug("Failed to write JSON-RPC response on WebSocket", zap.Error(err))
+				// A failed write corrupts the connection, so it is closed and the read loop returns
+				conn.Close()
+			}
+		}()
+	}
+}
@@ -0,0 +1,120 @@
+package jsonrpcserver
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/gorilla/websocket"
+	"github.com/nmvalera/go-utils/app/svc"
+	"github.com/nmvalera/go-utils/jsonrpc"
+	jsonrpchttp "github.com/nmvalera/go-utils/jsonrpc/http"
+	jsonrpcws "github.com/nmvalera/go-utils/jsonrpc/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServerImplementsInterface(t *testing.T) {
+	assert.Implementsf(t, (*svc.API)(nil), new(Server), "Server should implement svc.API")
+	assert.Implementsf(t, (*svc.Metricable)(nil), new(Server), "Server should implement svc.Metricable")
+	assert.Implementsf(t, (*svc.Taggable)(nil), new(Server), "Server should implement svc.Taggable")
+}
+
+func newTestHTTPServer(t *testing.T) (*httptest.Server, *ethAPI) {
+	s, api := newTestServer(t, WithPath("/rpc"))
+	router := mux.NewRouter()
+	s.RegisterHandler(router)
+
+	srv := httptest.NewServer(router)
+	t.Cleanup(srv.Close)
+	return srv, api
+}
+
+func testClient(t *testing.T, c jsonrpc.Client) {
+	c = jsonrpc.WithIncrementalID()(jsonrpc.WithVersion("2.0")(c))
+
+	var chainID string
+	require.NoError(t, c.Call(context.Background(), &jsonrpc.Request{Method: "eth_chainId"}, &chainID))
+	assert.Equal(t, "0x1", chainID)
+
+	err := c.Call(context.Background(), &jsonrpc.Request{Method: "eth_fail"}, nil)
+	errMsg := new(jsonrpc.ErrorMsg)
+	require.ErrorAs(t, err, &errMsg)
+	assert.Equal(t, 3, errMsg.Code)
+
+	balance := new(string)
+	var echo []string
+	elems := []*jsonrpc.BatchElem{
+		{Req: &jsonrpc.Request{Method: "eth_getBalance", Params: []string{"0xabc"}}, Result: balance},
+		{Req: &jsonrpc.Request{Method: "debug_echo", Params: []string{"hello"}}, Result: &echo},
+		{Req: &jsonrpc.Request{Method: "eth_unknown"}},
+	}
+	require.NoError(t, jsonrpc.BatchCall(context.Background(), c, elems))
+	assert.NoError(t, elems[0].Error)
+	assert.Equal(t, "0xabc@latest", *balance)
+	assert.NoError(t, elems[1].Error)
+	assert.Equal(t, []string{"hello"}, echo)
+	require.ErrorAs(t, elems[2].Error, &errMsg)
+	assert.Equal(t, jsonrpc.CodeMethodNotFound, errMsg.Code)
+}
+
+func TestHTTP(t *testing.T) {
+	srv, api := newTestHTTPServer(t)
+
+	c, err := jsonrpchttp.NewClient(srv.URL+"/rpc", (&jsonrpchttp.Config{}).SetDefault())
+	require.NoError(t, err)
+	testClient(t, c)
+
+	t.Run("Notification", func(t *testing.T) {
+		resp, err := http.Post(srv.URL+"/rpc", "application/json", strings.NewReader(`{"jsonrpc":"2.0","method":"eth_notify","params":["hello"]}`))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+		assert.Equal(t, "hello", <-api.notified)
+	})
+
+	t.Run("MethodNotAllowed", func(t *testing.T) {
+		resp, err := http.Get(srv.URL + "/rpc")
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusMethodNotAllowed, resp.StatusCode)
+	})
+}
+
+func TestWebSocket(t *testing.T) {
+	srv, _ := newTestHTTPServer(t)
+
+	c, err := jsonrpcws.NewClient("ws"+strings.TrimPrefix(srv.URL, "http")+"/rpc", (&jsonrpcws.Config{}).SetDefault())
+	require.NoError(t, err)
+	require.NoError(t, c.Start(context.Background()))
+	defer func() { require.NoError(t, c.Stop(context.Background())) }()
+
+	testClient(t, c)
+}
+
+func TestWebSocketMaxConcurrentMessages(t *testing.T) {
+	s, _ := newTestServer(t, WithMaxConcurrentMessages(1))
+	recorder := new(concurrencyRecorder)
+	require.NoError(t, s.Register("debug_slow", recorder.handler()))
+
+	srv := httptest.NewServer(s)
+	t.Cleanup(srv.Close)
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(srv.URL, "http"), nil)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	for i := range 3 {
+		require.NoError(t, conn.WriteMessage(websocket.TextMessage, []byte(fmt.Sprintf(`{"jsonrpc":"2.0","id":%d,"method":"debug_slow"}`, i))))
+	}
+	for range 3 {
+		_, msg, err := conn.ReadMessage()
+		require.NoError(t, err)
+		assert.Contains(t, string(msg), `"result":"ok"`)
+	}
+	assert.Equal(t, int32(1), recorder.max.Load(), "Messages of a connection should be handled one at a time")
+}
@@ -0,0 +1,165 @@
+package jsonrpcserver
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/nmvalera/go-utils/app/svc"
+	"github.com/nmvalera/go-utils/jsonrpc"
+	"github.com/nmvalera/go-utils/log"
+	"github.com/nmvalera/go-utils/tag"
+	"github.com/nmvalera/go-utils/tracing"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+type tagged struct {
+	handler Handler
+	svc.Tagged
+}
+
+func (t *tagged) ServeJSONRPC(ctx context.Context, req *jsonrpc.Request) (any, error) {
+	ctx = t.Context(
+		ctx,
+		tag.Key("req.method").String(req.Method),
+		tag.Key("req.version").String(req.Version),
+		tag.Key("req.id").Object(req.ID),
+	)
+
+	return t.handler.ServeJSONRPC(ctx, req)
+}
+
+// WithTags is a decorator that attaches following JSON-RPC specific tags to the request context
+// - req.method: JSON-RPC method
+// - req.version: JSON-RPC version
+// - req.id: JSON-RPC id
+//
+// Tags attached to the Server (e.g. by an App) are also attached to the request context
+func WithTags(h Handler) Handler {
+	return &tagged{
+		handler: h,
+	}
+}
+
+// WithLog is a decorator that logs every request and its failure
+func WithLog(namespaces ...string) HandlerDecorator {
+	return func(h Handler) Handler {
+		return HandlerFunc(func(ctx context.Context, req *jsonrpc.Request) (any, error) {
+			logger := log.LoggerWithFieldsFromNamespaceContext(ctx, namespaces...)
+
+			logger.Debug("Serve JSON-RPC")
+			res, err := h.ServeJSONRPC(ctx, req)
+			if err != nil {
+				logger.Error("JSON-RPC request failed", zap.Error(err))
+			}
+
+			return res, err
+		})
+	}
+}
+
+type metricable struct {
+	handler Handler
+
+	duration      *prometheus.HistogramVec
+	counterTotal  *prometheus.CounterVec
+	counterErrors *prometheus.CounterVec
+}
+
+// WithMetrics is a decorator that records the duration, count and failures of requests (per method)
+func WithMetrics(h Handler) Handler {
+	m := &metricable{
+		handler: h,
+	}
+	m.SetMetrics("", "")
+	return m
+}
+
+// SetMetrics sets the metrics of the decorator, tags are attached to all metrics as const labels
+func (m *metricable) SetMetrics(system, subsystem string, tags ...*tag.Tag) {
+	constLabels := prometheus.Labels(tag.Labels(tags...))
+	m.duration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace:   system,
+		Subsystem:   subsystem,
+		Name:        "request_duration_seconds",
+		Help:        "The duration of served requests in seconds (per method)",
+		ConstLabels: constLabels,
+	}, []string{"method"})
+
+	m.counterTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace:   system,
+		Subsystem:   subsystem,
+		Name:        "requests_total",
+		Help:        "The total number of served requests (per method)",
+		ConstLabels: constLabels,
+	}, []string{"method"})
+
+	m.counterErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace:   system,
+		Subsystem:   subsystem,
+		Name:        "requests_errors",
+		Help:        "The number of served requests that failed (per method)",
+		ConstLabels: constLabels,
+	}, []string{"method"})
+}
+
+func (m *metricable) ServeJSONRPC(ctx context.Context, req *jsonrpc.Request) (any, error) {
+	start := time.Now()
+	res, err := m.handler.ServeJSONRPC(ctx, req)
+	m.duration.WithLabelValues(req.Method).Observe(time.Since(start).Seconds())
+	m.counterTotal.WithLabelValues(req.Method).Inc()
+	if err != nil {
+		m.counterErrors.WithLabelValues(req.Method).Inc()
+	}
+
+	return res, err
+}
+
+func (m *metricable) Describe(ch chan<- *prometheus.Desc) {
+	m.duration.Describe(ch)
+	m.counterTotal.Describe(ch)
+	m.counterErrors.Describe(ch)
+}
+
+func (m *metricable) Collect(ch chan<- prometheus.Metric) {
+	m.duration.Collect(ch)
+	m.counterTotal.Collect(ch)
+	m.counterErrors.Collect(ch)
+}
+
+// WithTracing is a decorator that creates a server span for every request with following attributes
+// - rpc.system: "jsonrpc"
+// - rpc.method: JSON-RPC method
+// - rpc.jsonrpc.version: JSON-RPC version
+// - rpc.jsonrpc.request_id: JSON-RPC id
+// - rpc.jsonrpc.error_code and rpc.jsonrpc.error_message: JSON-RPC error (if the request failed)
+func WithTracing(h Handler) Handler {
+	return HandlerFunc(func(ctx context.Context, req *jsonrpc.Request) (any, error) {
+		ctx, span := tracing.StartSpan(
+			ctx,
+			req.Method,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				attribute.String("rpc.system", "jsonrpc"),
+				attribute.String("rpc.method", req.Method),
+				attribute.String("rpc.jsonrpc.version", req.Version),
+				attribute.String("rpc.jsonrpc.request_id", fmt.Sprintf("%v", req.ID)),
+			),
+		)
+
+		res, err := h.ServeJSONRPC(ctx, req)
+		if err != nil {
+			errMsg := toErrorMsg(err)
+			span.SetAttributes(
+				attribute.Int("rpc.jsonrpc.error_code", errMsg.Code),
+				attribute.String("rpc.jsonrpc.error_message", errMsg.Message),
+			)
+		}
+		tracing.End(span, err)
+
+		return res, err
+	})
+}
@@ -0,0 +1,232 @@
+package jsonrpcserver
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/nmvalera/go-utils/app/svc"
+	"github.com/nmvalera/go-utils/jsonrpc"
+)
+
+var (
+	contextType = reflect.TypeOf((*context.Context)(nil)).Elem()
+	errorType   = reflect.TypeOf((*error)(nil)).Elem()
+
+	// serviceTypes are the app service interfaces which methods are not exposed (e.g. Start and Stop)
+	serviceTypes = []reflect.Type{
+		reflect.TypeFor[svc.Runnable](),
+		reflect.TypeFor[svc.Drainable](),
+		reflect.TypeFor[svc.Checkable](),
+		reflect.TypeFor[svc.Reloadable](),
+	}
+)
+
+// RegisterName registers the exported methods of rcvr in the given namespace
+//
+// A method Foo is registered as "<namespace>_foo" (e.g. "eth_chainId" for method ChainId in namespace "eth").
+// Suitable methods have the following signature, where the context is optional and args are decoded
+// from the positional params of the request
+//
+//	func (r *T) Method(ctx context.Context, arg1 A1, arg2 A2, ...) (R, error)
+//	func (r *T) Method(ctx context.Context, arg1 A1, arg2 A2, ...) error
+//
+// Trailing args of pointer, slice, map or interface type are optional.
+// Methods with other signatures are ignored, it returns an error if rcvr has no suitable method.
+//
+// If rcvr is also an app service, the methods of the service interfaces it implements
+// (svc.Runnable, svc.Drainable, svc.Checkable and svc.Reloadable) are not registered.
+func (s *Server) RegisterName(namespace string, rcvr any) error {
+	val := reflect.ValueOf(rcvr)
+	typ := val.Type()
+
+	registered := 0
+	for i := range typ.NumMethod() {
+		m := typ.Method(i)
+		if !m.IsExported() || isServiceMethod(typ, m.Name) {
+			continue
+		}
+
+		h, err := newMethodHandler(val.Method(i))
+		if err != nil {
+			// The method does not have a suitable signature
+			continue
+		}
+
+		if err := s.Register(methodName(namespace, m.Name), h); err != nil {
+			return err
+		}
+		registered++
+	}
+
+	if registered == 0 {
+		return fmt.Errorf("type %s has no suitable method to register in namespace %q", typ, namespace)
+	}
+
+	return nil
+}
+
+// RegisterFunc registers a typed function for the given method
+//
+// The params of the request are unmarshalled into P (e.g. a slice for positional params or a struct for named params).
+func RegisterFunc[P, R any](s *Server, method string, f func(ctx context.Context, params P) (R, error)) error {
+	return s.Register(method, HandlerFunc(func(ctx context.Context, req *jsonrpc.Request) (any, error) {
+		var params P
+		if raw := rawParams(req); len(raw) > 0 {
+			if err := json.Unmarshal(raw, &params); err != nil {
+				return nil, invalidParams(err)
+			}
+		}
+		return f(ctx, params)
+	}))
+}
+
+// isServiceMethod returns true if the method belongs to a service interface implemented by typ
+func isServiceMethod(typ reflect.Type, name string) bool {
+	for _, serviceType := range serviceTypes {
+		if _, ok := serviceType.MethodByName(name); ok && typ.Implements(serviceType) {
+			return true
+		}
+	}
+	return false
+}
+
+// methodName returns the JSON-RPC name of a method in a namespace (e.g. "eth_chainId")
+func methodName(namespace, name string) string {
+	r, size := utf8.DecodeRuneInString(name)
+	name = string(unicode.ToLower(r)) + name[size:]
+	if namespace == "" {
+		return name
+	}
+	return namespace + "_" + name
+}
+
+// methodHandler is a Handler calling a function by reflection
+type methodHandler struct {
+	fn        reflect.Value
+	hasCtx    bool
+	argTypes  []reflect.Type
+	hasResult bool
+}
+
+func newMethodHandler(fn reflect.Value) (*methodHandler, error) {
+	typ := fn.Type()
+	if typ.IsVariadic() {
+		return nil, fmt.Errorf("variadic functions are not supported")
+	}
+
+	h := &methodHandler{fn: fn}
+	for i := range typ.NumIn() {
+		if i == 0 && typ.In(i) == contextType {
+			h.hasCtx = true
+			continue
+		}
+		h.argTypes = append(h.argTypes, typ.In(i))
+	}
+
+	switch {
+	case typ.NumOut() == 1 && typ.Out(0) == errorType:
+	case typ.NumOut() == 2 && typ.Out(1) == errorType:
+		h.hasResult = true
+	default:
+		return nil, fmt.Errorf("function must return an error or a result and an error")
+	}
+
+	return h, nil
+}
+
+func (h *methodHandler) ServeJSONRPC(ctx context.Context, req *jsonrpc.Request) (any, error) {
+	args, err := h.parseArgs(rawParams(req))
+	if err != nil {
+		return nil, invalidParams(err)
+	}
+
+	in := make([]reflect.Value, 0, len(args)+1)
+	if h.hasCtx {
+		in = append(in, reflect.ValueOf(ctx))
+	}
+	in = append(in, args...)
+
+	out := h.fn.Call(in)
+	if errV := out[len(out)-1]; !errV.IsNil() {
+		return nil, errV.Interface().(error)
+	}
+	if h.hasResult {
+		return out[0].Interface(), nil
+	}
+
+	return nil, nil
+}
+
+// parseArgs decodes the positional params into the args of the function
+// A single arg can also be decoded from named params (i.e. a JSON object).
+func (h *methodHandler) parseArgs(raw json.RawMessage) ([]reflect.Value, error) {
+	var params []json.RawMessage
+	switch {
+	case len(raw) == 0 || bytes.Equal(raw, []byte("null")):
+	case raw[0] == '[':
+		if err := json.Unmarshal(raw, &params); err != nil {
+			return nil, err
+		}
+	case raw[0] == '{' && len(h.argTypes) == 1:
+		params = []json.RawMessage{raw}
+	default:
+		return nil, fmt.Errorf("params must be an array")
+	}
+
+	if len(params) > len(h.argTypes) {
+		return nil, fmt.Errorf("too many arguments, want at most %d", len(h.argTypes))
+	}
+
+	args := make([]reflect.Value, len(h.argTypes))
+	for i, typ := range h.argTypes {
+		if i >= len(params) {
+			if !isOptional(typ) {
+				return nil, fmt.Errorf("missing value for required argument %d", i)
+			}
+			args[i] = reflect.Zero(typ)
+			continue
+		}
+
+		arg := reflect.New(typ)
+		if err := json.Unmarshal(params[i], arg.Interface()); err != nil {
+			return nil, fmt.Errorf("invalid argument %d: %v", i, err)
+		}
+		args[i] = arg.Elem()
+	}
+
+	return args, nil
+}
+
+func isOptional(typ reflect.Type) bool {
+	switch typ.Kind() {
+	case reflect.Ptr, reflect.Slice, reflect.Map, reflect.Interface:
+		return true
+	default:
+		return false
+	}
+}
+
+// rawParams returns the raw JSON params of an incoming request
+func rawParams(req *jsonrpc.Request) json.RawMessage {
+	switch params := req.Params.(type) {
+	case json.RawMessage:
+		return bytes.TrimSpace(params)
+	case []byte:
+		return bytes.TrimSpace(params)
+	case nil:
+		return nil
+	default:
+		// The request was not decoded from the wire (e.g. it is served directly) so we encode its params
+		b, _ := json.Marshal(params)
+		return b
+	}
+}
+
+func invalidParams(err error) *jsonrpc.ErrorMsg {
+	return &jsonrpc.ErrorMsg{Code: jsonrpc.CodeInvalidParams, Message: fmt.Sprintf("invalid params: %v", err)}
+}
@@ -0,0 +1,491 @@
+// Package jsonrpcserver implements a JSON-RPC 2.0 server
+//
+// Methods are registered on a Server either from the exported methods of a receiver (see RegisterName),
+// from typed functions (see RegisterFunc) or as raw Handlers (see Server.Register).
+//
+// The Server handles single requests, batches and notifications, and serves them over HTTP and WebSocket.
+// It implements svc.API, so it can be provided to an App to be mounted on the main entrypoint.
+package jsonrpcserver
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/nmvalera/go-utils/app/svc"
+	"github.com/nmvalera/go-utils/jsonrpc"
+	"github.com/nmvalera/go-utils/log"
+	"github.com/nmvalera/go-utils/tag"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultPath                  = "/"
+	defaultMaxMessageSize        = 5 * 1024 * 1024
+	defaultMaxBatchSize          = 100
+	defaultBatchConcurrency      = 10
+	defaultMaxConcurrentMessages = 16
+	defaultWriteTimeout          = 10 * time.Second
+)
+
+// Handler handles a JSON-RPC request
+//
+// The params of the request are the raw JSON params (json.RawMessage, possibly empty).
+// The returned result is marshalled into the response. To respond with a specific JSON-RPC error,
+// the handler returns a *jsonrpc.ErrorMsg, other errors are responded with jsonrpc.CodeServerError.
+type Handler interface {
+	ServeJSONRPC(ctx context.Context, req *jsonrpc.Request) (any, error)
+}
+
+type HandlerFunc func(ctx context.Context, req *jsonrpc.Request) (any, error)
+
+func (f HandlerFunc) ServeJSONRPC(ctx context.Context, req *jsonrpc.Request) (any, error) {
+	return f(ctx, req)
+}
+
+// HandlerDecorator is a function that enable to decorate a JSON-RPC handler with additional functionality
+// (it is the server counterpart of jsonrpc.ClientDecorator)
+type HandlerDecorator func(Handler) Handler
+
+// Server is a JSON-RPC server routing requests to registered methods
+type Server struct {
+	path           string
+	maxMessageSize int64
+	maxBatchSize   int
+	upgrader       *websocket.Upgrader
+
+	batchConcurrency      int
+	maxConcurrentMessages int
+	writeTimeout          time.Duration
+
+	mux        sync.RWMutex
+	methods    map[string]Handler
+	decorators []HandlerDecorator
+	handler    Handler
+
+	// decorated are the handlers of the middleware chain, so metrics and tags can be set on them
+	decorated []Handler
+
+	// metrics and tags are set again on the handlers when the middleware chain is rebuilt (see Use)
+	metrics *metricsConfig
+	tags    []*tag.Tag
+}
+
+type metricsConfig struct {
+	system, subsystem string
+	tags              []*tag.Tag
+}
+
+type Option func(*Server)
+
+// WithPath sets the path the server is mounted on by RegisterHandler (default "/")
+func WithPath(path string) Option {
+	return func(s *Server) {
+		s.path = path
+	}
+}
+
+// WithMaxMessageSize sets the maximum size in bytes of an incoming HTTP body or WebSocket message (default 5MiB)
+func WithMaxMessageSize(n int64) Option {
+	return func(s *Server) {
+		s.maxMessageSize = n
+	}
+}
+
+// WithMaxBatchSize sets the maximum number of requests in a batch (default 100, zero means no limit)
+func WithMaxBatchSize(n int) Option {
+	return func(s *Server) {
+		s.maxBatchSize = n
+	}
+}
+
+// WithBatchConcurrency sets the maximum number of requests of a batch handled concurrently (default 10)
+func WithBatchConcurrency(n int) Option {
+	return func(s *Server) {
+		s.batchConcurrency = n
+	}
+}
+
+// WithMaxConcurrentMessages sets the maximum number of messages handled concurrently on a WebSocket connection (default 16)
+// Further messages are read from the connection once a message has been handled.
+func WithMaxConcurrentMessages(n int) Option {
+	return func(s *Server) {
+		s.maxConcurrentMessages = n
+	}
+}
+
+// WithWriteTimeout sets the timeout to write a response on a WebSocket connection (default 10s)
+// The connection is closed if the client does not read the response in time.
+func WithWriteTimeout(d time.Duration) Option {
+	return func(s *Server) {
+		s.writeTimeout = d
+	}
+}
+
+// WithCheckOrigin sets the function validating the origin of WebSocket connections
+// (default rejects cross-origin connections)
+func WithCheckOrigin(checkOrigin func(r *http.Request) bool) Option {
+	return func(s *Server) {
+		s.upgrader.CheckOrigin = checkOrigin
+	}
+}
+
+// WithMiddlewares decorates the handling of every request with the given decorators (see Server.Use)
+func WithMiddlewares(decorators ...HandlerDecorator) Option {
+	return func(s *Server) {
+		s.decorators = append(s.decorators, decorators...)
+	}
+}
+
+// NewServer creates a new Server
+func NewServer(opts ...Option) *Server {
+	s := &Server{
+		path:           defaultPath,
+		maxMessageSize: defaultMaxMessageSize,
+		maxBatchSize:   defaultMaxBatchSize,
+
+		batchConcurrency:      defaultBatchConcurrency,
+		maxConcurrentMessages: defaultMaxConcurrentMessages,
+		writeTimeout:          defaultWriteTimeout,
+
+		upgrader: &websocket.Upgrader{
+			ReadBufferSize:  1024,
+			WriteBufferSize: 1024,
+		},
+		methods: make(map[string]Handler),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	s.buildHandler()
+
+	return s
+}
+
+// Use decorates the handling of every request with the given decorators
+//
+// The first decorator is the outermost, so it sees the request first.
+// Requests to unknown methods are responded before the decorators are called.
+// Metrics and tags set earlier on the server are set on the new middleware chain.
+func (s *Server) Use(decorators ...HandlerDecorator) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	s.decorators = append(s.decorators, decorators...)
+	s.buildHandlerLocked()
+}
+
+func (s *Server) buildHandler() {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	s.buildHandlerLocked()
+}
+
+func (s *Server) buildHandlerLocked() {
+	var h Handler = HandlerFunc(s.route)
+	s.decorated = s.decorated[:0]
+	for i := len(s.decorators) - 1; i >= 0; i-- {
+		h = s.decorators[i](h)
+		s.decorated = append(s.decorated, h)
+	}
+	s.handler = h
+
+	// The decorators created new handlers, so the metrics and tags set earlier are set on them
+	if s.metrics != nil {
+		s.setMetricsLocked(s.metrics.system, s.metrics.subsystem, s.metrics.tags...)
+	}
+	s.withTagsLocked(s.tags...)
+}
+
+// Register registers a handler for the given method
+// It returns an error if the method is already registered
+func (s *Server) Register(method string, h Handler) error {
+	if method == "" {
+		return fmt.Errorf("empty method name")
+	}
+
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	if _, ok := s.methods[method]; ok {
+		return fmt.Errorf("method %q already registered", method)
+	}
+	s.methods[method] = h
+
+	return nil
+}
+
+// Methods returns the names of the registered methods, sorted
+func (s *Server) Methods() []string {
+	s.mux.RLock()
+	defer s.mux.RUnlock()
+	methods := make([]string, 0, len(s.methods))
+	for method := range s.methods {
+		methods = append(methods, method)
+	}
+	sort.Strings(methods)
+	return methods
+}
+
+func (s *Server) lookup(method string) (Handler, bool) {
+	s.mux.RLock()
+	defer s.mux.RUnlock()
+	h, ok := s.methods[method]
+	return h, ok
+}
+
+// route is the innermost handler, it calls the handler of the requested method
+func (s *Server) route(ctx context.Context, req *jsonrpc.Request) (any, error) {
+	h, ok := s.lookup(req.Method)
+	if !ok {
+		return nil, methodNotFound(req.Method)
+	}
+	return h.ServeJSONRPC(ctx, req)
+}
+
+// ServeJSONRPC handles a request through the middlewares and the registered method
+func (s *Server) ServeJSONRPC(ctx context.Context, req *jsonrpc.Request) (res any, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.LoggerFromContext(ctx).Error("JSON-RPC handler panicked", zap.String("method", req.Method), zap.Any("panic", r))
+			res, err = nil, &jsonrpc.ErrorMsg{Code: jsonrpc.CodeInternalError, Message: "internal error"}
+		}
+	}()
+
+	if _, ok := s.lookup(req.Method); !ok {
+		return nil, methodNotFound(req.Method)
+	}
+
+	s.mux.RLock()
+	h := s.handler
+	s.mux.RUnlock()
+
+	return h.ServeJSONRPC(ctx, req)
+}
+
+// HandleMessage handles an incoming JSON-RPC message, either a single request or a batch,
+// and returns the encoded response
+//
+// It returns nil if no response must be sent (i.e. the message only contains notifications).
+func (s *Server) HandleMessage(ctx context.Context, msg []byte) []byte {
+	msg = bytes.TrimSpace(msg)
+	if !json.Valid(msg) {
+		return encodeResponse(errorResponse(nil, &jsonrpc.ErrorMsg{Code: jsonrpc.CodeParseError, Message: "parse error"}))
+	}
+
+	if msg[0] != '[' {
+		resp := s.handleRequest(ctx, msg)
+		if resp == nil {
+			return nil
+		}
+		return encodeResponse(resp)
+	}
+
+	var raws []json.RawMessage
+	if err := json.Unmarshal(msg, &raws); err != nil {
+		return encodeResponse(errorResponse(nil, &jsonrpc.ErrorMsg{Code: jsonrpc.CodeParseError, Message: "parse error"}))
+	}
+	if len(raws) == 0 {
+		return encodeResponse(errorResponse(nil, &jsonrpc.ErrorMsg{Code: jsonrpc.CodeInvalidRequest, Message: "empty batch"}))
+	}
+	if s.maxBatchSize > 0 && len(raws) > s.maxBatchSize {
+		return encodeResponse(errorResponse(nil, &jsonrpc.ErrorMsg{
+			Code:    jsonrpc.CodeInvalidRequest,
+			Message: fmt.Sprintf("batch too large (%d requests, max %d)", len(raws), s.maxBatchSize),
+		}))
+	}
+
+	// Requests of a batch are handled concurrently, by a bounded number of goroutines
+	resps := make([]*jsonrpc.ResponseMsg, len(raws))
+	sem := make(chan struct{}, max(s.batchConcurrency, 1))
+	var wg sync.WaitGroup
+	for i, raw := range raws {
+		sem <- struct{}{}
+		wg.Add(1)
+		go func() {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			resps[i] = s.handleRequest(ctx, raw)
+		}()
+	}
+	wg.Wait()
+
+	batch := make([]*jsonrpc.ResponseMsg, 0, len(resps))
+	for _, resp := range resps {
+		if resp != nil {
+			batch = append(batch, resp)
+		}
+	}
+	if len(batch) == 0 {
+		return nil
+	}
+
+	return encodeResponse(batch)
+}
+
+// requestMsg is a JSON-RPC request as received on the wire
+//
+// The ID is kept raw to distinguish a request with a null ID, which is responded, from a notification without ID.
+type requestMsg struct {
+	Version string          `json:"jsonrpc"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params"`
+	ID      json.RawMessage `json:"id"`
+}
+
+// isNotification returns true if the request has no ID
+func (msg *requestMsg) isNotification() bool {
+	return msg.ID == nil
+}
+
+// id returns the decoded ID of the request, nil if it is missing or null
+func (msg *requestMsg) id() any {
+	if msg.ID == nil {
+		return nil
+	}
+
+	var id any
+	dec := json.NewDecoder(bytes.NewReader(msg.ID))
+	// Numeric IDs are responded as received
+	dec.UseNumber()
+	// The ID has been decoded as part of the request so it is valid JSON
+	_ = dec.Decode(&id)
+	return id
+}
+
+// handleRequest handles a single request and returns its response (nil for notifications)
+func (s *Server) handleRequest(ctx context.Context, raw json.RawMessage) *jsonrpc.ResponseMsg {
+	msg := new(requestMsg)
+	if err := json.Unmarshal(raw, msg); err != nil {
+		return errorResponse(nil, &jsonrpc.ErrorMsg{Code: jsonrpc.CodeInvalidRequest, Message: "invalid request"})
+	}
+	id := msg.id()
+
+	if msg.Method == "" {
+		return errorResponse(id, &jsonrpc.ErrorMsg{Code: jsonrpc.CodeInvalidRequest, Message: "missing method"})
+	}
+
+	req := &jsonrpc.Request{
+		Version: msg.Version,
+		Method:  msg.Method,
+		ID:      id,
+		Params:  msg.Params,
+	}
+	res, err := s.ServeJSONRPC(ctx, req)
+
+	// Notifications are not responded
+	if msg.isNotification() {
+		return nil
+	}
+
+	if err != nil {
+		return errorResponse(id, toErrorMsg(err))
+	}
+
+	result, err := json.Marshal(res)
+	if err != nil {
+		log.LoggerFromContext(ctx).Error("Failed to marshal JSON-RPC result", zap.String("method", msg.Method), zap.Error(err))
+		return errorResponse(id, &jsonrpc.ErrorMsg{Code: jsonrpc.CodeInternalError, Message: "internal error"})
+	}
+
+	return &jsonrpc.ResponseMsg{
+		Version: "2.0",
+		Result:  result,
+		ID:      id,
+	}
+}
+
+func errorResponse(id any, errMsg *jsonrpc.ErrorMsg) *jsonrpc.ResponseMsg {
+	b, _ := json.Marshal(errMsg)
+	return &jsonrpc.ResponseMsg{
+		Version: "2.0",
+		Error:   b,
+		ID:      id,
+	}
+}
+
+func encodeResponse(resp any) []byte {
+	// Responses are made of valid JSON so marshalling can not fail
+	b, _ := json.Marshal(resp)
+	return b
+}
+
+// toErrorMsg converts an error returned by a handler into a JSON-RPC error
+func toErrorMsg(err error) *jsonrpc.ErrorMsg {
+	if errMsg := new(jsonrpc.ErrorMsg); errors.As(err, &errMsg) {
+		return errMsg
+	}
+	var errMsg jsonrpc.ErrorMsg
+	if errors.As(err, &errMsg) {
+		return &errMsg
+	}
+	return &jsonrpc.ErrorMsg{Code: jsonrpc.CodeServerError, Message: err.Error()}
+}
+
+func methodNotFound(method string) *jsonrpc.ErrorMsg {
+	return &jsonrpc.ErrorMsg{Code: jsonrpc.CodeMethodNotFound, Message: fmt.Sprintf("the method %s does not exist/is not available", method)}
+}
+
+// SetMetrics sets the metrics of the middlewares
+func (s *Server) SetMetrics(system, subsystem string, tags ...*tag.Tag) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	s.metrics = &metricsConfig{system: system, subsystem: subsystem, tags: tags}
+	s.setMetricsLocked(system, subsystem, tags...)
+}
+
+func (s *Server) setMetricsLocked(system, subsystem string, tags ...*tag.Tag) {
+	for _, h := range s.decorated {
+		if m, ok := h.(svc.Metricable); ok {
+			m.SetMetrics(system, subsystem, tags...)
+		}
+	}
+}
+
+// Describe implements prometheus.Collector
+func (s *Server) Describe(ch chan<- *prometheus.Desc) {
+	for _, h := range s.middlewares() {
+		if c, ok := h.(prometheus.Collector); ok {
+			c.Describe(ch)
+		}
+	}
+}
+
+// Collect implements prometheus.Collector
+func (s *Server) Collect(ch chan<- prometheus.Metric) {
+	for _, h := range s.middlewares() {
+		if c, ok := h.(prometheus.Collector); ok {
+			c.Collect(ch)
+		}
+	}
+}
+
+// WithTags attaches tags to the middlewares
+func (s *Server) WithTags(tags ...*tag.Tag) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	s.tags = append(s.tags, tags...)
+	s.withTagsLocked(tags...)
+}
+
+func (s *Server) withTagsLocked(tags ...*tag.Tag) {
+	for _, h := range s.decorated {
+		if t, ok := h.(svc.Taggable); ok {
+			t.WithTags(tags...)
+		}
+	}
+}
+
+func (s *Server) middlewares() []Handler {
+	s.mux.RLock()
+	defer s.mux.RUnlock()
+	return append([]Handler(nil), s.decorated...)
+}
@@ -0,0 +1,304 @@
+package jsonrpcserver
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/nmvalera/go-utils/jsonrpc"
+	"github.com/nmvalera/go-utils/tag"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type ethAPI struct {
+	notified chan string
+}
+
+func (api *ethAPI) ChainId() (string, error) { return "0x1", nil }
+
+func (api *ethAPI) GetBalance(_ context.Context, address string, block *string) (string, error) {
+	if block == nil {
+		return address + "@latest", nil
+	}
+	return address + "@" + *block, nil
+}
+
+func (api *ethAPI) Notify(msg string) error {
+	api.notified <- msg
+	return nil
+}
+
+func (api *ethAPI) Fail() error {
+	return &jsonrpc.ErrorMsg{Code: 3, Message: "execution reverted"}
+}
+
+func (api *ethAPI) Panic() error {
+	panic("test panic")
+}
+
+// Unsupported does not have a suitable signature so it is not registered
+func (api *ethAPI) Unsupported() string { return "" }
+
+func newTestServer(t *testing.T, opts ...Option) (*Server, *ethAPI) {
+	s := NewServer(opts...)
+	api := &ethAPI{notified: make(chan string, 1)}
+	require.NoError(t, s.RegisterName("eth", api))
+	require.NoError(t, RegisterFunc(s, "debug_echo", func(_ context.Context, params []string) ([]string, error) {
+		return params, nil
+	}))
+	require.NoError(t, s.Register("debug_fail", HandlerFunc(func(context.Context, *jsonrpc.Request) (any, error) {
+		return nil, errors.New("test error")
+	})))
+	return s, api
+}
+
+func TestRegister(t *testing.T) {
+	s, api := newTestServer(t)
+	assert.Equal(
+		t,
+		[]string{"debug_echo", "debug_fail", "eth_chainId", "eth_fail", "eth_getBalance", "eth_notify", "eth_panic"},
+		s.Methods(),
+	)
+
+	assert.Error(t, s.RegisterName("eth", api), "Registering a method twice should error")
+	assert.Error(t, s.RegisterName("test", new(struct{})), "Registering a type without method should error")
+}
+
+// serviceAPI is an API which is also an app service
+type serviceAPI struct{}
+
+func (api *serviceAPI) Version() (string, error)       { return "1.0", nil }
+func (api *serviceAPI) Start(_ context.Context) error  { return nil }
+func (api *serviceAPI) Stop(_ context.Context) error   { return nil }
+func (api *serviceAPI) Drain(_ context.Context) error  { return nil }
+func (api *serviceAPI) Ready(_ context.Context) error  { return nil }
+func (api *serviceAPI) Reload(_ context.Context) error { return nil }
+
+func TestRegisterNameExcludesServiceMethods(t *testing.T) {
+	s := NewServer()
+	require.NoError(t, s.RegisterName("test", new(serviceAPI)))
+	assert.Equal(t, []string{"test_version"}, s.Methods(), "Service methods should not be exposed")
+}
+
+func TestHandleMessage(t *testing.T) {
+	s, api := newTestServer(t)
+
+	tests := []struct {
+		desc string
+		msg  string
+		resp string
+	}{
+		{
+			desc: "no params",
+			msg:  `{"jsonrpc":"2.0","id":1,"method":"eth_chainId"}`,
+			resp: `{"jsonrpc":"2.0","id":1,"result":"0x1"}`,
+		},
+		{
+			desc: "positional params with optional arg",
+			msg:  `{"jsonrpc":"2.0","id":"a","method":"eth_getBalance","params":["0xabc"]}`,
+			resp: `{"jsonrpc":"2.0","id":"a","result":"0xabc@latest"}`,
+		},
+		{
+			desc: "positional params",
+			msg:  `{"jsonrpc":"2.0","id":2,"method":"eth_getBalance","params":["0xabc","0x10"]}`,
+			resp: `{"jsonrpc":"2.0","id":2,"result":"0xabc@0x10"}`,
+		},
+		{
+			desc: "typed function",
+			msg:  `{"jsonrpc":"2.0","id":3,"method":"debug_echo","params":["hello","world"]}`,
+			resp: `{"jsonrpc":"2.0","id":3,"result":["hello","world"]}`,
+		},
+		{
+			desc: "missing required arg",
+			msg:  `{"jsonrpc":"2.0","id":4,"method":"eth_getBalance","params":[]}`,
+			resp: `{"jsonrpc":"2.0","id":4,"error":{"code":-32602,"message":"invalid params: missing value for required argument 0"}}`,
+		},
+		{
+			desc: "too many args",
+			msg:  `{"jsonrpc":"2.0","id":5,"method":"eth_chainId","params":[1]}`,
+			resp: `{"jsonrpc":"2.0","id":5,"error":{"code":-32602,"message":"invalid params: too many arguments, want at most 0"}}`,
+		},
+		{
+			desc: "method not found",
+			msg:  `{"jsonrpc":"2.0","id":6,"method":"eth_unknown"}`,
+			resp: `{"jsonrpc":"2.0","id":6,"error":{"code":-32601,"message":"the method eth_unknown does not exist/is not available"}}`,
+		},
+		{
+			desc: "JSON-RPC error",
+			msg:  `{"jsonrpc":"2.0","id":7,"method":"eth_fail"}`,
+			resp: `{"jsonrpc":"2.0","id":7,"error":{"code":3,"message":"execution reverted"}}`,
+		},
+		{
+			desc: "server error",
+			msg:  `{"jsonrpc":"2.0","id":8,"method":"debug_fail"}`,
+			resp: `{"jsonrpc":"2.0","id":8,"error":{"code":-32000,"message":"test error"}}`,
+		},
+		{
+			desc: "panic",
+			msg:  `{"jsonrpc":"2.0","id":9,"method":"eth_panic"}`,
+			resp: `{"jsonrpc":"2.0","id":9,"error":{"code":-32603,"message":"internal error"}}`,
+		},
+		{
+			desc: "null id",
+			msg:  `{"jsonrpc":"2.0","id":null,"method":"eth_chainId"}`,
+			resp: `{"jsonrpc":"2.0","id":null,"result":"0x1"}`,
+		},
+		{
+			desc: "parse error",
+			msg:  `{"jsonrpc":"2.0","id":10,"method"`,
+			resp: `{"jsonrpc":"2.0","id":null,"error":{"code":-32700,"message":"parse error"}}`,
+		},
+		{
+			desc: "missing method",
+			msg:  `{"jsonrpc":"2.0","id":11}`,
+			resp: `{"jsonrpc":"2.0","id":11,"error":{"code":-32600,"message":"missing method"}}`,
+		},
+		{
+			desc: "empty batch",
+			msg:  `[]`,
+			resp: `{"jsonrpc":"2.0","id":null,"error":{"code":-32600,"message":"empty batch"}}`,
+		},
+		{
+			desc: "batch",
+			msg:  `[{"jsonrpc":"2.0","id":1,"method":"eth_chainId"},{"jsonrpc":"2.0","method":"eth_notify","params":["batch"]},1]`,
+			resp: `[{"jsonrpc":"2.0","id":1,"result":"0x1"},{"jsonrpc":"2.0","id":null,"error":{"code":-32600,"message":"invalid request"}}]`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			resp := s.HandleMessage(context.Background(), []byte(test.msg))
+			assert.JSONEq(t, test.resp, string(resp))
+		})
+	}
+	assert.Equal(t, "batch", <-api.notified)
+
+	t.Run("notification", func(t *testing.T) {
+		resp := s.HandleMessage(context.Background(), []byte(`{"jsonrpc":"2.0","method":"eth_notify","params":["hello"]}`))
+		assert.Nil(t, resp, "Notifications should not be responded")
+		assert.Equal(t, "hello", <-api.notified)
+	})
+
+	t.Run("batch too large", func(t *testing.T) {
+		s, _ := newTestServer(t, WithMaxBatchSize(1))
+		resp := s.HandleMessage(context.Background(), []byte(`[{"id":1,"method":"eth_chainId"},{"id":2,"method":"eth_chainId"}]`))
+		assert.JSONEq(t, `{"jsonrpc":"2.0","id":null,"error":{"code":-32600,"message":"batch too large (2 requests, max 1)"}}`, string(resp))
+	})
+}
+
+func TestMiddlewares(t *testing.T) {
+	var calls []string
+	trace := func(name string) HandlerDecorator {
+		return func(h Handler) Handler {
+			return HandlerFunc(func(ctx context.Context, req *jsonrpc.Request) (any, error) {
+				calls = append(calls, name+":"+req.Method)
+				return h.ServeJSONRPC(ctx, req)
+			})
+		}
+	}
+
+	s, _ := newTestServer(t, WithMiddlewares(trace("first"), WithTags, WithLog(), WithMetrics, WithTracing))
+	s.Use(trace("second"))
+	s.SetMetrics("test", "server")
+	s.WithTags()
+
+	s.HandleMessage(context.Background(), []byte(`{"id":1,"method":"eth_chainId"}`))
+	s.HandleMessage(context.Background(), []byte(`{"id":2,"method":"eth_unknown"}`))
+	assert.Equal(t, []string{"first:eth_chainId", "second:eth_chainId"}, calls, "Unknown methods should not reach middlewares")
+
+	reg := prometheus.NewPedanticRegistry()
+	require.NoError(t, reg.Register(s))
+	families, err := reg.Gather()
+	require.NoError(t, err)
+
+	names := make([]string, 0, len(families))
+	for _, family := range families {
+		names = append(names, family.GetName())
+	}
+	assert.ElementsMatch(t, []string{"test_server_request_duration_seconds", "test_server_requests_total"}, names)
+}
+
+func TestUseAfterSetMetrics(t *testing.T) {
+	s, _ := newTestServer(t, WithMiddlewares(WithTags, WithMetrics))
+	s.SetMetrics("test", "server", tag.Key("component").String("rpc"))
+	s.WithTags(tag.Key("env").String("test"))
+
+	// Use rebuilds the middleware chain, the metrics and tags set earlier are kept
+	s.Use(WithLog())
+	s.HandleMessage(context.Background(), []byte(`{"id":1,"method":"eth_chainId"}`))
+
+	reg := prometheus.NewPedanticRegistry()
+	require.NoError(t, reg.Register(s))
+	families, err := reg.Gather()
+	require.NoError(t, err)
+	require.NotEmpty(t, families)
+	for _, family := range families {
+		assert.True(t, strings.HasPrefix(family.GetName(), "test_server_"), family.GetName())
+		labels := make(map[string]string)
+		for _, label := range family.GetMetric()[0].GetLabel() {
+			labels[label.GetName()] = label.GetValue()
+		}
+		assert.Equal(t, "rpc", labels["component"], "Tags should be attached as const labels")
+	}
+
+	count, err := testutil.GatherAndCount(reg, "test_server_requests_total")
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+}
+
+func TestServeJSONRPCWithoutWire(t *testing.T) {
+	s, _ := newTestServer(t)
+
+	// Requests that do not come from the wire have their params encoded
+	res, err := s.ServeJSONRPC(context.Background(), &jsonrpc.Request{Method: "debug_echo", Params: []string{"hello"}})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"hello"}, res)
+
+	_, err = s.ServeJSONRPC(context.Background(), &jsonrpc.Request{Method: "eth_getBalance", Params: json.RawMessage(`{}`)})
+	errMsg := new(jsonrpc.ErrorMsg)
+	require.ErrorAs(t, err, &errMsg)
+	assert.Equal(t, jsonrpc.CodeInvalidParams, errMsg.Code)
+}
+
+// concurrencyRecorder records the maximum number of concurrent calls of a method
+type concurrencyRecorder struct {
+	current, max atomic.Int32
+}
+
+func (r *concurrencyRecorder) handler() Handler {
+	return HandlerFunc(func(context.Context, *jsonrpc.Request) (any, error) {
+		n := r.current.Add(1)
+		defer r.current.Add(-1)
+		for {
+			m := r.max.Load()
+			if n <= m || r.max.CompareAndSwap(m, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		return "ok", nil
+	})
+}
+
+func TestBatchConcurrency(t *testing.T) {
+	s, _ := newTestServer(t, WithBatchConcurrency(2))
+	recorder := new(concurrencyRecorder)
+	require.NoError(t, s.Register("debug_slow", recorder.handler()))
+
+	resp := s.HandleMessage(context.Background(), []byte(`[
+		{"id":1,"method":"debug_slow"},{"id":2,"method":"debug_slow"},{"id":3,"method":"debug_slow"},
+		{"id":4,"method":"debug_slow"},{"id":5,"method":"debug_slow"}
+	]`))
+
+	var msgs []*jsonrpc.ResponseMsg
+	require.NoError(t, json.Unmarshal(resp, &msgs))
+	assert.Len(t, msgs, 5)
+	assert.Equal(t, int32(2), recorder.max.Load(), "Requests of a batch should be handled by at most 2 goroutines")
+}
@@ -1,8 +1,10 @@
 package jsonrpcws
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"sync"
@@ -25,6 +27,7 @@ type Client struct {
 	closeOnce sync.Once
 	closeErr  error
 	closed    chan struct{}
+	stopped   bool // true once Stop has completed, so the client is re-initialized on Start
 }
 
 // NewClient creates a new JSON-RPC WebSocket client.
@@ -32,7 +35,7 @@ func NewClient(addr string, cfg *Config) (*Client, error) {
 	wsClient, err := ws.NewClient(
 		addr,
 		cfg.Client,
-		func(r io.Reader) (interface{}, error) { return jsonrpc.DecodeResponseMsg(r) },
+		decodeResponseMsgs,
 	)
 	if err != nil {
 		return nil, err
@@ -50,7 +53,18 @@ func NewFromClient(c *ws.Client) *Client {
 	}
 }
 
+// Start starts the underlying WebSocket client and the loop handling responses
+// A stopped client can be started again (e.g. after a connection failure)
 func (c *Client) Start(ctx context.Context) error {
+	c.mux.Lock()
+	if c.stopped {
+		c.closeOnce = sync.Once{}
+		c.closeErr = nil
+		c.closed = make(chan struct{})
+		c.stopped = false
+	}
+	c.mux.Unlock()
+
 	err := c.client.Start(ctx)
 	if err != nil {
 		return err
@@ -95,7 +109,13 @@ func (c *Client) call(ctx context.Context, r *jsonrpc.Request, res interface{})
 
 	// We need to lock the inflights map as we are accessing it concurrently
 	c.mux.Lock()
+	if _, ok := c.inflights[r.ID]; ok {
+		// Responses are correlated by ID, so the response of the request in flight would be lost
+		c.mux.Unlock()
+		return errorf(r, "request ID already in flight")
+	}
 	c.inflights[r.ID] = op
+	closed := c.closed
 	c.mux.Unlock()
 	defer func() {
 		c.mux.Lock()
@@ -123,11 +143,83 @@ func (c *Client) call(ctx context.Context, r *jsonrpc.Request, res interface{})
 		return nil
 	case <-ctx.Done():
 		return errorWithErrorf(ctx.Err(), r, "Context canceled")
-	case <-c.closed:
+	case <-closed:
 		return errorf(r, "Client has closed")
 	}
 }
 
+// BatchCall sends the JSON-RPC requests of the given elements in a single message and waits for all responses
+//
+// Requests MUST have unique IDs (e.g. set with jsonrpc.WithIncrementalID) to correlate responses,
+// which the server can send in a single array message or in separate messages.
+// The batch fails if the ID of a request is already in flight.
+func (c *Client) BatchCall(ctx context.Context, elems []*jsonrpc.BatchElem) error {
+	if len(elems) == 0 {
+		return nil
+	}
+
+	if err := jsonrpc.NormalizeBatchIDs(elems); err != nil {
+		return batchErrorWithErrorf(err, "Invalid batch")
+	}
+
+	// Create an operation per request to track the responses
+	ops := make([]*operation, len(elems))
+	reqs := make([]*jsonrpc.Request, len(elems))
+	c.mux.Lock()
+	for _, elem := range elems {
+		if _, ok := c.inflights[elem.Req.ID]; ok {
+			// Responses are correlated by ID, so the response of the request in flight would be lost
+			c.mux.Unlock()
+			return autorest.NewError("jsonrpcws.Client", "BatchCall", "Request ID %v already in flight", elem.Req.ID)
+		}
+	}
+	for i, elem := range elems {
+		ops[i] = &operation{
+			result: make(chan *jsonrpc.ResponseMsg, 1),
+		}
+		c.inflights[elem.Req.ID] = ops[i]
+		reqs[i] = elem.Req
+	}
+	closed := c.closed
+	c.mux.Unlock()
+	defer func() {
+		c.mux.Lock()
+		for i, elem := range elems {
+			delete(c.inflights, elem.Req.ID)
+			close(ops[i].result)
+		}
+		c.mux.Unlock()
+	}()
+
+	// Send the requests to the server
+	err := c.client.SendMessage(
+		ctx,
+		websocket.BinaryMessage,
+		func(w io.Writer) error { return json.NewEncoder(w).Encode(reqs) },
+	)
+	if err != nil {
+		return batchErrorWithErrorf(err, "SendMessage failed")
+	}
+
+	// Wait for all responses
+	for i, op := range ops {
+		select {
+		case msg := <-op.result:
+			elems[i].Error = msg.Unmarshal(elems[i].Result)
+		case <-ctx.Done():
+			return batchErrorWithErrorf(ctx.Err(), "Context canceled")
+		case <-closed:
+			return autorest.NewError("jsonrpcws.Client", "BatchCall", "Client has closed")
+		}
+	}
+
+	return nil
+}
+
+func batchErrorWithErrorf(err error, message string, args ...interface{}) error {
+	return autorest.NewErrorWithError(err, "jsonrpcws.Client", "BatchCall", nil, message, args...)
+}
+
 func errorWithErrorf(err error, r *jsonrpc.Request, message string, args ...interface{}) error {
 	msg, _ := json.Marshal(r)
 	return autorest.NewErrorWithError(err, "jsonrpcws.Client", fmt.Sprintf("Call(%v)", string(msg)), nil, message, args...)
@@ -138,18 +230,51 @@ func errorf(r *jsonrpc.Request, message string, args ...interface{}) error {
 	return autorest.NewError("jsonrpcws.Client", fmt.Sprintf("Call(%v)", string(msg)), message, args...)
 }
 
+// decodeResponseMsgs decodes a JSON-RPC response message or an array of response messages (batch)
+// from an incoming WebSocket message
+func decodeResponseMsgs(r io.Reader) (interface{}, error) {
+	var raw json.RawMessage
+	if err := json.NewDecoder(r).Decode(&raw); err != nil {
+		return nil, fmt.Errorf("failed to decode into JSON-RPC response message: %v", err)
+	}
+
+	raw = bytes.TrimLeft(raw, " \t\r\n")
+	if len(raw) > 0 && raw[0] == '[' {
+		var msgs []*jsonrpc.ResponseMsg
+		if err := json.Unmarshal(raw, &msgs); err != nil {
+			return nil, fmt.Errorf("failed to decode into JSON-RPC response messages: %v", err)
+		}
+		return msgs, nil
+	}
+
+	return jsonrpc.DecodeResponseMsg(bytes.NewReader(raw))
+}
+
 // handleIncomingMessage handles incoming messages from the WebSocket client.
 func (c *Client) handleIncomingMessage(msg *ws.IncomingMessage) error {
 	if msg.Err() != nil {
 		return msg.Err()
 	}
 
-	resp, ok := msg.Value().(*jsonrpc.ResponseMsg)
-	if !ok {
+	switch v := msg.Value().(type) {
+	case *jsonrpc.ResponseMsg:
+		return c.handleResponse(v)
+	case []*jsonrpc.ResponseMsg:
+		var errs []error
+		for _, resp := range v {
+			if err := c.handleResponse(resp); err != nil {
+				errs = append(errs, err)
+			}
+		}
+		return errors.Join(errs...)
+	default:
 		// This should never happen
 		return fmt.Errorf("unexpected message value type: %T", msg.Value())
 	}
+}
 
+// handleResponse publishes a response to the operation with the same ID
+func (c *Client) handleResponse(resp *jsonrpc.ResponseMsg) error {
 	if resp.ID == nil {
 		// This should never happen
 		return fmt.Errorf("missing response ID")
@@ -194,6 +319,10 @@ func (c *Client) Stop(ctx context.Context) error {
 		c.closeErr = c.client.Stop(ctx)
 		c.wg.Wait()
 		close(c.closed)
+
+		c.mux.Lock()
+		c.stopped = true
+		c.mux.Unlock()
 	})
 
 	return c.closeErr
@@ -202,16 +331,5 @@ func (c *Client) Stop(ctx context.Context) error {
 // Takes an ID as received on the wire, validates it, and translates it to a
 // normalized ID appropriate for keying.
 func normalizeID(id interface{}) (interface{}, error) {
-	switch v := id.(type) {
-	case string, float64, nil:
-		return v, nil
-	case int64: // clients sending int64 need to normalize to float64
-		return float64(v), nil
-	case int:
-		return float64(v), nil
-	case uint32:
-		return float64(v), nil
-	default:
-		return nil, fmt.Errorf("invalid id type: %T (must be one of string, float64, int64, int, uint32)", id)
-	}
+	return jsonrpc.NormalizeID(id)
 }
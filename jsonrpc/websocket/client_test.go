@@ -18,12 +18,13 @@ import (
 
 func TestClientImplementsInterface(t *testing.T) {
 	assert.Implementsf(t, (*jsonrpc.Client)(nil), new(Client), "Client should implement jsonrpc.Client")
+	assert.Implementsf(t, (*jsonrpc.BatchClient)(nil), new(Client), "Client should implement jsonrpc.BatchClient")
 }
 
 type handler struct {
 	*testing.T
 	reqs   chan *jsonrpc.RequestMsg
-	resps  chan *jsonrpc.ResponseMsg
+	resps  chan any // *jsonrpc.ResponseMsg or []*jsonrpc.ResponseMsg (batch)
 	errors chan error
 }
 
@@ -40,7 +41,7 @@ func newServer(t *testing.T) (*httptest.Server, handler) {
 	h := handler{
 		T:      t,
 		reqs:   make(chan *jsonrpc.RequestMsg),
-		resps:  make(chan *jsonrpc.ResponseMsg),
+		resps:  make(chan any),
 		errors: make(chan error),
 	}
 	return httptest.NewServer(h), h
@@ -62,14 +63,31 @@ func (h handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 
 	go func() {
 		for {
-			reqMsg := new(jsonrpc.RequestMsg)
-			e := conn.ReadJSON(reqMsg)
+			var raw json.RawMessage
+			e := conn.ReadJSON(&raw)
 			if e != nil {
-				fmt.Printf("test-server: ReadJSON: resp=%v err=%v\n", *reqMsg, e)
+				fmt.Printf("test-server: ReadJSON: err=%v\n", e)
 				h.errors <- e
 				return
 			}
-			h.reqs <- reqMsg
+
+			// Requests of a batch are pushed one by one
+			var reqMsgs []*jsonrpc.RequestMsg
+			if raw[0] == '[' {
+				e = json.Unmarshal(raw, &reqMsgs)
+			} else {
+				reqMsg := new(jsonrpc.RequestMsg)
+				e = json.Unmarshal(raw, reqMsg)
+				reqMsgs = append(reqMsgs, reqMsg)
+			}
+			if e != nil {
+				fmt.Printf("test-server: Unmarshal: err=%v\n", e)
+				h.errors <- e
+				return
+			}
+			for _, reqMsg := range reqMsgs {
+				h.reqs <- reqMsg
+			}
 		}
 	}()
 
@@ -187,6 +205,85 @@ func TestWsClientSendReceiveMsg(t *testing.T) {
 		require.NoError(t, err, "Stop should not error")
 	})
 
+	t.Run("BatchCall", func(t *testing.T) {
+		tt := newTestCtx(t)
+		defer tt.s.Close()
+
+		res1, res2, res3 := new(string), new(string), new(string)
+		elems := []*jsonrpc.BatchElem{
+			{Req: &jsonrpc.Request{ID: 1, Method: "test", Params: []string{"hello"}}, Result: res1},
+			{Req: &jsonrpc.Request{ID: 2, Method: "test", Params: []string{"bonjour"}}, Result: res2},
+			{Req: &jsonrpc.Request{ID: "3", Method: "test", Params: []string{"hola"}}, Result: res3},
+		}
+
+		done := make(chan struct{})
+		var callErr error
+		go func() {
+			callErr = tt.c.BatchCall(context.TODO(), elems)
+			close(done)
+		}()
+
+		// Check the server received the requests
+		for i := range elems {
+			srvReq, err := tt.h.getNextReq(time.Second)
+			require.NoError(t, err, "getNextReq should not error")
+			assert.Equal(t, elems[i].Req.Method, srvReq.Method)
+		}
+
+		// Server sends responses out of order, in an array and in a separate message
+		tt.h.resps <- []*jsonrpc.ResponseMsg{
+			{ID: 2, Result: json.RawMessage(`"world"`)},
+			{ID: 1, Error: json.RawMessage(`{"code":-32000,"message":"test error"}`)},
+		}
+		tt.h.resps <- &jsonrpc.ResponseMsg{ID: "3", Result: json.RawMessage(`"mundo"`)}
+
+		err := waitDone(done, time.Second)
+		require.NoError(t, err, "BatchCall should have ended")
+		require.NoError(t, callErr, "BatchCall should not error")
+		require.IsType(t, &jsonrpc.ErrorMsg{}, elems[0].Error)
+		assert.NoError(t, elems[1].Error)
+		assert.Equal(t, "world", *res2)
+		assert.NoError(t, elems[2].Error)
+		assert.Equal(t, "mundo", *res3)
+
+		err = tt.c.Stop(context.Background())
+		require.NoError(t, err, "Stop should not error")
+	})
+
+	t.Run("BatchCall#IDInFlight", func(t *testing.T) {
+		tt := newTestCtx(t)
+		defer tt.s.Close()
+
+		res := new(string)
+		done := make(chan struct{})
+		var callErr error
+		go func() {
+			callErr = tt.c.Call(context.TODO(), &jsonrpc.Request{ID: 1, Method: "test"}, res)
+			close(done)
+		}()
+
+		_, err := tt.h.getNextReq(time.Second)
+		require.NoError(t, err, "getNextReq should not error")
+
+		// Requests with the ID in flight are rejected, so the response of the first call is not lost
+		err = tt.c.BatchCall(context.TODO(), []*jsonrpc.BatchElem{
+			{Req: &jsonrpc.Request{ID: 2, Method: "test"}},
+			{Req: &jsonrpc.Request{ID: 1, Method: "test"}},
+		})
+		assert.Error(t, err, "BatchCall with an ID in flight should error")
+		err = tt.c.Call(context.TODO(), &jsonrpc.Request{ID: 1, Method: "test"}, nil)
+		assert.Error(t, err, "Call with an ID in flight should error")
+
+		tt.h.resps <- &jsonrpc.ResponseMsg{ID: 1, Result: json.RawMessage(`"world"`)}
+		err = waitDone(done, time.Second)
+		require.NoError(t, err, "Call should have ended")
+		assert.NoError(t, callErr, "Call should not error")
+		assert.Equal(t, "world", *res)
+
+		err = tt.c.Stop(context.Background())
+		require.NoError(t, err, "Stop should not error")
+	})
+
 	t.Run("Call#ClientClose", func(t *testing.T) {
 		tt := newTestCtx(t)
 		defer tt.s.Close()
@@ -0,0 +1,547 @@
+package leader
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/nmvalera/go-utils/app/svc"
+	"github.com/nmvalera/go-utils/log"
+	"github.com/nmvalera/go-utils/tag"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+)
+
+// ErrNotLeader is returned by Elector.Ready when the elector does not hold the leadership (see WithLeaderOnlyReadiness)
+var ErrNotLeader = errors.New("not the leader")
+
+// Elector is a service campaigning for the leadership using a Lock
+//
+// Services which must run as exactly one active replica are wrapped with Singleton:
+// they are started when the leadership is acquired and stopped when it is lost.
+//
+// It implements svc.Runnable, svc.Checkable and svc.Metricable so it can be provided to an app.App.
+// The elector must be provided in the constructor of the Singleton so it is a dependency, e.g.
+//
+//	app.Provide(a, "compactor", func() (*leader.Singleton, error) {
+//		elector := app.Provide(a, "leader", func() (*leader.Elector, error) {
+//			return leader.NewElector(leader.NewFileLock("/tmp/my-app.lock")), nil
+//		})
+//		return elector.Singleton(newCompactor()), nil
+//	})
+type Elector struct {
+	lock     Lock
+	identity string
+
+	leaseDuration  time.Duration
+	renewInterval  time.Duration
+	retryInterval  time.Duration
+	leaderOnlyRead bool
+
+	leading   atomic.Bool
+	lastRenew time.Time
+
+	errMux  sync.RWMutex
+	lastErr error
+
+	singletonsMux sync.Mutex
+	singletons    []*Singleton
+
+	loopMux  sync.Mutex
+	stop     chan struct{}
+	loopDone chan struct{}
+
+	isLeader    prometheus.Gauge
+	transitions prometheus.Counter
+	lockErrors  prometheus.Counter
+
+	*svc.RunContext
+}
+
+type Option func(*Elector)
+
+// WithIdentity sets the identity of the candidate (default "<hostname>-<pid>")
+func WithIdentity(identity string) Option {
+	return func(e *Elector) {
+		e.identity = identity
+	}
+}
+
+// WithLeaseDuration sets the duration a leader holds the lock without renewing it (default 15s)
+// It is the maximum duration without leader when the leader dies without releasing the lock
+func WithLeaseDuration(d time.Duration) Option {
+	return func(e *Elector) {
+		e.leaseDuration = d
+	}
+}
+
+// WithRenewInterval sets the interval at which the leader renews the lock (default 5s)
+// It must be lower than the lease duration
+func WithRenewInterval(d time.Duration) Option {
+	return func(e *Elector) {
+		e.renewInterval = d
+	}
+}
+
+// WithRetryInterval sets the interval at which a follower tries to acquire the lock (default 2s)
+func WithRetryInterval(d time.Duration) Option {
+	return func(e *Elector) {
+		e.retryInterval = d
+	}
+}
+
+// WithLeaderOnlyReadiness makes the elector not ready while it does not hold the leadership
+// e.g. so only the leader receives traffic
+func WithLeaderOnlyReadiness() Option {
+	return func(e *Elector) {
+		e.leaderOnlyRead = true
+	}
+}
+
+// NewElector creates an Elector campaigning with the given lock
+func NewElector(lock Lock, opts ...Option) *Elector {
+	e := &Elector{
+		lock:          lock,
+		identity:      defaultIdentity(),
+		leaseDuration: 15 * time.Second,
+		renewInterval: 5 * time.Second,
+		retryInterval: 2 * time.Second,
+		RunContext:    &svc.RunContext{},
+	}
+	for _, opt := range opts {
+		opt(e)
+	}
+
+	// Metrics are replaced when the elector is provided to an app (see SetMetrics)
+	e.SetMetrics("", "")
+
+	return e
+}
+
+func defaultIdentity() string {
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "unknown"
+	}
+	return fmt.Sprintf("%s-%d", hostname, os.Getpid())
+}
+
+// Identity returns the identity of the candidate
+func (e *Elector) Identity() string {
+	return e.identity
+}
+
+// IsLeader returns true if the elector holds the leadership
+func (e *Elector) IsLeader() bool {
+	return e.leading.Load()
+}
+
+// Singleton returns a service running r only while the elector holds the leadership
+//
+// The Singleton must be provided to the app instead of r, with the elector as a dependency.
+func (e *Elector) Singleton(r svc.Runnable) *Singleton {
+	s := &Singleton{elector: e, runnable: r, errs: make(chan error, 1)}
+
+	e.singletonsMux.Lock()
+	e.singletons = append(e.singletons, s)
+	e.singletonsMux.Unlock()
+
+	return s
+}
+
+// Start starts campaigning for the leadership
+func (e *Elector) Start(ctx context.Context) error {
+	log.LoggerFromContext(ctx).Info("Leader elector starting...", zap.String("identity", e.identity))
+
+	runCtx := e.Context()
+	if runCtx == context.Background() {
+		// Not provided to an app, keep the values (logger, tags) of the start context
+		runCtx = context.WithoutCancel(ctx)
+	}
+
+	e.loopMux.Lock()
+	defer e.loopMux.Unlock()
+	if e.stop != nil {
+		return errors.New("leader elector already started")
+	}
+
+	e.stop = make(chan struct{})
+	e.loopDone = make(chan struct{})
+	go e.loop(runCtx, e.stop, e.loopDone)
+
+	return nil
+}
+
+// Stop stops campaigning, stops the singletons and releases the leadership if held
+// It is a no-op if the elector has not been started
+func (e *Elector) Stop(ctx context.Context) error {
+	logger := log.LoggerFromContext(ctx)
+	logger.Info("Leader elector stopping...")
+
+	e.loopMux.Lock()
+	stop, loopDone := e.stop, e.loopDone
+	e.stop, e.loopDone = nil, nil
+	e.loopMux.Unlock()
+	if stop == nil {
+		// The elector has not been started
+		return nil
+	}
+
+	close(stop)
+	select {
+	case <-loopDone:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	if !e.IsLeader() {
+		return nil
+	}
+
+	e.resign(ctx)
+	return e.lock.Release(ctx, e.identity)
+}
+
+// Ready returns the error of the last attempt to acquire or renew the lock
+// and ErrNotLeader if the elector is not the leader and WithLeaderOnlyReadiness is set
+func (e *Elector) Ready(_ context.Context) error {
+	e.errMux.RLock()
+	err := e.lastErr
+	e.errMux.RUnlock()
+	if err != nil {
+		return err
+	}
+
+	if e.leaderOnlyRead && !e.IsLeader() {
+		return ErrNotLeader
+	}
+	return nil
+}
+
+func (e *Elector) loop(ctx context.Context, stop <-chan struct{}, loopDone chan<- struct{}) {
+	defer close(loopDone)
+
+	for {
+		interval := e.campaign(ctx)
+
+		select {
+		case <-stop:
+			return
+		case <-time.After(interval):
+		}
+	}
+}
+
+// campaign tries to acquire or renew the lock, and updates the leadership accordingly
+// It returns the duration to wait before the next attempt
+func (e *Elector) campaign(ctx context.Context) time.Duration {
+	logger := log.LoggerFromContext(ctx)
+
+	acquireCtx, cancel := context.WithTimeout(ctx, e.renewInterval)
+	held, err := e.lock.Acquire(acquireCtx, e.identity, e.leaseDuration)
+	cancel()
+	e.setLastErr(err)
+
+	switch {
+	case err != nil:
+		e.lockErrors.Inc()
+		logger.Warn("Failed to acquire leadership lock", zap.Error(err))
+		// Resign before the lease expires, so another candidate can not become leader while this one still acts as leader
+		if e.IsLeader() && time.Since(e.lastRenew)+e.renewInterval >= e.leaseDuration {
+			logger.Warn("Leadership lock could not be renewed in time")
+			e.resign(ctx)
+		}
+	case held:
+		e.lastRenew = time.Now()
+		if !e.IsLeader() && !e.lead(ctx) {
+			// A singleton failed to start, so we let another candidate try
+			e.resign(ctx)
+			if err := e.lock.Release(ctx, e.identity); err != nil {
+				logger.Warn("Failed to release leadership lock", zap.Error(err))
+			}
+		}
+	case e.IsLeader():
+		logger.Warn("Leadership lost")
+		e.resign(ctx)
+	}
+
+	if e.IsLeader() {
+		return e.renewInterval
+	}
+	return e.retryInterval
+}
+
+// lead marks the elector as leader and starts the singletons
+// It returns false if a singleton failed to start
+func (e *Elector) lead(ctx context.Context) bool {
+	logger := log.LoggerFromContext(ctx)
+	logger.Info("Leadership acquired", zap.String("identity", e.identity))
+
+	e.leading.Store(true)
+	e.isLeader.Set(1)
+	e.transitions.Inc()
+
+	startCtx, cancel := context.WithTimeout(ctx, e.leaseDuration)
+	defer cancel()
+	for _, s := range e.getSingletons() {
+		if err := s.startIfActive(startCtx); err != nil {
+			logger.Error("Singleton failed to start", zap.Error(err))
+			return false
+		}
+	}
+	return true
+}
+
+// resign stops the singletons and marks the elector as follower
+func (e *Elector) resign(ctx context.Context) {
+	logger := log.LoggerFromContext(ctx)
+
+	// Singletons must be stopped before the lease expires
+	stopCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), e.leaseDuration)
+	defer cancel()
+	for _, s := range e.getSingletons() {
+		if err := s.stopIfRunning(stopCtx); err != nil {
+			logger.Error("Singleton failed to stop", zap.Error(err))
+		}
+	}
+
+	e.leading.Store(false)
+	e.isLeader.Set(0)
+	e.transitions.Inc()
+	logger.Info("Leadership resigned", zap.String("identity", e.identity))
+}
+
+func (e *Elector) getSingletons() []*Singleton {
+	e.singletonsMux.Lock()
+	defer e.singletonsMux.Unlock()
+	return append([]*Singleton(nil), e.singletons...)
+}
+
+func (e *Elector) setLastErr(err error) {
+	e.errMux.Lock()
+	defer e.errMux.Unlock()
+	e.lastErr = err
+}
+
+// SetMetrics sets the metrics of the elector, tags are attached to all metrics as const labels
+func (e *Elector) SetMetrics(system, subsystem string, tags ...*tag.Tag) {
+	constLabels := prometheus.Labels(tag.Labels(tags...))
+	e.isLeader = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace:   system,
+		Subsystem:   subsystem,
+		Name:        "is_leader",
+		Help:        "1 if the instance holds the leadership, 0 otherwise",
+		ConstLabels: constLabels,
+	})
+	e.transitions = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace:   system,
+		Subsystem:   subsystem,
+		Name:        "leadership_transitions_total",
+		Help:        "Total number of leadership acquisitions and losses",
+		ConstLabels: constLabels,
+	})
+	e.lockErrors = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace:   system,
+		Subsystem:   subsystem,
+		Name:        "lock_errors_total",
+		Help:        "Total number of failed attempts to acquire or renew the leadership lock",
+		ConstLabels: constLabels,
+	})
+}
+
+func (e *Elector) Describe(ch chan<- *prometheus.Desc) {
+	e.isLeader.Describe(ch)
+	e.transitions.Describe(ch)
+	e.lockErrors.Describe(ch)
+}
+
+func (e *Elector) Collect(ch chan<- prometheus.Metric) {
+	e.isLeader.Collect(ch)
+	e.transitions.Collect(ch)
+	e.lockErrors.Collect(ch)
+}
+
+// Singleton is a service running a svc.Runnable only while its Elector holds the leadership (see Elector.Singleton)
+//
+// When the app starts the Singleton, the runnable is started only if the elector is the leader,
+// otherwise it is started later, when the leadership is acquired.
+//
+// The Singleton forwards svc.Metricable, prometheus.Collector, svc.Taggable and svc.Fallible to the runnable,
+// so it keeps its metrics, tags and supervision once provided to an app in place of the runnable.
+type Singleton struct {
+	elector  *Elector
+	runnable svc.Runnable
+
+	mux      sync.Mutex
+	active   bool
+	running  bool
+	starting chan struct{} // closed once the runnable start returned, nil if not starting
+
+	errs       chan error
+	runnerDone chan struct{}
+}
+
+// Start enables the singleton and starts the runnable if the elector is the leader
+func (s *Singleton) Start(ctx context.Context) error {
+	s.mux.Lock()
+	s.active = true
+	s.mux.Unlock()
+
+	if !s.elector.IsLeader() {
+		log.LoggerFromContext(ctx).Info("Singleton waiting for leadership...")
+		return nil
+	}
+	return s.startIfActive(ctx)
+}
+
+// Stop disables the singleton and stops the runnable if running
+func (s *Singleton) Stop(ctx context.Context) error {
+	s.mux.Lock()
+	s.active = false
+	s.mux.Unlock()
+
+	return s.stopIfRunning(ctx)
+}
+
+// Ready returns nil if the runnable is not running (the replica is a follower),
+// otherwise the readiness of the runnable if it implements svc.Checkable
+func (s *Singleton) Ready(ctx context.Context) error {
+	s.mux.Lock()
+	running := s.running
+	s.mux.Unlock()
+
+	if c, ok := s.runnable.(svc.Checkable); ok && running {
+		return c.Ready(ctx)
+	}
+	return nil
+}
+
+// IsRunning returns true if the runnable is running
+func (s *Singleton) IsRunning() bool {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	return s.running
+}
+
+// SetRunContext forwards the run context to the runnable if it implements svc.RunContextAware
+func (s *Singleton) SetRunContext(ctx context.Context) {
+	if rca, ok := s.runnable.(svc.RunContextAware); ok {
+		rca.SetRunContext(ctx)
+	}
+}
+
+func (s *Singleton) startIfActive(ctx context.Context) error {
+	s.mux.Lock()
+	if !s.active || s.running || s.starting != nil {
+		s.mux.Unlock()
+		return nil
+	}
+	starting := make(chan struct{})
+	s.starting = starting
+	s.mux.Unlock()
+
+	// The runnable is started outside of the lock so a slow start does not block Ready or the election
+	err := s.runnable.Start(ctx)
+
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	s.starting = nil
+	close(starting)
+	if err != nil {
+		return err
+	}
+	s.running = true
+
+	if f, ok := s.runnable.(svc.Fallible); ok {
+		s.runnerDone = make(chan struct{})
+		go s.forwardErrors(f.Errors(), s.runnerDone)
+	}
+	return nil
+}
+
+func (s *Singleton) stopIfRunning(ctx context.Context) error {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	// Wait for a pending start to return so the runnable is not left running
+	for s.starting != nil {
+		starting := s.starting
+		s.mux.Unlock()
+		select {
+		case <-starting:
+		case <-ctx.Done():
+			s.mux.Lock()
+			return ctx.Err()
+		}
+		s.mux.Lock()
+	}
+
+	if !s.running {
+		return nil
+	}
+	s.running = false
+	if s.runnerDone != nil {
+		close(s.runnerDone)
+		s.runnerDone = nil
+	}
+	return s.runnable.Stop(ctx)
+}
+
+// forwardErrors forwards the errors of the runnable until it is stopped
+func (s *Singleton) forwardErrors(errs <-chan error, done <-chan struct{}) {
+	for {
+		select {
+		case err, ok := <-errs:
+			if !ok {
+				return
+			}
+			if err == nil {
+				continue
+			}
+			select {
+			case s.errs <- err:
+			default:
+				// A fatal error is already pending
+			}
+		case <-done:
+			return
+		}
+	}
+}
+
+// Errors returns the fatal errors of the runnable if it implements svc.Fallible
+func (s *Singleton) Errors() <-chan error {
+	return s.errs
+}
+
+// SetMetrics forwards to the runnable if it implements svc.Metricable
+func (s *Singleton) SetMetrics(system, subsystem string, tags ...*tag.Tag) {
+	if m, ok := s.runnable.(svc.Metricable); ok {
+		m.SetMetrics(system, subsystem, tags...)
+	}
+}
+
+// Describe forwards to the runnable if it implements prometheus.Collector
+func (s *Singleton) Describe(ch chan<- *prometheus.Desc) {
+	if c, ok := s.runnable.(prometheus.Collector); ok {
+		c.Describe(ch)
+	}
+}
+
+// Collect forwards to the runnable if it implements prometheus.Collector
+func (s *Singleton) Collect(ch chan<- prometheus.Metric) {
+	if c, ok := s.runnable.(prometheus.Collector); ok {
+		c.Collect(ch)
+	}
+}
+
+// WithTags forwards to the runnable if it implements svc.Taggable
+func (s *Singleton) WithTags(tags ...*tag.Tag) {
+	if t, ok := s.runnable.(svc.Taggable); ok {
+		t.WithTags(tags...)
+	}
+}
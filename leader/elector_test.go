@@ -0,0 +1,241 @@
+package leader
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/nmvalera/go-utils/tag"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testRunnable struct {
+	running atomic.Bool
+	starts  atomic.Int32
+}
+
+func (r *testRunnable) Start(_ context.Context) error {
+	r.running.Store(true)
+	r.starts.Add(1)
+	return nil
+}
+
+func (r *testRunnable) Stop(_ context.Context) error {
+	r.running.Store(false)
+	return nil
+}
+
+// failingLock is a Lock returning an error when failing is set
+type failingLock struct {
+	Lock
+	failing atomic.Bool
+}
+
+func (l *failingLock) Acquire(ctx context.Context, identity string, ttl time.Duration) (bool, error) {
+	if l.failing.Load() {
+		return false, errors.New("backend unavailable")
+	}
+	return l.Lock.Acquire(ctx, identity, ttl)
+}
+
+func newTestElector(lock Lock, identity string, opts ...Option) *Elector {
+	opts = append([]Option{
+		WithIdentity(identity),
+		WithLeaseDuration(time.Second),
+		WithRenewInterval(50 * time.Millisecond),
+		WithRetryInterval(10 * time.Millisecond),
+	}, opts...)
+	return NewElector(lock, opts...)
+}
+
+func TestElectorFailover(t *testing.T) {
+	ctx := context.Background()
+	client := NewFakeLeaseClient()
+
+	electorA := newTestElector(NewKubernetesLock(client, "default", "my-app"), "a", WithLeaderOnlyReadiness())
+	runnableA := new(testRunnable)
+	singletonA := electorA.Singleton(runnableA)
+
+	electorB := newTestElector(NewKubernetesLock(client, "default", "my-app"), "b", WithLeaderOnlyReadiness())
+	runnableB := new(testRunnable)
+	singletonB := electorB.Singleton(runnableB)
+
+	// A starts first and becomes the leader
+	require.NoError(t, electorA.Start(ctx))
+	require.NoError(t, singletonA.Start(ctx))
+	require.Eventually(t, electorA.IsLeader, time.Second, 5*time.Millisecond)
+	require.Eventually(t, runnableA.running.Load, time.Second, 5*time.Millisecond)
+	assert.NoError(t, electorA.Ready(ctx))
+
+	// B starts as a follower, its singleton does not run
+	require.NoError(t, electorB.Start(ctx))
+	require.NoError(t, singletonB.Start(ctx))
+	time.Sleep(100 * time.Millisecond)
+	assert.False(t, electorB.IsLeader())
+	assert.False(t, runnableB.running.Load())
+	assert.ErrorIs(t, electorB.Ready(ctx), ErrNotLeader)
+
+	// A stops and releases the leadership, B takes over
+	require.NoError(t, singletonA.Stop(ctx))
+	require.NoError(t, electorA.Stop(ctx))
+	assert.False(t, runnableA.running.Load())
+	assert.False(t, electorA.IsLeader())
+
+	require.Eventually(t, electorB.IsLeader, time.Second, 5*time.Millisecond)
+	require.Eventually(t, runnableB.running.Load, time.Second, 5*time.Millisecond)
+	assert.True(t, singletonB.IsRunning())
+	assert.Equal(t, float64(1), testutil.ToFloat64(electorB.isLeader))
+	assert.Equal(t, float64(1), testutil.ToFloat64(electorB.transitions))
+
+	require.NoError(t, singletonB.Stop(ctx))
+	require.NoError(t, electorB.Stop(ctx))
+	assert.False(t, runnableB.running.Load())
+	assert.Equal(t, int32(1), runnableB.starts.Load())
+}
+
+func TestElectorStepsDownOnLockErrors(t *testing.T) {
+	ctx := context.Background()
+	lock := &failingLock{Lock: NewKubernetesLock(NewFakeLeaseClient(), "default", "my-app")}
+
+	elector := newTestElector(lock, "a", WithLeaseDuration(200*time.Millisecond))
+	runnable := new(testRunnable)
+	singleton := elector.Singleton(runnable)
+
+	require.NoError(t, elector.Start(ctx))
+	require.NoError(t, singleton.Start(ctx))
+	require.Eventually(t, runnable.running.Load, time.Second, 5*time.Millisecond)
+
+	// The lock can not be renewed, so the elector steps down before the lease expires
+	lock.failing.Store(true)
+	require.Eventually(t, func() bool { return !elector.IsLeader() }, time.Second, 5*time.Millisecond)
+	assert.False(t, runnable.running.Load())
+	assert.Error(t, elector.Ready(ctx))
+	assert.Positive(t, testutil.ToFloat64(elector.lockErrors))
+
+	// The lock recovers, so the elector leads again
+	lock.failing.Store(false)
+	require.Eventually(t, runnable.running.Load, time.Second, 5*time.Millisecond)
+	assert.NoError(t, elector.Ready(ctx))
+
+	require.NoError(t, singleton.Stop(ctx))
+	require.NoError(t, elector.Stop(ctx))
+}
+
+func TestElectorStopWithoutStart(t *testing.T) {
+	elector := newTestElector(NewKubernetesLock(NewFakeLeaseClient(), "default", "my-app"), "a")
+	assert.NoError(t, elector.Stop(context.Background()))
+}
+
+// instrumentedRunnable is a testRunnable with metrics, tags and fatal errors
+type instrumentedRunnable struct {
+	testRunnable
+
+	tags    []*tag.Tag
+	errs    chan error
+	counter prometheus.Counter
+}
+
+func (r *instrumentedRunnable) SetMetrics(system, subsystem string, _ ...*tag.Tag) {
+	r.counter = prometheus.NewCounter(prometheus.CounterOpts{Namespace: system, Subsystem: subsystem, Name: "runs_total", Help: "Test counter"})
+}
+
+func (r *instrumentedRunnable) WithTags(tags ...*tag.Tag) { r.tags = append(r.tags, tags...) }
+
+func (r *instrumentedRunnable) Describe(ch chan<- *prometheus.Desc) { r.counter.Describe(ch) }
+func (r *instrumentedRunnable) Collect(ch chan<- prometheus.Metric) { r.counter.Collect(ch) }
+func (r *instrumentedRunnable) Errors() <-chan error                { return r.errs }
+
+func TestSingletonForwardsInterfaces(t *testing.T) {
+	ctx := context.Background()
+
+	elector := newTestElector(NewKubernetesLock(NewFakeLeaseClient(), "default", "my-app"), "a")
+	runnable := &instrumentedRunnable{errs: make(chan error, 1)}
+	singleton := elector.Singleton(runnable)
+
+	singleton.SetMetrics("test", "singleton")
+	singleton.WithTags(tag.Key("key").String("value"))
+	assert.Len(t, runnable.tags, 1, "Tags should be forwarded to the runnable")
+
+	reg := prometheus.NewPedanticRegistry()
+	require.NoError(t, reg.Register(singleton))
+	assert.Equal(t, 1, testutil.CollectAndCount(singleton, "test_singleton_runs_total"), "Metrics should be forwarded to the runnable")
+
+	require.NoError(t, singleton.Start(ctx))
+	require.NoError(t, elector.Start(ctx))
+	defer func() { require.NoError(t, elector.Stop(ctx)) }()
+	require.Eventually(t, singleton.IsRunning, time.Second, 10*time.Millisecond)
+
+	runnable.errs <- errors.New("fatal")
+	select {
+	case err := <-singleton.Errors():
+		assert.EqualError(t, err, "fatal")
+	case <-time.After(time.Second):
+		t.Fatal("Errors of the runnable should be forwarded")
+	}
+}
+
+func TestElectorMetrics(t *testing.T) {
+	elector := newTestElector(NewKubernetesLock(NewFakeLeaseClient(), "default", "my-app"), "a")
+	elector.SetMetrics("app", "leader", tag.Key("component").String("elector"))
+
+	reg := prometheus.NewRegistry()
+	require.NoError(t, reg.Register(elector))
+
+	families, err := reg.Gather()
+	require.NoError(t, err)
+	require.Len(t, families, 3)
+	for _, family := range families {
+		require.Len(t, family.GetMetric()[0].GetLabel(), 1)
+		assert.Equal(t, "component", family.GetMetric()[0].GetLabel()[0].GetName(), "Tags should be attached as const labels")
+	}
+}
+
+// slowRunnable is a testRunnable blocking in Start until release is closed
+type slowRunnable struct {
+	testRunnable
+
+	starting chan struct{}
+	release  chan struct{}
+}
+
+func (r *slowRunnable) Start(ctx context.Context) error {
+	close(r.starting)
+	<-r.release
+	return r.testRunnable.Start(ctx)
+}
+
+func TestSingletonSlowStart(t *testing.T) {
+	ctx := context.Background()
+
+	elector := newTestElector(NewKubernetesLock(NewFakeLeaseClient(), "default", "my-app"), "a")
+	runnable := &slowRunnable{starting: make(chan struct{}), release: make(chan struct{})}
+	singleton := elector.Singleton(runnable)
+
+	require.NoError(t, singleton.Start(ctx))
+	require.NoError(t, elector.Start(ctx))
+	<-runnable.starting
+
+	// Ready does not wait for the runnable to start
+	assert.NoError(t, singleton.Ready(ctx))
+	assert.False(t, singleton.IsRunning())
+
+	// Stop waits for the pending start before stopping the runnable
+	stopped := make(chan error, 1)
+	go func() { stopped <- singleton.Stop(ctx) }()
+	select {
+	case <-stopped:
+		t.Fatal("Stop should wait for the pending start")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(runnable.release)
+	require.NoError(t, <-stopped)
+	assert.False(t, runnable.running.Load(), "The runnable should be stopped once started")
+	assert.False(t, singleton.IsRunning())
+	require.NoError(t, elector.Stop(ctx))
+}
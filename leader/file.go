@@ -0,0 +1,74 @@
+package leader
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// FileLock is a Lock backed by an advisory lock on a local file (flock)
+//
+// It elects a leader among processes running on the same host (e.g. for local development).
+// The lock is held until released or until the process holding it exits, so the ttl is ignored.
+type FileLock struct {
+	path string
+
+	mux    sync.Mutex
+	file   *os.File
+	holder string
+}
+
+// NewFileLock creates a FileLock on the file at path (created if it does not exist)
+func NewFileLock(path string) *FileLock {
+	return &FileLock{path: path}
+}
+
+func (l *FileLock) Acquire(_ context.Context, identity string, _ time.Duration) (bool, error) {
+	l.mux.Lock()
+	defer l.mux.Unlock()
+
+	if l.file != nil {
+		return l.holder == identity, nil
+	}
+
+	f, err := os.OpenFile(l.path, os.O_CREATE|os.O_RDWR, 0o644)
+	if err != nil {
+		return false, fmt.Errorf("failed to open lock file: %w", err)
+	}
+
+	locked, err := tryLockFile(f)
+	if err != nil || !locked {
+		_ = f.Close()
+		return false, err
+	}
+
+	// The identity of the holder is written for debugging purpose only
+	if err := f.Truncate(0); err == nil {
+		_, _ = f.WriteAt([]byte(identity), 0)
+	}
+
+	l.file = f
+	l.holder = identity
+
+	return true, nil
+}
+
+func (l *FileLock) Release(_ context.Context, identity string) error {
+	l.mux.Lock()
+	defer l.mux.Unlock()
+
+	if l.file == nil || l.holder != identity {
+		return nil
+	}
+
+	err := unlockFile(l.file)
+	if closeErr := l.file.Close(); err == nil {
+		err = closeErr
+	}
+	l.file = nil
+	l.holder = ""
+
+	return err
+}
@@ -0,0 +1,16 @@
+//go:build !unix
+
+package leader
+
+import (
+	"errors"
+	"os"
+)
+
+func tryLockFile(_ *os.File) (bool, error) {
+	return false, errors.ErrUnsupported
+}
+
+func unlockFile(_ *os.File) error {
+	return errors.ErrUnsupported
+}
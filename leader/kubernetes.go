@@ -0,0 +1,191 @@
+package leader
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrLeaseNotFound is returned by a LeaseClient when the Lease does not exist
+	ErrLeaseNotFound = errors.New("lease not found")
+	// ErrLeaseConflict is returned by a LeaseClient when the Lease already exists on Create,
+	// or has been modified since it has been read on Update
+	ErrLeaseConflict = errors.New("lease conflict")
+)
+
+// Lease holds the fields of a Kubernetes coordination.k8s.io/v1 Lease used for leader election
+type Lease struct {
+	Namespace string
+	Name      string
+
+	// ResourceVersion is used for optimistic concurrency on Update
+	ResourceVersion string
+
+	HolderIdentity       string
+	LeaseDurationSeconds int32
+	AcquireTime          time.Time
+	RenewTime            time.Time
+	LeaseTransitions     int32
+}
+
+// LeaseClient is the subset of the Kubernetes Lease API used by KubernetesLock
+//
+// It is typically implemented by an adapter of the client-go CoordinationV1 Leases client,
+// mapping NotFound and Conflict API errors to ErrLeaseNotFound and ErrLeaseConflict.
+type LeaseClient interface {
+	Get(ctx context.Context, namespace, name string) (*Lease, error)
+	Create(ctx context.Context, lease *Lease) (*Lease, error)
+	Update(ctx context.Context, lease *Lease) (*Lease, error)
+}
+
+// KubernetesLock is a Lock backed by a Kubernetes Lease
+//
+// Updates of the Lease rely on its resource version, so concurrent candidates can not both acquire it.
+type KubernetesLock struct {
+	client    LeaseClient
+	namespace string
+	name      string
+
+	now func() time.Time
+}
+
+// NewKubernetesLock creates a KubernetesLock using the Lease with the given namespace and name
+func NewKubernetesLock(client LeaseClient, namespace, name string) *KubernetesLock {
+	return &KubernetesLock{
+		client:    client,
+		namespace: namespace,
+		name:      name,
+		now:       time.Now,
+	}
+}
+
+func (l *KubernetesLock) Acquire(ctx context.Context, identity string, ttl time.Duration) (bool, error) {
+	now := l.now()
+	durationSeconds := int32(ttl.Round(time.Second) / time.Second)
+
+	lease, err := l.client.Get(ctx, l.namespace, l.name)
+	if errors.Is(err, ErrLeaseNotFound) {
+		_, err = l.client.Create(ctx, &Lease{
+			Namespace:            l.namespace,
+			Name:                 l.name,
+			HolderIdentity:       identity,
+			LeaseDurationSeconds: durationSeconds,
+			AcquireTime:          now,
+			RenewTime:            now,
+		})
+		return l.result(err)
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to get lease: %w", err)
+	}
+
+	expireTime := lease.RenewTime.Add(time.Duration(lease.LeaseDurationSeconds) * time.Second)
+	if lease.HolderIdentity != "" && lease.HolderIdentity != identity && now.Before(expireTime) {
+		return false, nil
+	}
+
+	if lease.HolderIdentity != identity {
+		lease.HolderIdentity = identity
+		lease.AcquireTime = now
+		lease.LeaseTransitions++
+	}
+	lease.LeaseDurationSeconds = durationSeconds
+	lease.RenewTime = now
+
+	_, err = l.client.Update(ctx, lease)
+	return l.result(err)
+}
+
+// result converts a conflict into a failed acquisition
+func (l *KubernetesLock) result(err error) (bool, error) {
+	if errors.Is(err, ErrLeaseConflict) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to write lease: %w", err)
+	}
+	return true, nil
+}
+
+func (l *KubernetesLock) Release(ctx context.Context, identity string) error {
+	lease, err := l.client.Get(ctx, l.namespace, l.name)
+	if errors.Is(err, ErrLeaseNotFound) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get lease: %w", err)
+	}
+	if lease.HolderIdentity != identity {
+		return nil
+	}
+
+	lease.HolderIdentity = ""
+	_, err = l.client.Update(ctx, lease)
+	if err != nil && !errors.Is(err, ErrLeaseConflict) {
+		return fmt.Errorf("failed to release lease: %w", err)
+	}
+	return nil
+}
+
+// FakeLeaseClient is an in-memory LeaseClient behaving as the Kubernetes API (e.g. for tests and local development)
+type FakeLeaseClient struct {
+	mux     sync.Mutex
+	leases  map[string]Lease
+	version int
+}
+
+// NewFakeLeaseClient creates a FakeLeaseClient without any Lease
+func NewFakeLeaseClient() *FakeLeaseClient {
+	return &FakeLeaseClient{
+		leases: make(map[string]Lease),
+	}
+}
+
+func (c *FakeLeaseClient) Get(_ context.Context, namespace, name string) (*Lease, error) {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	lease, ok := c.leases[namespace+"/"+name]
+	if !ok {
+		return nil, ErrLeaseNotFound
+	}
+	return &lease, nil
+}
+
+func (c *FakeLeaseClient) Create(_ context.Context, lease *Lease) (*Lease, error) {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	key := lease.Namespace + "/" + lease.Name
+	if _, ok := c.leases[key]; ok {
+		return nil, ErrLeaseConflict
+	}
+	return c.save(key, lease), nil
+}
+
+func (c *FakeLeaseClient) Update(_ context.Context, lease *Lease) (*Lease, error) {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	key := lease.Namespace + "/" + lease.Name
+	current, ok := c.leases[key]
+	if !ok {
+		return nil, ErrLeaseNotFound
+	}
+	if current.ResourceVersion != lease.ResourceVersion {
+		return nil, ErrLeaseConflict
+	}
+	return c.save(key, lease), nil
+}
+
+func (c *FakeLeaseClient) save(key string, lease *Lease) *Lease {
+	c.version++
+	saved := *lease
+	saved.ResourceVersion = strconv.Itoa(c.version)
+	c.leases[key] = saved
+	return &saved
+}
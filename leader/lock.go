@@ -0,0 +1,20 @@
+// Package leader provides leader election, so a service runs as exactly one active replica
+package leader
+
+import (
+	"context"
+	"time"
+)
+
+// Lock is a backend for leader election
+//
+// At most one identity can hold the lock at a given time.
+type Lock interface {
+	// Acquire tries to acquire the lock for the identity, or to renew it if the identity already holds it
+	// It returns true if the identity holds the lock for at least ttl after the call
+	Acquire(ctx context.Context, identity string, ttl time.Duration) (bool, error)
+
+	// Release releases the lock if the identity holds it,
+	// so another candidate can acquire it without waiting for the lock to expire
+	Release(ctx context.Context, identity string) error
+}
@@ -0,0 +1,154 @@
+package leader
+
+import (
+	"context"
+	"io"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/nmvalera/go-utils/store"
+	"github.com/nmvalera/go-utils/store/memory"
+	"github.com/nmvalera/go-utils/store/s3"
+	s3testutils "github.com/nmvalera/go-utils/store/s3/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testLock(t *testing.T, lockA, lockB Lock, expire func()) {
+	ctx := context.Background()
+
+	held, err := lockA.Acquire(ctx, "a", time.Minute)
+	require.NoError(t, err)
+	assert.True(t, held, "a should acquire the free lock")
+
+	held, err = lockA.Acquire(ctx, "a", time.Minute)
+	require.NoError(t, err)
+	assert.True(t, held, "a should renew the lock")
+
+	held, err = lockB.Acquire(ctx, "b", time.Minute)
+	require.NoError(t, err)
+	assert.False(t, held, "b should not acquire the lock held by a")
+
+	require.NoError(t, lockB.Release(ctx, "b"), "releasing a lock not held should be a no-op")
+
+	require.NoError(t, lockA.Release(ctx, "a"))
+	held, err = lockB.Acquire(ctx, "b", time.Minute)
+	require.NoError(t, err)
+	assert.True(t, held, "b should acquire the released lock")
+
+	if expire == nil {
+		return
+	}
+
+	expire()
+	held, err = lockA.Acquire(ctx, "a", time.Minute)
+	require.NoError(t, err)
+	assert.True(t, held, "a should acquire the expired lock")
+}
+
+func TestFileLock(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "leader.lock")
+	testLock(t, NewFileLock(path), NewFileLock(path), nil)
+}
+
+func TestStoreLock(t *testing.T) {
+	s := memory.New().(*memory.Store)
+
+	now := time.Now()
+	clock := func() time.Time { return now }
+
+	lockA := NewStoreLock(s, "leader.json")
+	lockA.now = clock
+	lockB := NewStoreLock(s, "leader.json")
+	lockB.now = clock
+
+	testLock(t, lockA, lockB, func() { now = now.Add(2 * time.Minute) })
+
+	// a acquires the expired lease while b is acquiring it
+	now = now.Add(2 * time.Minute)
+	racing := NewStoreLock(&racingStore{Store: s, onLoad: func() {
+		held, err := lockA.Acquire(context.Background(), "a", time.Minute)
+		require.NoError(t, err)
+		require.True(t, held)
+	}}, "leader.json")
+	racing.now = clock
+
+	held, err := racing.Acquire(context.Background(), "b", time.Minute)
+	require.NoError(t, err)
+	assert.False(t, held, "b should not overwrite the lease acquired concurrently by a")
+}
+
+func TestStoreLockS3(t *testing.T) {
+	srv := s3testutils.NewServer(t)
+	s3Store, err := s3.New(srv.Client(), "test-bucket")
+	require.NoError(t, err)
+	s := store.WithTags(store.WithLog(s3Store))
+	require.Implements(t, (*store.ConditionalStore)(nil), s)
+
+	now := time.Now()
+	clock := func() time.Time { return now }
+
+	lockA := NewStoreLock(s.(store.ConditionalStore), "leader.json")
+	lockA.now = clock
+	lockB := NewStoreLock(s.(store.ConditionalStore), "leader.json")
+	lockB.now = clock
+
+	testLock(t, lockA, lockB, func() { now = now.Add(2 * time.Minute) })
+}
+
+// racingStore calls onLoad once, after the lease has been loaded
+type racingStore struct {
+	*memory.Store
+	onLoad func()
+}
+
+func (s *racingStore) LoadWithETag(ctx context.Context, key string) (io.ReadCloser, string, error) {
+	reader, etag, err := s.Store.LoadWithETag(ctx, key)
+	if s.onLoad != nil {
+		onLoad := s.onLoad
+		s.onLoad = nil
+		onLoad()
+	}
+	return reader, etag, err
+}
+
+func TestKubernetesLock(t *testing.T) {
+	client := NewFakeLeaseClient()
+
+	now := time.Now()
+	clock := func() time.Time { return now }
+
+	lockA := NewKubernetesLock(client, "default", "my-app")
+	lockA.now = clock
+	lockB := NewKubernetesLock(client, "default", "my-app")
+	lockB.now = clock
+
+	testLock(t, lockA, lockB, func() { now = now.Add(2 * time.Minute) })
+
+	lease, err := client.Get(context.Background(), "default", "my-app")
+	require.NoError(t, err)
+	assert.Equal(t, "a", lease.HolderIdentity)
+	assert.Equal(t, int32(60), lease.LeaseDurationSeconds)
+	assert.Equal(t, int32(2), lease.LeaseTransitions)
+}
+
+func TestFakeLeaseClientConflict(t *testing.T) {
+	ctx := context.Background()
+	client := NewFakeLeaseClient()
+
+	created, err := client.Create(ctx, &Lease{Namespace: "default", Name: "my-app", HolderIdentity: "a"})
+	require.NoError(t, err)
+
+	_, err = client.Create(ctx, &Lease{Namespace: "default", Name: "my-app"})
+	assert.ErrorIs(t, err, ErrLeaseConflict)
+
+	stale := *created
+	created.HolderIdentity = "b"
+	_, err = client.Update(ctx, created)
+	require.NoError(t, err)
+
+	stale.HolderIdentity = "c"
+	_, err = client.Update(ctx, &stale)
+	assert.ErrorIs(t, err, ErrLeaseConflict)
+}
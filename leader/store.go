@@ -0,0 +1,100 @@
+package leader
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/nmvalera/go-utils/store"
+)
+
+// storeLease is the lease record written in the store
+type storeLease struct {
+	Holder     string    `json:"holder"`
+	RenewTime  time.Time `json:"renewTime"`
+	ExpireTime time.Time `json:"expireTime"`
+}
+
+// StoreLock is a Lock backed by a lease object in a store.ConditionalStore (e.g. S3 with conditional writes)
+//
+// Writes of the lease are conditioned on the ETag it has been loaded with, so concurrent candidates can not both acquire it.
+type StoreLock struct {
+	store store.ConditionalStore
+	key   string
+
+	now func() time.Time
+}
+
+// NewStoreLock creates a StoreLock using the object at key in the store
+func NewStoreLock(s store.ConditionalStore, key string) *StoreLock {
+	return &StoreLock{
+		store: s,
+		key:   key,
+		now:   time.Now,
+	}
+}
+
+func (l *StoreLock) Acquire(ctx context.Context, identity string, ttl time.Duration) (bool, error) {
+	current, etag, err := l.load(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	now := l.now()
+	if current != nil && current.Holder != identity && now.Before(current.ExpireTime) {
+		return false, nil
+	}
+
+	b, err := json.Marshal(&storeLease{
+		Holder:     identity,
+		RenewTime:  now,
+		ExpireTime: now.Add(ttl),
+	})
+	if err != nil {
+		return false, err
+	}
+
+	err = l.store.StoreIfMatch(ctx, l.key, bytes.NewReader(b), &store.Headers{ContentType: store.ContentTypeJSON}, etag)
+	if errors.Is(err, store.ErrPreconditionFailed) {
+		// Another candidate wrote the lease since it has been loaded
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to store lease: %w", err)
+	}
+	return true, nil
+}
+
+func (l *StoreLock) Release(ctx context.Context, identity string) error {
+	current, etag, err := l.load(ctx)
+	if err != nil || current == nil || current.Holder != identity {
+		return err
+	}
+
+	err = l.store.DeleteIfMatch(ctx, l.key, etag)
+	if err != nil && !errors.Is(err, store.ErrPreconditionFailed) {
+		return fmt.Errorf("failed to delete lease: %w", err)
+	}
+	return nil
+}
+
+// load returns the current lease and its ETag, or nil if there is none
+func (l *StoreLock) load(ctx context.Context) (*storeLease, string, error) {
+	reader, etag, err := l.store.LoadWithETag(ctx, l.key)
+	if errors.Is(err, store.ErrNotFound) {
+		return nil, "", nil
+	}
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to load lease: %w", err)
+	}
+	defer reader.Close()
+
+	lease := new(storeLease)
+	if err := json.NewDecoder(reader).Decode(lease); err != nil {
+		return nil, "", fmt.Errorf("failed to decode lease: %w", err)
+	}
+	return lease, etag, nil
+}
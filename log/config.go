@@ -222,6 +222,11 @@ func (l Level) MarshalJSON() ([]byte, error) {
 	return json.Marshal(l.String())
 }
 
+// ZapLevel returns the zap level corresponding to the level
+func (l Level) ZapLevel() zapcore.Level {
+	return zapLevels[l]
+}
+
 const (
 	DebugLevel Level = iota
 	InfoLevel
@@ -9,6 +9,7 @@ import (
 	"github.com/spf13/pflag"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
 )
 
 func TestViperConfig(t *testing.T) {
@@ -239,3 +240,8 @@ func TestMarshalJSON(t *testing.T) {
 		"errorOutputPaths": ["stderr"]
 	}`, jsonStr)
 }
+
+func TestLevelZapLevel(t *testing.T) {
+	assert.Equal(t, zap.DebugLevel, DebugLevel.ZapLevel())
+	assert.Equal(t, zap.ErrorLevel, ErrorLevel.ZapLevel())
+}
@@ -0,0 +1,130 @@
+package grpc
+
+import (
+	"math"
+	"time"
+
+	"github.com/nmvalera/go-utils/common"
+	"github.com/nmvalera/go-utils/config"
+	kkrthttp "github.com/nmvalera/go-utils/net/http"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/keepalive"
+)
+
+// DefaultEntrypointConfig returns a default EntrypointConfig.
+func DefaultEntrypointConfig() *EntrypointConfig {
+	return &EntrypointConfig{
+		GRPC: &ServerConfig{
+			MaxRecvMsgSize:       common.Ptr(4 * 1024 * 1024),
+			MaxSendMsgSize:       common.Ptr(math.MaxInt32),
+			MaxConcurrentStreams: common.Ptr(0),
+			ConnectionTimeout:    common.Ptr(120 * time.Second),
+			MaxConnectionIdle:    common.Ptr(time.Duration(0)),
+			MaxConnectionAge:     common.Ptr(time.Duration(0)),
+			KeepAliveTime:        common.Ptr(2 * time.Hour),
+			KeepAliveTimeout:     common.Ptr(20 * time.Second),
+		},
+		Net: &kkrthttp.ListenConfig{
+			KeepAlive: common.Ptr(-time.Second),
+			KeepAliveProbe: &kkrthttp.KeepAliveProbeConfig{
+				Enable:   common.Ptr(false),
+				Idle:     common.Ptr(15 * time.Second),
+				Interval: common.Ptr(15 * time.Second),
+				Count:    common.Ptr(9),
+			},
+		},
+		TLS: &kkrthttp.TLSCertConfig{},
+	}
+}
+
+// EntrypointConfig is the configuration for a gRPC entrypoint.
+type EntrypointConfig struct {
+	Addr *string                 `key:"addr,omitempty" desc:"TCP Address to listen on"`
+	GRPC *ServerConfig           `key:"grpc,omitempty"`
+	Net  *kkrthttp.ListenConfig  `key:"net,omitempty"`
+	TLS  *kkrthttp.TLSCertConfig `key:"tls,omitempty"`
+}
+
+func (cfg *EntrypointConfig) MarshalJSON() ([]byte, error) {
+	return config.Marshal(&embedConfig{cfg})
+}
+
+func (cfg *EntrypointConfig) Entrypoint() (*Entrypoint, error) {
+	return NewEntrypoint(
+		common.Val(cfg.Addr),
+		WithServerOptions(cfg.GRPC.ServerOptions()...),
+		WithListenConfig(cfg.Net.ListenConfig()),
+		WithTLSConfig(cfg.TLS),
+	)
+}
+
+type embedConfig struct {
+	EP *EntrypointConfig `key:"ep,omitempty"`
+}
+
+// Env returns the environment variables for the entrypoint config.
+// All environment variables are prefixed with "EP_".
+func (cfg *EntrypointConfig) Env(hooks ...config.EncodeHookFunc) (map[string]string, error) {
+	return config.Env(&embedConfig{cfg}, hooks...)
+}
+
+// Unmarshal unmarshals the given viper into the entrypoint config.
+// Assumes
+// - all viper keys are prefixed with "ep."
+// - all environment variables are prefixed with "EP_".
+func (cfg *EntrypointConfig) Unmarshal(v *viper.Viper) error {
+	return config.Unmarshal(&embedConfig{cfg}, v)
+}
+
+// AddFlags adds flags to the given viper and pflag.FlagSet.
+// Sets
+// - all viper keys with "ep." prefix
+// - all environment variables with "EP_" prefix
+// - all flags with "ep-" prefix
+func AddFlags(v *viper.Viper, f *pflag.FlagSet, hooks ...config.EncodeHookFunc) error {
+	return config.AddFlags(&embedConfig{DefaultEntrypointConfig()}, v, f, hooks...)
+}
+
+type ServerConfig struct {
+	MaxRecvMsgSize       *int           `key:"maxRecvMsgSize,omitempty" env:"MAX_RECV_MSG_SIZE" flag:"max-recv-msg-size" desc:"Maximum size in bytes of a message the server can receive"`
+	MaxSendMsgSize       *int           `key:"maxSendMsgSize,omitempty" env:"MAX_SEND_MSG_SIZE" flag:"max-send-msg-size" desc:"Maximum size in bytes of a message the server can send"`
+	MaxConcurrentStreams *int           `key:"maxConcurrentStreams,omitempty" env:"MAX_CONCURRENT_STREAMS" flag:"max-concurrent-streams" desc:"Maximum number of concurrent streams per connection (zero means no limit)"`
+	ConnectionTimeout    *time.Duration `key:"connectionTimeout,omitempty" env:"CONNECTION_TIMEOUT" flag:"connection-timeout" desc:"Maximum duration for new connections to complete their handshake"`
+	MaxConnectionIdle    *time.Duration `key:"maxConnectionIdle,omitempty" env:"MAX_CONNECTION_IDLE" flag:"max-connection-idle" desc:"Duration after which an idle connection is closed (zero means no limit)"`
+	MaxConnectionAge     *time.Duration `key:"maxConnectionAge,omitempty" env:"MAX_CONNECTION_AGE" flag:"max-connection-age" desc:"Maximum duration a connection may exist before being gracefully closed (zero means no limit)"`
+	KeepAliveTime        *time.Duration `key:"keepAliveTime,omitempty" env:"KEEP_ALIVE_TIME" flag:"keep-alive-time" desc:"Duration without activity after which the server pings the client"`
+	KeepAliveTimeout     *time.Duration `key:"keepAliveTimeout,omitempty" env:"KEEP_ALIVE_TIMEOUT" flag:"keep-alive-timeout" desc:"Duration the server waits for a ping acknowledgement before closing the connection"`
+}
+
+func (cfg *ServerConfig) MarshalJSON() ([]byte, error) {
+	return config.Marshal(cfg)
+}
+
+// ServerOptions returns the grpc.ServerOptions for the config
+// Unset and zero values use the gRPC defaults
+func (cfg *ServerConfig) ServerOptions() []grpc.ServerOption {
+	var opts []grpc.ServerOption
+	if v := common.Val(cfg.MaxRecvMsgSize); v > 0 {
+		opts = append(opts, grpc.MaxRecvMsgSize(v))
+	}
+	if v := common.Val(cfg.MaxSendMsgSize); v > 0 {
+		opts = append(opts, grpc.MaxSendMsgSize(v))
+	}
+	if v := common.Val(cfg.MaxConcurrentStreams); v > 0 {
+		opts = append(opts, grpc.MaxConcurrentStreams(uint32(v)))
+	}
+	if v := common.Val(cfg.ConnectionTimeout); v > 0 {
+		opts = append(opts, grpc.ConnectionTimeout(v))
+	}
+
+	opts = append(opts, grpc.KeepaliveParams(keepalive.ServerParameters{
+		MaxConnectionIdle: common.Val(cfg.MaxConnectionIdle),
+		MaxConnectionAge:  common.Val(cfg.MaxConnectionAge),
+		Time:              common.Val(cfg.KeepAliveTime),
+		Timeout:           common.Val(cfg.KeepAliveTimeout),
+	}))
+
+	return opts
+}
@@ -0,0 +1,306 @@
+// Package grpc provides a gRPC entrypoint with interceptors and a health service
+package grpc
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+
+	"github.com/nmvalera/go-utils/app/svc"
+	"github.com/nmvalera/go-utils/log"
+	kkrthttp "github.com/nmvalera/go-utils/net/http"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+)
+
+// Entrypoint listens on a local network address and serves incoming gRPC requests.
+//
+// The gRPC server is created when the entrypoint starts, so server options can be added
+// and services registered (Entrypoint implements grpc.ServiceRegistrar) until then.
+type Entrypoint struct {
+	addr string
+
+	lCfg   *net.ListenConfig
+	opts   []grpc.ServerOption
+	tlsCfg *kkrthttp.TLSCertConfig
+
+	registry *ServiceRegistry
+
+	mux    sync.RWMutex
+	l      net.Listener
+	server *grpc.Server
+
+	done   chan struct{}
+	srvErr error
+
+	*svc.RunContext
+}
+
+type EntrypointOption func(*Entrypoint) error
+
+// WithServerOptions adds options to the grpc.Server of the entrypoint.
+func WithServerOptions(opts ...grpc.ServerOption) EntrypointOption {
+	return func(ep *Entrypoint) error {
+		ep.opts = append(ep.opts, opts...)
+		return nil
+	}
+}
+
+// WithListenConfig sets the net.ListenConfig to use for the entrypoint.
+func WithListenConfig(lCfg *net.ListenConfig) EntrypointOption {
+	return func(ep *Entrypoint) error {
+		ep.lCfg = lCfg
+		return nil
+	}
+}
+
+// WithTLSConfig sets the TLS certificate to use for the entrypoint.
+func WithTLSConfig(tlsCfg *kkrthttp.TLSCertConfig) EntrypointOption {
+	return func(ep *Entrypoint) error {
+		ep.tlsCfg = tlsCfg
+		return nil
+	}
+}
+
+// NewEntrypoint creates a new Entrypoint.
+func NewEntrypoint(addr string, opts ...EntrypointOption) (*Entrypoint, error) {
+	ep := &Entrypoint{
+		addr:       addr,
+		lCfg:       &net.ListenConfig{},
+		registry:   NewServiceRegistry(),
+		RunContext: &svc.RunContext{},
+	}
+
+	for _, opt := range opts {
+		if err := opt(ep); err != nil {
+			return nil, err
+		}
+	}
+
+	return ep, nil
+}
+
+// Addr returns the address the entrypoint is exposed to after Start() is called.
+func (ep *Entrypoint) Addr() string {
+	ep.mux.RLock()
+	defer ep.mux.RUnlock()
+
+	if ep.l == nil {
+		return ""
+	}
+	return ep.l.Addr().String()
+}
+
+// AddServerOptions adds options to the grpc.Server
+// It MUST be called before Start() (e.g. to add interceptors).
+func (ep *Entrypoint) AddServerOptions(opts ...grpc.ServerOption) {
+	ep.opts = append(ep.opts, opts...)
+}
+
+// RegisterService registers a service on the entrypoint
+// It MUST be called before Start().
+func (ep *Entrypoint) RegisterService(desc *grpc.ServiceDesc, impl any) {
+	ep.registry.RegisterService(desc, impl)
+}
+
+// Server returns the grpc.Server of the entrypoint, or nil if the entrypoint has not been started.
+func (ep *Entrypoint) Server() *grpc.Server {
+	ep.mux.RLock()
+	defer ep.mux.RUnlock()
+	return ep.server
+}
+
+// Start starts the entrypoint.
+func (ep *Entrypoint) Start(ctx context.Context) error {
+	opts := ep.opts
+	if ep.tlsCfg != nil && ep.tlsCfg.CertFile != nil {
+		creds, err := ep.credentials()
+		if err != nil {
+			return err
+		}
+		opts = append(opts, grpc.Creds(creds))
+	}
+
+	server := grpc.NewServer(opts...)
+	ep.registry.RegisterTo(server)
+
+	// Open connection and return possibly error
+	l, err := ep.listen(ctx)
+	if err != nil {
+		return err
+	}
+
+	ep.mux.Lock()
+	ep.l = l
+	ep.server = server
+	ep.mux.Unlock()
+
+	return ep.serve(ctx, server, l)
+}
+
+// Stop gracefully stops the entrypoint, waiting for in-flight requests to complete.
+// If the context is done before, the server is stopped forcefully.
+func (ep *Entrypoint) Stop(stopCtx context.Context) error {
+	logger := log.LoggerFromContext(stopCtx)
+	logger.Info("Entrypoint gracefully stopping...")
+
+	if err := ep.gracefulStop(stopCtx); err != nil {
+		logger.Error("Error while stopping entrypoint", zap.Error(err))
+		return err
+	}
+
+	// Wait for Serve(...) to be done
+	<-ep.done
+
+	// Return possible error from Serve(...)
+	if srvErr := ep.getSrvErr(); srvErr != nil && !errors.Is(srvErr, grpc.ErrServerStopped) {
+		return srvErr
+	}
+
+	logger.Info("Entrypoint successfully stopped")
+
+	return nil
+}
+
+// Drain stops accepting new connections and waits for in-flight requests to complete
+// Stop MUST still be called afterwards
+func (ep *Entrypoint) Drain(ctx context.Context) error {
+	logger := log.LoggerFromContext(ctx)
+	logger.Info("Entrypoint draining...")
+
+	if err := ep.gracefulStop(ctx); err != nil {
+		logger.Error("Error while draining entrypoint", zap.Error(err))
+		return err
+	}
+
+	logger.Info("Entrypoint successfully drained")
+
+	return nil
+}
+
+// gracefulStop gracefully stops the server and forcefully stops it if the context is done first
+func (ep *Entrypoint) gracefulStop(ctx context.Context) error {
+	server := ep.Server()
+
+	stopped := make(chan struct{})
+	go func() {
+		server.GracefulStop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+		return nil
+	case <-ctx.Done():
+		server.Stop()
+		return ctx.Err()
+	}
+}
+
+// Ready returns the error from Serve(...) if it's not nil.
+func (ep *Entrypoint) Ready(_ context.Context) error {
+	return ep.getSrvErr()
+}
+
+func (ep *Entrypoint) getSrvErr() error {
+	ep.mux.RLock()
+	defer ep.mux.RUnlock()
+	return ep.srvErr
+}
+
+func (ep *Entrypoint) setSrvErr(err error) {
+	ep.mux.Lock()
+	defer ep.mux.Unlock()
+	ep.srvErr = err
+}
+
+func (ep *Entrypoint) credentials() (credentials.TransportCredentials, error) {
+	if ep.tlsCfg.KeyFile == nil {
+		return nil, errors.New("key file is required")
+	}
+	return credentials.NewServerTLSFromFile(*ep.tlsCfg.CertFile, *ep.tlsCfg.KeyFile)
+}
+
+func (ep *Entrypoint) listen(startCtx context.Context) (net.Listener, error) {
+	logger := log.LoggerFromContext(startCtx)
+
+	logger.Info(
+		"Open entrypoint on local network",
+		zap.String("network", "tcp"),
+		zap.String("address", ep.addr),
+	)
+
+	l, err := ep.lCfg.Listen(startCtx, "tcp", ep.addr)
+	if err != nil {
+		ep.setSrvErr(err)
+		logger.Error("Failed to open entrypoint on local network", zap.Error(err))
+		return nil, err
+	}
+
+	return l, nil
+}
+
+// serve serves incoming gRPC requests.
+func (ep *Entrypoint) serve(startCtx context.Context, server *grpc.Server, l net.Listener) error {
+	logger := log.LoggerFromContext(startCtx)
+
+	logger.Info("Entrypoint is accepting and serving incoming gRPC requests...")
+	ep.done = make(chan struct{})
+
+	go func() {
+		err := server.Serve(l)
+		ep.setSrvErr(err)
+		if err != nil && !errors.Is(err, grpc.ErrServerStopped) {
+			logger.Error("Entrypoint failed while serving incoming gRPC requests", zap.Error(err))
+		}
+		close(ep.done)
+	}()
+
+	return nil
+}
+
+// ServiceRegistry records gRPC service registrations, to register them later on a grpc.ServiceRegistrar
+// (e.g. services registered while an app is constructed, before its gRPC server is created)
+type ServiceRegistry struct {
+	mux      sync.Mutex
+	services []*serviceRegistration
+}
+
+type serviceRegistration struct {
+	desc *grpc.ServiceDesc
+	impl any
+}
+
+// NewServiceRegistry creates an empty ServiceRegistry
+func NewServiceRegistry() *ServiceRegistry {
+	return &ServiceRegistry{}
+}
+
+// RegisterService records the registration of a service
+func (r *ServiceRegistry) RegisterService(desc *grpc.ServiceDesc, impl any) {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	r.services = append(r.services, &serviceRegistration{desc: desc, impl: impl})
+}
+
+// RegisterTo registers all the recorded services on the registrar
+func (r *ServiceRegistry) RegisterTo(registrar grpc.ServiceRegistrar) {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	for _, s := range r.services {
+		registrar.RegisterService(s.desc, s.impl)
+	}
+}
+
+// ServiceNames returns the names of the recorded services
+func (r *ServiceRegistry) ServiceNames() []string {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	names := make([]string, 0, len(r.services))
+	for _, s := range r.services {
+		names = append(names, s.desc.ServiceName)
+	}
+	return names
+}
@@ -0,0 +1,185 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/nmvalera/go-utils/tag"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// echoServer is a gRPC service with a single unary method Ping
+type echoServer struct {
+	err  error
+	tags map[string]string
+}
+
+func (s *echoServer) Ping(ctx context.Context) (*emptypb.Empty, error) {
+	s.tags = make(map[string]string)
+	for _, t := range tag.FromContext(ctx) {
+		s.tags[string(t.Key)] = t.Value.String()
+	}
+	return &emptypb.Empty{}, s.err
+}
+
+var echoServiceDesc = grpc.ServiceDesc{
+	ServiceName: "test.Echo",
+	HandlerType: (*any)(nil),
+	Methods: []grpc.MethodDesc{{
+		MethodName: "Ping",
+		Handler: func(srv any, ctx context.Context, dec func(any) error, interceptor grpc.UnaryServerInterceptor) (any, error) {
+			in := new(emptypb.Empty)
+			if err := dec(in); err != nil {
+				return nil, err
+			}
+			handler := func(ctx context.Context, _ any) (any, error) {
+				return srv.(*echoServer).Ping(ctx)
+			}
+			if interceptor == nil {
+				return handler(ctx, in)
+			}
+			return interceptor(ctx, in, &grpc.UnaryServerInfo{Server: srv, FullMethod: "/test.Echo/Ping"}, handler)
+		},
+	}},
+}
+
+func dial(t *testing.T, addr string) *grpc.ClientConn {
+	conn, err := grpc.NewClient(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+	return conn
+}
+
+func TestEntrypoint(t *testing.T) {
+	ctx := context.Background()
+
+	runCtx := tag.WithTags(ctx, tag.Key("app").String("test"))
+	metrics := NewServerMetrics()
+
+	ep, err := NewEntrypoint("127.0.0.1:0")
+	require.NoError(t, err)
+	ep.SetRunContext(runCtx)
+	ep.AddServerOptions(
+		grpc.ChainUnaryInterceptor(
+			ContextUnaryServerInterceptor(ep.Context),
+			LoggingUnaryServerInterceptor(),
+			metrics.UnaryServerInterceptor(),
+		),
+	)
+
+	echo := new(echoServer)
+	ep.RegisterService(&echoServiceDesc, echo)
+
+	var ready atomicError
+	health := NewHealthServer(ready.Load)
+	health.AddServices(echoServiceDesc.ServiceName)
+	health.Register(ep)
+
+	require.NoError(t, ep.Start(ctx))
+	require.NotNil(t, ep.Server())
+	conn := dial(t, ep.Addr())
+
+	// Unary call with interceptors
+	require.NoError(t, conn.Invoke(ctx, "/test.Echo/Ping", &emptypb.Empty{}, new(emptypb.Empty)))
+	assert.Equal(t, map[string]string{"app": "test", "grpc.method": "/test.Echo/Ping"}, echo.tags)
+
+	echo.err = status.Error(codes.InvalidArgument, "invalid")
+	err = conn.Invoke(ctx, "/test.Echo/Ping", &emptypb.Empty{}, new(emptypb.Empty))
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	assert.Equal(t, float64(2), testutil.ToFloat64(metrics.started.WithLabelValues("unary", "test.Echo", "Ping")))
+	assert.Equal(t, float64(1), testutil.ToFloat64(metrics.handled.WithLabelValues("unary", "test.Echo", "Ping", "OK")))
+	assert.Equal(t, float64(1), testutil.ToFloat64(metrics.handled.WithLabelValues("unary", "test.Echo", "Ping", "InvalidArgument")))
+
+	// Health
+	client := healthpb.NewHealthClient(conn)
+	resp, err := client.Check(ctx, &healthpb.HealthCheckRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, healthpb.HealthCheckResponse_SERVING, resp.Status)
+
+	resp, err = client.Check(ctx, &healthpb.HealthCheckRequest{Service: "test.Echo"})
+	require.NoError(t, err)
+	assert.Equal(t, healthpb.HealthCheckResponse_SERVING, resp.Status)
+
+	_, err = client.Check(ctx, &healthpb.HealthCheckRequest{Service: "unknown"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	ready.Store(errors.New("not ready"))
+	resp, err = client.Check(ctx, &healthpb.HealthCheckRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, resp.Status)
+
+	stopCtx, cancel := context.WithTimeout(ctx, time.Second)
+	defer cancel()
+	require.NoError(t, ep.Stop(stopCtx))
+}
+
+func TestHealthServerWatch(t *testing.T) {
+	ctx := context.Background()
+
+	var ready atomicError
+	health := NewHealthServer(ready.Load, WithWatchInterval(10*time.Millisecond))
+
+	ep, err := NewEntrypoint("127.0.0.1:0")
+	require.NoError(t, err)
+	health.Register(ep)
+	require.NoError(t, ep.Start(ctx))
+	defer func() { require.NoError(t, ep.Stop(ctx)) }()
+
+	watchCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	stream, err := healthpb.NewHealthClient(dial(t, ep.Addr())).Watch(watchCtx, &healthpb.HealthCheckRequest{})
+	require.NoError(t, err)
+
+	resp, err := stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, healthpb.HealthCheckResponse_SERVING, resp.Status)
+
+	ready.Store(errors.New("not ready"))
+	resp, err = stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, resp.Status)
+}
+
+func TestHealthServerWatchSharesStatus(t *testing.T) {
+	var checks atomic.Int32
+	health := NewHealthServer(func(context.Context) error {
+		checks.Add(1)
+		return nil
+	}, WithWatchInterval(time.Hour))
+
+	for range 3 {
+		assert.Equal(t, healthpb.HealthCheckResponse_SERVING, health.sharedStatus(context.Background()))
+	}
+	assert.Equal(t, int32(1), checks.Load(), "Watch streams should share the status within the watch interval")
+}
+
+// atomicError is a check function whose result can be changed concurrently
+type atomicError struct {
+	mux sync.Mutex
+	err error
+}
+
+func (e *atomicError) Store(err error) {
+	e.mux.Lock()
+	defer e.mux.Unlock()
+	e.err = err
+}
+
+func (e *atomicError) Load(_ context.Context) error {
+	e.mux.Lock()
+	defer e.mux.Unlock()
+	return e.err
+}
@@ -0,0 +1,140 @@
+package grpc
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+)
+
+// HealthServer implements the standard gRPC health service (grpc.health.v1.Health) backed by a check function
+//
+// The serving status of the server ("" service) and of every known service is SERVING when the check succeeds,
+// NOT_SERVING otherwise.
+//
+// Watch streams share the status, so the check runs at most once per watch interval whatever the number of streams.
+type HealthServer struct {
+	healthpb.UnimplementedHealthServer
+
+	check         func(context.Context) error
+	watchInterval time.Duration
+
+	mux      sync.RWMutex
+	services map[string]struct{}
+
+	watchMux     sync.Mutex
+	watchStatus  healthpb.HealthCheckResponse_ServingStatus
+	watchChecked time.Time
+}
+
+type HealthServerOption func(*HealthServer)
+
+// WithWatchInterval sets the interval at which the check is run for Watch streams (default 5s)
+func WithWatchInterval(interval time.Duration) HealthServerOption {
+	return func(s *HealthServer) {
+		s.watchInterval = interval
+	}
+}
+
+// NewHealthServer creates a HealthServer reporting the result of check
+func NewHealthServer(check func(context.Context) error, opts ...HealthServerOption) *HealthServer {
+	s := &HealthServer{
+		check:         check,
+		watchInterval: 5 * time.Second,
+		services:      make(map[string]struct{}),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// AddServices declares services whose status can be requested
+// Requests for other services fail with codes.NotFound.
+func (s *HealthServer) AddServices(services ...string) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	for _, service := range services {
+		s.services[service] = struct{}{}
+	}
+}
+
+// Register registers the health service on the registrar
+func (s *HealthServer) Register(registrar grpc.ServiceRegistrar) {
+	healthpb.RegisterHealthServer(registrar, s)
+	s.AddServices(healthpb.Health_ServiceDesc.ServiceName)
+}
+
+func (s *HealthServer) Check(ctx context.Context, req *healthpb.HealthCheckRequest) (*healthpb.HealthCheckResponse, error) {
+	if !s.known(req.GetService()) {
+		return nil, status.Errorf(codes.NotFound, "unknown service %q", req.GetService())
+	}
+	return &healthpb.HealthCheckResponse{Status: s.status(ctx)}, nil
+}
+
+// Watch sends the serving status of the service, then sends it again every time it changes
+func (s *HealthServer) Watch(req *healthpb.HealthCheckRequest, stream grpc.ServerStreamingServer[healthpb.HealthCheckResponse]) error {
+	ctx := stream.Context()
+
+	last := healthpb.HealthCheckResponse_UNKNOWN
+	ticker := time.NewTicker(s.watchInterval)
+	defer ticker.Stop()
+	for {
+		current := healthpb.HealthCheckResponse_SERVICE_UNKNOWN
+		if s.known(req.GetService()) {
+			current = s.sharedStatus(ctx)
+		}
+
+		if current != last {
+			if err := stream.Send(&healthpb.HealthCheckResponse{Status: current}); err != nil {
+				return err
+			}
+			last = current
+		}
+
+		select {
+		case <-ctx.Done():
+			return status.FromContextError(ctx.Err()).Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *HealthServer) known(service string) bool {
+	if service == "" {
+		return true
+	}
+
+	s.mux.RLock()
+	defer s.mux.RUnlock()
+	_, ok := s.services[service]
+	return ok
+}
+
+func (s *HealthServer) status(ctx context.Context) healthpb.HealthCheckResponse_ServingStatus {
+	if err := s.check(ctx); err != nil {
+		return healthpb.HealthCheckResponse_NOT_SERVING
+	}
+	return healthpb.HealthCheckResponse_SERVING
+}
+
+// sharedStatus returns the status shared by Watch streams, running the check if it is older than the watch interval
+func (s *HealthServer) sharedStatus(ctx context.Context) healthpb.HealthCheckResponse_ServingStatus {
+	s.watchMux.Lock()
+	defer s.watchMux.Unlock()
+
+	if !s.watchChecked.IsZero() && time.Since(s.watchChecked) < s.watchInterval {
+		return s.watchStatus
+	}
+
+	// The status is shared, so it must not depend on the cancellation of the stream running the check
+	checkCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), s.watchInterval)
+	defer cancel()
+	s.watchStatus = s.status(checkCtx)
+	s.watchChecked = time.Now()
+	return s.watchStatus
+}
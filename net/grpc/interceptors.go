@@ -0,0 +1,192 @@
+package grpc
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/nmvalera/go-utils/log"
+	"github.com/nmvalera/go-utils/tag"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+// ContextUnaryServerInterceptor makes the values of the base context (e.g. logger and tags) available on the request context,
+// and adds a "grpc.method" tag
+//
+// It is typically used with the run context of the entrypoint, so requests hold the app and service tags
+// (as the base context of an HTTP entrypoint).
+func ContextUnaryServerInterceptor(base func() context.Context) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		return handler(withBaseContext(ctx, base(), info.FullMethod), req)
+	}
+}
+
+// ContextStreamServerInterceptor is the stream equivalent of ContextUnaryServerInterceptor
+func ContextStreamServerInterceptor(base func() context.Context) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return handler(srv, &serverStream{ServerStream: ss, ctx: withBaseContext(ss.Context(), base(), info.FullMethod)})
+	}
+}
+
+func withBaseContext(ctx, base context.Context, fullMethod string) context.Context {
+	return tag.WithTags(&valuesContext{Context: ctx, base: base}, tag.Key("grpc.method").String(fullMethod))
+}
+
+// valuesContext is a context falling back to the values of a base context
+// Cancellation and deadline are the ones of the request context.
+type valuesContext struct {
+	context.Context
+	base context.Context
+}
+
+func (c *valuesContext) Value(key any) any {
+	if v := c.Context.Value(key); v != nil {
+		return v
+	}
+	return c.base.Value(key)
+}
+
+// serverStream overrides the context of a grpc.ServerStream
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
+
+// LoggingUnaryServerInterceptor logs every request with the logger of the request context
+func LoggingUnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		logRequest(ctx, info.FullMethod, start, err)
+		return resp, err
+	}
+}
+
+// LoggingStreamServerInterceptor logs every stream with the logger of the stream context
+func LoggingStreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		err := handler(srv, ss)
+		logRequest(ss.Context(), info.FullMethod, start, err)
+		return err
+	}
+}
+
+func logRequest(ctx context.Context, fullMethod string, start time.Time, err error) {
+	fields := []zap.Field{
+		zap.String("grpc.method", fullMethod),
+		zap.String("grpc.code", status.Code(err).String()),
+		zap.Duration("duration", time.Since(start)),
+	}
+	if err != nil {
+		fields = append(fields, zap.Error(err))
+	}
+	log.LoggerFromContext(ctx).Info("gRPC request", fields...)
+}
+
+// ServerMetrics collects Prometheus metrics of the requests served by a gRPC server
+type ServerMetrics struct {
+	started  *prometheus.CounterVec
+	handled  *prometheus.CounterVec
+	duration *prometheus.HistogramVec
+}
+
+// NewServerMetrics creates a new ServerMetrics
+func NewServerMetrics() *ServerMetrics {
+	m := new(ServerMetrics)
+	m.SetMetrics("", "")
+	return m
+}
+
+func (m *ServerMetrics) SetMetrics(system, subsystem string, _ ...*tag.Tag) {
+	labels := []string{"grpc_type", "grpc_service", "grpc_method"}
+	m.started = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: system,
+		Subsystem: subsystem,
+		Name:      "grpc_server_started_total",
+		Help:      "Total number of RPCs started on the server",
+	}, labels)
+	m.handled = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: system,
+		Subsystem: subsystem,
+		Name:      "grpc_server_handled_total",
+		Help:      "Total number of RPCs completed on the server, regardless of success or failure",
+	}, append(labels, "grpc_code"))
+	m.duration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: system,
+		Subsystem: subsystem,
+		Name:      "grpc_server_handling_seconds",
+		Help:      "Duration of RPCs handled by the server",
+		Buckets:   prometheus.DefBuckets,
+	}, labels)
+}
+
+func (m *ServerMetrics) Describe(ch chan<- *prometheus.Desc) {
+	m.started.Describe(ch)
+	m.handled.Describe(ch)
+	m.duration.Describe(ch)
+}
+
+func (m *ServerMetrics) Collect(ch chan<- prometheus.Metric) {
+	m.started.Collect(ch)
+	m.handled.Collect(ch)
+	m.duration.Collect(ch)
+}
+
+// UnaryServerInterceptor returns an interceptor recording metrics of unary RPCs
+func (m *ServerMetrics) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		done := m.observe("unary", info.FullMethod)
+		resp, err := handler(ctx, req)
+		done(err)
+		return resp, err
+	}
+}
+
+// StreamServerInterceptor returns an interceptor recording metrics of streaming RPCs
+func (m *ServerMetrics) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		done := m.observe(streamType(info), info.FullMethod)
+		err := handler(srv, ss)
+		done(err)
+		return err
+	}
+}
+
+func (m *ServerMetrics) observe(rpcType, fullMethod string) func(err error) {
+	service, method := splitMethod(fullMethod)
+	m.started.WithLabelValues(rpcType, service, method).Inc()
+
+	start := time.Now()
+	return func(err error) {
+		m.handled.WithLabelValues(rpcType, service, method, status.Code(err).String()).Inc()
+		m.duration.WithLabelValues(rpcType, service, method).Observe(time.Since(start).Seconds())
+	}
+}
+
+func streamType(info *grpc.StreamServerInfo) string {
+	switch {
+	case info.IsClientStream && info.IsServerStream:
+		return "bidi_stream"
+	case info.IsClientStream:
+		return "client_stream"
+	default:
+		return "server_stream"
+	}
+}
+
+// splitMethod splits a full method name "/package.Service/Method" into service and method
+func splitMethod(fullMethod string) (service, method string) {
+	fullMethod = strings.TrimPrefix(fullMethod, "/")
+	if i := strings.LastIndex(fullMethod, "/"); i >= 0 {
+		return fullMethod[:i], fullMethod[i+1:]
+	}
+	return "unknown", fullMethod
+}
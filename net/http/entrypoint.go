@@ -145,6 +145,23 @@ func (ep *Entrypoint) Stop(stopCtx context.Context) error {
 	return nil
 }
 
+// Drain stops accepting new connections and waits for in-flight requests to complete
+// Stop MUST still be called afterwards to release resources
+func (ep *Entrypoint) Drain(ctx context.Context) error {
+	logger := log.LoggerFromContext(ctx)
+	logger.Info("Entrypoint draining...")
+
+	err := ep.server.Shutdown(ctx)
+	if err != nil {
+		logger.Error("Error while draining entrypoint", zap.Error(err))
+		return err
+	}
+
+	logger.Info("Entrypoint successfully drained")
+
+	return nil
+}
+
 func (ep *Entrypoint) listen(startCtx context.Context) (net.Listener, error) {
 	logger := log.LoggerFromContext(startCtx)
 
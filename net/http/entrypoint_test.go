@@ -40,6 +40,27 @@ func TestEntrypoint(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestEntrypointDrain(t *testing.T) {
+	ep, err := NewEntrypoint("")
+	require.NoError(t, err)
+	ep.SetHandler(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	err = ep.Start(context.Background())
+	require.NoError(t, err)
+	addr := ep.Addr()
+
+	err = ep.Drain(context.Background())
+	require.NoError(t, err)
+
+	// New connections are refused once drained
+	_, err = net.Dial("tcp", addr)
+	require.Error(t, err)
+
+	err = ep.Stop(context.Background())
+	require.NoError(t, err)
+}
+
 func TestOptions(t *testing.T) {
 	t.Run("WithServer", func(t *testing.T) {
 		srv := &http.Server{}
@@ -0,0 +1,56 @@
+package middleware
+
+import (
+	"time"
+
+	"github.com/nmvalera/go-utils/common"
+	"github.com/nmvalera/go-utils/config"
+)
+
+// DefaultConfig returns a default Config
+//
+// Request IDs, panic recovery and metrics are enabled, other middlewares are disabled.
+func DefaultConfig() *Config {
+	return &Config{
+		RequestIDHeader: common.Ptr(DefaultRequestIDHeader),
+		Recovery:        common.Ptr(true),
+		Metrics:         common.Ptr(true),
+		MaxBodyBytes:    common.Ptr(int64(0)),
+		Timeout:         common.Ptr(time.Duration(0)),
+		RateLimit: &RateLimitConfig{
+			Limit: common.Ptr(float64(0)),
+			Burst: common.Ptr(1),
+		},
+		CORS: &CORSConfig{
+			AllowedMethods:   []string{"GET", "HEAD", "POST", "PUT", "PATCH", "DELETE"},
+			AllowedHeaders:   []string{"Content-Type", "Authorization", DefaultRequestIDHeader},
+			AllowCredentials: common.Ptr(false),
+			MaxAge:           common.Ptr(10 * time.Minute),
+		},
+	}
+}
+
+// Config is the configuration of the middleware Suite
+type Config struct {
+	RequestIDHeader *string          `key:"requestIdHeader,omitempty" env:"REQUEST_ID_HEADER" flag:"request-id-header" desc:"Header holding the request ID (empty disables request IDs)"`
+	Recovery        *bool            `key:"recovery,omitempty" env:"RECOVERY" flag:"recovery" desc:"Recover panics of handlers and respond with 500"`
+	Metrics         *bool            `key:"metrics,omitempty" env:"METRICS" flag:"metrics" desc:"Record latency and status metrics per route"`
+	MaxBodyBytes    *int64           `key:"maxBodyBytes,omitempty" env:"MAX_BODY_BYTES" flag:"max-body-bytes" desc:"Maximum size of request bodies in bytes (zero means no limit)"`
+	Timeout         *time.Duration   `key:"timeout,omitempty" env:"TIMEOUT" flag:"timeout" desc:"Maximum duration to handle a request before responding with 503 (zero means no timeout)"`
+	RateLimit       *RateLimitConfig `key:"rateLimit,omitempty" env:"RATE_LIMIT" flag:"rate-limit"`
+	CORS            *CORSConfig      `key:"cors,omitempty" env:"CORS" flag:"cors"`
+}
+
+func (cfg *Config) MarshalJSON() ([]byte, error) {
+	return config.Marshal(cfg)
+}
+
+// RateLimitConfig is the configuration of the rate limiting middleware
+type RateLimitConfig struct {
+	Limit *float64 `key:"limit,omitempty" env:"LIMIT" flag:"limit" desc:"Maximum number of requests per second per client IP (zero means no limit)"`
+	Burst *int     `key:"burst,omitempty" env:"BURST" flag:"burst" desc:"Maximum burst of requests per client IP"`
+}
+
+func (cfg *RateLimitConfig) MarshalJSON() ([]byte, error) {
+	return config.Marshal(cfg)
+}
@@ -0,0 +1,115 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nmvalera/go-utils/common"
+	"github.com/nmvalera/go-utils/config"
+)
+
+// ErrCORSCredentialsWithAnyOrigin is returned when credentials are allowed for any origin,
+// which would let any website make credentialed requests
+var ErrCORSCredentialsWithAnyOrigin = errors.New("cors: credentials can only be allowed for explicit origins")
+
+// CORSConfig is the configuration of the CORS middleware
+type CORSConfig struct {
+	AllowedOrigins   []string       `key:"allowedOrigins,omitempty" env:"ALLOWED_ORIGINS" flag:"allowed-origins" desc:"Origins allowed to make cross-origin requests (* allows any origin and empty disables CORS)"`
+	AllowedMethods   []string       `key:"allowedMethods,omitempty" env:"ALLOWED_METHODS" flag:"allowed-methods" desc:"Methods allowed in cross-origin requests"`
+	AllowedHeaders   []string       `key:"allowedHeaders,omitempty" env:"ALLOWED_HEADERS" flag:"allowed-headers" desc:"Headers allowed in cross-origin requests"`
+	ExposedHeaders   []string       `key:"exposedHeaders,omitempty" env:"EXPOSED_HEADERS" flag:"exposed-headers" desc:"Response headers exposed to cross-origin requests"`
+	AllowCredentials *bool          `key:"allowCredentials,omitempty" env:"ALLOW_CREDENTIALS" flag:"allow-credentials" desc:"Allow cross-origin requests with credentials (cookies and authorization headers), origins must then be explicit"`
+	MaxAge           *time.Duration `key:"maxAge,omitempty" env:"MAX_AGE" flag:"max-age" desc:"Duration the results of a preflight request can be cached"`
+}
+
+func (cfg *CORSConfig) MarshalJSON() ([]byte, error) {
+	return config.Marshal(cfg)
+}
+
+// Enabled returns true if cross-origin requests are allowed from at least one origin
+func (cfg *CORSConfig) Enabled() bool {
+	return cfg != nil && len(cfg.AllowedOrigins) > 0
+}
+
+// Validate returns ErrCORSCredentialsWithAnyOrigin if credentials are allowed with the * origin
+func (cfg *CORSConfig) Validate() error {
+	if cfg == nil || !common.Val(cfg.AllowCredentials) {
+		return nil
+	}
+	for _, origin := range cfg.AllowedOrigins {
+		if origin == "*" {
+			return ErrCORSCredentialsWithAnyOrigin
+		}
+	}
+	return nil
+}
+
+// CORS sets the CORS headers on responses to cross-origin requests from allowed origins
+// and responds to preflight requests with 204 No Content
+//
+// It panics if the config is invalid (see Validate).
+func CORS(cfg *CORSConfig) func(http.Handler) http.Handler {
+	if err := cfg.Validate(); err != nil {
+		panic(err)
+	}
+
+	allowAny := false
+	origins := make(map[string]bool, len(cfg.AllowedOrigins))
+	for _, origin := range cfg.AllowedOrigins {
+		if origin == "*" {
+			allowAny = true
+		}
+		origins[strings.ToLower(origin)] = true
+	}
+
+	methods := strings.Join(cfg.AllowedMethods, ", ")
+	headers := strings.Join(cfg.AllowedHeaders, ", ")
+	exposed := strings.Join(cfg.ExposedHeaders, ", ")
+	credentials := common.Val(cfg.AllowCredentials)
+	maxAge := int(common.Val(cfg.MaxAge).Seconds())
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			origin := r.Header.Get("Origin")
+			w.Header().Add("Vary", "Origin")
+			if origin == "" || (!allowAny && !origins[strings.ToLower(origin)]) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if allowAny {
+				w.Header().Set("Access-Control-Allow-Origin", "*")
+			} else {
+				w.Header().Set("Access-Control-Allow-Origin", origin)
+			}
+			if credentials {
+				w.Header().Set("Access-Control-Allow-Credentials", "true")
+			}
+
+			preflight := r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != ""
+			if !preflight {
+				if exposed != "" {
+					w.Header().Set("Access-Control-Expose-Headers", exposed)
+				}
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			w.Header().Add("Vary", "Access-Control-Request-Method")
+			w.Header().Add("Vary", "Access-Control-Request-Headers")
+			if methods != "" {
+				w.Header().Set("Access-Control-Allow-Methods", methods)
+			}
+			if headers != "" {
+				w.Header().Set("Access-Control-Allow-Headers", headers)
+			}
+			if maxAge > 0 {
+				w.Header().Set("Access-Control-Max-Age", strconv.Itoa(maxAge))
+			}
+			w.WriteHeader(http.StatusNoContent)
+		})
+	}
+}
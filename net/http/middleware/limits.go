@@ -0,0 +1,52 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+	"time"
+)
+
+// BodyLimit limits the size of request bodies to maxBytes
+//
+// Requests with a larger Content-Length are rejected with 413 Request Entity Too Large,
+// other requests fail to read bodies beyond the limit (see http.MaxBytesReader).
+func BodyLimit(maxBytes int64) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.ContentLength > maxBytes {
+				http.Error(w, http.StatusText(http.StatusRequestEntityTooLarge), http.StatusRequestEntityTooLarge)
+				return
+			}
+			r.Body = http.MaxBytesReader(w, r.Body, maxBytes)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// Timeout responds with 503 Service Unavailable if the next handler does not complete within timeout
+// and cancels the request context (see http.TimeoutHandler)
+//
+// Upgrade requests (e.g. websocket) are not subject to the timeout as their connection is long-lived.
+func Timeout(timeout time.Duration) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		withTimeout := http.TimeoutHandler(next, timeout, http.StatusText(http.StatusServiceUnavailable))
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if isUpgrade(r) {
+				next.ServeHTTP(w, r)
+				return
+			}
+			withTimeout.ServeHTTP(w, r)
+		})
+	}
+}
+
+func isUpgrade(r *http.Request) bool {
+	for _, v := range r.Header.Values("Connection") {
+		for _, token := range strings.Split(v, ",") {
+			if strings.EqualFold(strings.TrimSpace(token), "upgrade") {
+				return true
+			}
+		}
+	}
+	return false
+}
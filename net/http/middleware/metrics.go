@@ -0,0 +1,114 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/nmvalera/go-utils/tag"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// UnmatchedRoute is the route label of requests not matching any route of the router
+const UnmatchedRoute = "unmatched"
+
+// OtherMethod is the method label of requests with a non standard method
+const OtherMethod = "other"
+
+// Metrics records Prometheus metrics of HTTP requests per route
+//
+// Routes are identified by the path template of the gorilla/mux route matching the request (e.g. "/users/{id}"),
+// so the cardinality of the metrics is bounded. For the same reason, non standard methods are recorded as OtherMethod.
+type Metrics struct {
+	requests *prometheus.CounterVec
+	duration *prometheus.HistogramVec
+}
+
+// NewMetrics creates a new Metrics
+func NewMetrics() *Metrics {
+	m := new(Metrics)
+	m.SetMetrics("", "")
+	return m
+}
+
+// SetMetrics sets the metrics of the middleware, tags are attached to all metrics as const labels
+func (m *Metrics) SetMetrics(system, subsystem string, tags ...*tag.Tag) {
+	constLabels := prometheus.Labels(tag.Labels(tags...))
+	labels := []string{"method", "route", "code"}
+	m.requests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace:   system,
+		Subsystem:   subsystem,
+		Name:        "http_requests_total",
+		Help:        "Total number of HTTP requests by method, route and status code",
+		ConstLabels: constLabels,
+	}, labels)
+	m.duration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace:   system,
+		Subsystem:   subsystem,
+		Name:        "http_request_duration_seconds",
+		Help:        "Duration of HTTP requests by method, route and status code",
+		Buckets:     prometheus.DefBuckets,
+		ConstLabels: constLabels,
+	}, labels)
+}
+
+func (m *Metrics) Describe(ch chan<- *prometheus.Desc) {
+	m.requests.Describe(ch)
+	m.duration.Describe(ch)
+}
+
+func (m *Metrics) Collect(ch chan<- prometheus.Metric) {
+	m.requests.Collect(ch)
+	m.duration.Collect(ch)
+}
+
+// Middleware returns a middleware recording metrics of requests, identifying routes with the given router
+//
+// router is a function as the router is typically known after the middleware chain is built.
+// If it returns nil, all requests are recorded as UnmatchedRoute.
+func (m *Metrics) Middleware(router func() *mux.Router) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			srw := NewStatusResponseWriter(w)
+			next.ServeHTTP(srw, r)
+
+			labels := prometheus.Labels{
+				"method": methodLabel(r.Method),
+				"route":  routeTemplate(router(), r),
+				"code":   strconv.Itoa(srw.Status()),
+			}
+			m.requests.With(labels).Inc()
+			m.duration.With(labels).Observe(time.Since(start).Seconds())
+		})
+	}
+}
+
+// methodLabel returns the method if it is a standard HTTP method, OtherMethod otherwise
+// as the method is set by the client
+func methodLabel(method string) string {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut, http.MethodPatch,
+		http.MethodDelete, http.MethodConnect, http.MethodOptions, http.MethodTrace:
+		return method
+	}
+	return OtherMethod
+}
+
+func routeTemplate(router *mux.Router, r *http.Request) string {
+	if router == nil {
+		return UnmatchedRoute
+	}
+
+	var match mux.RouteMatch
+	if !router.Match(r, &match) || match.Route == nil {
+		return UnmatchedRoute
+	}
+
+	tpl, err := match.Route.GetPathTemplate()
+	if err != nil {
+		return UnmatchedRoute
+	}
+	return tpl
+}
@@ -0,0 +1,236 @@
+package middleware
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/justinas/alice"
+	"github.com/nmvalera/go-utils/common"
+	"github.com/nmvalera/go-utils/tag"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestID(t *testing.T) {
+	var ctxID string
+	var tags map[string]string
+	h := RequestID(DefaultRequestIDHeader)(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		ctxID = RequestIDFromContext(r.Context())
+		tags = make(map[string]string)
+		for _, t := range tag.FromContext(r.Context()) {
+			tags[string(t.Key)] = t.Value.String()
+		}
+	}))
+
+	t.Run("propagate", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/", http.NoBody)
+		req.Header.Set(DefaultRequestIDHeader, "abc")
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+
+		assert.Equal(t, "abc", rec.Header().Get(DefaultRequestIDHeader))
+		assert.Equal(t, "abc", ctxID)
+		assert.Equal(t, "abc", tags["request.id"])
+	})
+
+	t.Run("generate", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", http.NoBody))
+
+		id := rec.Header().Get(DefaultRequestIDHeader)
+		assert.Len(t, id, 36)
+		assert.Equal(t, id, ctxID)
+	})
+}
+
+func TestRecovery(t *testing.T) {
+	t.Run("panic", func(t *testing.T) {
+		h := Recovery(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+			panic("boom")
+		}))
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", http.NoBody))
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	})
+
+	t.Run("abort", func(t *testing.T) {
+		h := Recovery(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+			panic(http.ErrAbortHandler)
+		}))
+		assert.PanicsWithValue(t, http.ErrAbortHandler, func() {
+			h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", http.NoBody))
+		})
+	})
+}
+
+func TestCORS(t *testing.T) {
+	var called bool
+	h := CORS(&CORSConfig{
+		AllowedOrigins: []string{"https://example.com"},
+		AllowedMethods: []string{"GET", "POST"},
+		AllowedHeaders: []string{"Content-Type"},
+		MaxAge:         common.Ptr(time.Minute),
+	})(http.HandlerFunc(func(http.ResponseWriter, *http.Request) { called = true }))
+
+	t.Run("preflight", func(t *testing.T) {
+		called = false
+		req := httptest.NewRequest(http.MethodOptions, "/", http.NoBody)
+		req.Header.Set("Origin", "https://example.com")
+		req.Header.Set("Access-Control-Request-Method", "POST")
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+
+		assert.False(t, called)
+		assert.Equal(t, http.StatusNoContent, rec.Code)
+		assert.Equal(t, "https://example.com", rec.Header().Get("Access-Control-Allow-Origin"))
+		assert.Equal(t, "GET, POST", rec.Header().Get("Access-Control-Allow-Methods"))
+		assert.Equal(t, "Content-Type", rec.Header().Get("Access-Control-Allow-Headers"))
+		assert.Equal(t, "60", rec.Header().Get("Access-Control-Max-Age"))
+	})
+
+	t.Run("allowed origin", func(t *testing.T) {
+		called = false
+		req := httptest.NewRequest(http.MethodGet, "/", http.NoBody)
+		req.Header.Set("Origin", "https://example.com")
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+
+		assert.True(t, called)
+		assert.Equal(t, "https://example.com", rec.Header().Get("Access-Control-Allow-Origin"))
+	})
+
+	t.Run("disallowed origin", func(t *testing.T) {
+		called = false
+		req := httptest.NewRequest(http.MethodGet, "/", http.NoBody)
+		req.Header.Set("Origin", "https://other.com")
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+
+		assert.True(t, called)
+		assert.Empty(t, rec.Header().Get("Access-Control-Allow-Origin"))
+	})
+}
+
+func TestCORSCredentialsWithAnyOrigin(t *testing.T) {
+	cfg := &CORSConfig{
+		AllowedOrigins:   []string{"https://example.com", "*"},
+		AllowCredentials: common.Ptr(true),
+	}
+	require.ErrorIs(t, cfg.Validate(), ErrCORSCredentialsWithAnyOrigin)
+	assert.PanicsWithValue(t, ErrCORSCredentialsWithAnyOrigin, func() { CORS(cfg) })
+
+	_, err := New(&Config{CORS: cfg})
+	require.ErrorIs(t, err, ErrCORSCredentialsWithAnyOrigin)
+
+	// Credentials are allowed for explicit origins, and any origin is allowed without credentials
+	cfg.AllowedOrigins = []string{"https://example.com"}
+	require.NoError(t, cfg.Validate())
+	require.NoError(t, (&CORSConfig{AllowedOrigins: []string{"*"}}).Validate())
+}
+
+func TestBodyLimit(t *testing.T) {
+	h := BodyLimit(4)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		buf := make([]byte, 16)
+		if _, err := r.Body.Read(buf); err != nil && err != io.EOF {
+			w.WriteHeader(http.StatusRequestEntityTooLarge)
+		}
+	}))
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/", strings.NewReader("ok")))
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/", strings.NewReader("too large")))
+	assert.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+}
+
+func TestTimeout(t *testing.T) {
+	h := Timeout(10 * time.Millisecond)(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", http.NoBody))
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+}
+
+func TestRateLimit(t *testing.T) {
+	h := RateLimit(1, 2, ClientIP)(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+
+	serve := func(remoteAddr string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/", http.NoBody)
+		req.RemoteAddr = remoteAddr
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec
+	}
+
+	assert.Equal(t, http.StatusOK, serve("10.0.0.1:1000").Code)
+	assert.Equal(t, http.StatusOK, serve("10.0.0.1:1001").Code)
+	rec := serve("10.0.0.1:1002")
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+	assert.Equal(t, "1", rec.Header().Get("Retry-After"))
+
+	// Other clients are not limited
+	assert.Equal(t, http.StatusOK, serve("10.0.0.2:1000").Code)
+}
+
+func TestSuite(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.MaxBodyBytes = common.Ptr(int64(4))
+	s, err := New(cfg)
+	require.NoError(t, err)
+	s.SetMetrics("test", "middleware", tag.Key("env").String("test"))
+
+	router := mux.NewRouter()
+	s.RegisterHandler(router)
+	router.HandleFunc("/users/{id}", func(http.ResponseWriter, *http.Request) {}).Methods(http.MethodGet)
+	router.HandleFunc("/panic", func(http.ResponseWriter, *http.Request) { panic("boom") })
+
+	h := s.RegisterMiddleware(alice.New()).Then(router)
+
+	for _, path := range []string{"/users/1", "/users/2", "/panic", "/unknown"} {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, http.NoBody))
+		assert.NotEmpty(t, rec.Header().Get(DefaultRequestIDHeader))
+	}
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/users/1", strings.NewReader("too large")))
+	assert.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+
+	// Methods are set by the client, so non standard methods must not create new series
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("FOO", "/unknown", http.NoBody))
+
+	assert.InDelta(t, 2, testutil.ToFloat64(s.metrics.requests.WithLabelValues("GET", "/users/{id}", "200")), 0)
+	assert.InDelta(t, 1, testutil.ToFloat64(s.metrics.requests.WithLabelValues("GET", "/users/{id}", "413")), 0)
+	assert.InDelta(t, 1, testutil.ToFloat64(s.metrics.requests.WithLabelValues("GET", "/panic", "500")), 0)
+	assert.InDelta(t, 1, testutil.ToFloat64(s.metrics.requests.WithLabelValues("GET", UnmatchedRoute, "404")), 0)
+	assert.InDelta(t, 1, testutil.ToFloat64(s.metrics.requests.WithLabelValues(OtherMethod, UnmatchedRoute, "404")), 0)
+
+	reg := prometheus.NewPedanticRegistry()
+	require.NoError(t, reg.Register(s))
+	count, err := testutil.GatherAndCount(reg, "test_middleware_http_requests_total")
+	require.NoError(t, err)
+	assert.Equal(t, 5, count)
+
+	families, err := reg.Gather()
+	require.NoError(t, err)
+	for _, family := range families {
+		for _, metric := range family.GetMetric() {
+			labels := make(map[string]string)
+			for _, label := range metric.GetLabel() {
+				labels[label.GetName()] = label.GetValue()
+			}
+			assert.Equal(t, "test", labels["env"], "Tags should be attached as const labels")
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package middleware
+
+import (
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// KeyFunc returns the key identifying the client of a request for rate limiting
+type KeyFunc func(r *http.Request) string
+
+// ClientIP returns the IP of the remote address of the request
+// Forwarding headers (e.g. X-Forwarded-For) are not trusted.
+func ClientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// limiterTTL is the duration after which the limiter of an inactive client is dropped
+var limiterTTL = 3 * time.Minute
+
+// RateLimit limits each client (as identified by key) to limit requests per second with bursts of burst requests
+//
+// Requests over the limit are rejected with 429 Too Many Requests and a Retry-After header.
+func RateLimit(limit float64, burst int, key KeyFunc) func(http.Handler) http.Handler {
+	limiters := &rateLimiters{
+		limit:    rate.Limit(limit),
+		burst:    burst,
+		limiters: make(map[string]*clientLimiter),
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			reservation := limiters.get(key(r)).Reserve()
+			if delay := reservation.Delay(); delay > 0 {
+				reservation.Cancel()
+				w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(delay.Seconds()))))
+				http.Error(w, http.StatusText(http.StatusTooManyRequests), http.StatusTooManyRequests)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+type clientLimiter struct {
+	*rate.Limiter
+	lastSeen time.Time
+}
+
+// rateLimiters holds a limiter per client, dropping limiters of inactive clients
+type rateLimiters struct {
+	limit rate.Limit
+	burst int
+
+	mux       sync.Mutex
+	limiters  map[string]*clientLimiter
+	lastSweep time.Time
+}
+
+func (l *rateLimiters) get(key string) *rate.Limiter {
+	l.mux.Lock()
+	defer l.mux.Unlock()
+
+	now := time.Now()
+	if now.Sub(l.lastSweep) > limiterTTL {
+		for k, c := range l.limiters {
+			if now.Sub(c.lastSeen) > limiterTTL {
+				delete(l.limiters, k)
+			}
+		}
+		l.lastSweep = now
+	}
+
+	c, ok := l.limiters[key]
+	if !ok {
+		c = &clientLimiter{Limiter: rate.NewLimiter(l.limit, l.burst)}
+		l.limiters[key] = c
+	}
+	c.lastSeen = now
+
+	return c.Limiter
+}
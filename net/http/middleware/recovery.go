@@ -0,0 +1,41 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"runtime/debug"
+
+	"github.com/nmvalera/go-utils/log"
+	"go.uber.org/zap"
+)
+
+// Recovery recovers panics of the next handler, logs them and responds with 500 Internal Server Error
+//
+// http.ErrAbortHandler panics are propagated so the server aborts the response.
+func Recovery(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		srw := NewStatusResponseWriter(w)
+		defer func() {
+			rec := recover()
+			if rec == nil {
+				return
+			}
+			if rec == http.ErrAbortHandler {
+				panic(rec)
+			}
+
+			log.LoggerFromContext(r.Context()).Error(
+				"Panic while serving HTTP request",
+				zap.String("panic", fmt.Sprint(rec)),
+				zap.ByteString("stack", debug.Stack()),
+			)
+
+			// The status can not be changed if the handler already started writing the response
+			if !srw.wroteHeader {
+				http.Error(srw, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			}
+		}()
+
+		next.ServeHTTP(srw, r)
+	})
+}
@@ -0,0 +1,40 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/nmvalera/go-utils/tag"
+)
+
+// DefaultRequestIDHeader is the default header holding the request ID
+const DefaultRequestIDHeader = "X-Request-Id"
+
+type requestIDKey struct{}
+
+// RequestIDFromContext returns the request ID set by the RequestID middleware, or "" if there is none
+func RequestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// RequestID propagates the request ID from the given request header, or generates one if the header is not set
+//
+// The request ID is set on the response header and on the request context,
+// as a "request.id" tag so it is attached to logs (see log.LoggerFromContext).
+func RequestID(header string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			id := r.Header.Get(header)
+			if id == "" || len(id) > 128 {
+				id = uuid.NewString()
+			}
+			w.Header().Set(header, id)
+
+			ctx := context.WithValue(r.Context(), requestIDKey{}, id)
+			ctx = tag.WithTags(ctx, tag.Key("request.id").String(id))
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
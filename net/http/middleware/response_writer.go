@@ -0,0 +1,62 @@
+package middleware
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"net/http"
+)
+
+// StatusResponseWriter is an http.ResponseWriter recording the status code of the response
+// (e.g. for middlewares instrumenting requests)
+type StatusResponseWriter struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+}
+
+// NewStatusResponseWriter wraps w, the status code is http.StatusOK until a header is written
+func NewStatusResponseWriter(w http.ResponseWriter) *StatusResponseWriter {
+	return &StatusResponseWriter{ResponseWriter: w, status: http.StatusOK}
+}
+
+// Status returns the status code of the response
+func (w *StatusResponseWriter) Status() int {
+	return w.status
+}
+
+func (w *StatusResponseWriter) WriteHeader(code int) {
+	if !w.wroteHeader {
+		w.status = code
+		w.wroteHeader = true
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *StatusResponseWriter) Write(b []byte) (int, error) {
+	w.wroteHeader = true
+	return w.ResponseWriter.Write(b)
+}
+
+// Flush implements http.Flusher if the underlying ResponseWriter does
+func (w *StatusResponseWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Hijack implements http.Hijacker so connections can be upgraded (e.g. websocket)
+func (w *StatusResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	h, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, fmt.Errorf("response writer does not implement http.Hijacker")
+	}
+	w.status = http.StatusSwitchingProtocols
+	w.wroteHeader = true
+	return h.Hijack()
+}
+
+// Unwrap allows http.ResponseController to access the underlying ResponseWriter
+func (w *StatusResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
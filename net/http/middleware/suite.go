@@ -0,0 +1,102 @@
+// Package middleware provides standard HTTP middlewares
+// (request IDs, panic recovery, CORS, metrics, body size limits, timeouts and rate limiting)
+// and a Suite to set them on an App entrypoint.
+package middleware
+
+import (
+	"sync"
+
+	"github.com/gorilla/mux"
+	"github.com/justinas/alice"
+	"github.com/nmvalera/go-utils/common"
+	"github.com/nmvalera/go-utils/tag"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Suite is a service setting the middlewares enabled by its Config on an App entrypoint
+//
+// It implements svc.Middleware and svc.API (to identify routes in metrics), so it can simply be provided
+// on the entrypoint it applies to, e.g.
+//
+//	app.Provide(application, "middleware", func() (*middleware.Suite, error) {
+//		return middleware.New(cfg)
+//	}, app.WithEntrypoint("public"))
+type Suite struct {
+	cfg     *Config
+	metrics *Metrics
+
+	mux    sync.RWMutex
+	router *mux.Router
+}
+
+// New creates a new Suite
+//
+// It returns an error if the config is invalid (e.g. credentials allowed for any CORS origin).
+func New(cfg *Config) (*Suite, error) {
+	if err := cfg.CORS.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &Suite{
+		cfg:     cfg,
+		metrics: NewMetrics(),
+	}, nil
+}
+
+// RegisterHandler records the router of the entrypoint so metrics are recorded per route template
+func (s *Suite) RegisterHandler(router *mux.Router) {
+	s.mux.Lock()
+	s.router = router
+	s.mux.Unlock()
+}
+
+func (s *Suite) getRouter() *mux.Router {
+	s.mux.RLock()
+	defer s.mux.RUnlock()
+	return s.router
+}
+
+// RegisterMiddleware appends the enabled middlewares to the chain
+//
+// Middlewares are applied in order: request ID, metrics, recovery, CORS, rate limit, body limit and timeout,
+// so request IDs are available to all other middlewares and metrics record the responses of all of them.
+func (s *Suite) RegisterMiddleware(chain alice.Chain) alice.Chain {
+	if header := common.Val(s.cfg.RequestIDHeader); header != "" {
+		chain = chain.Append(RequestID(header))
+	}
+	if common.Val(s.cfg.Metrics) {
+		chain = chain.Append(s.metrics.Middleware(s.getRouter))
+	}
+	if common.Val(s.cfg.Recovery) {
+		chain = chain.Append(Recovery)
+	}
+	if s.cfg.CORS.Enabled() {
+		chain = chain.Append(CORS(s.cfg.CORS))
+	}
+	if s.cfg.RateLimit != nil && common.Val(s.cfg.RateLimit.Limit) > 0 {
+		chain = chain.Append(RateLimit(common.Val(s.cfg.RateLimit.Limit), max(common.Val(s.cfg.RateLimit.Burst), 1), ClientIP))
+	}
+	if maxBytes := common.Val(s.cfg.MaxBodyBytes); maxBytes > 0 {
+		chain = chain.Append(BodyLimit(maxBytes))
+	}
+	if timeout := common.Val(s.cfg.Timeout); timeout > 0 {
+		chain = chain.Append(Timeout(timeout))
+	}
+	return chain
+}
+
+func (s *Suite) SetMetrics(system, subsystem string, tags ...*tag.Tag) {
+	s.metrics.SetMetrics(system, subsystem, tags...)
+}
+
+func (s *Suite) Describe(ch chan<- *prometheus.Desc) {
+	if common.Val(s.cfg.Metrics) {
+		s.metrics.Describe(ch)
+	}
+}
+
+func (s *Suite) Collect(ch chan<- prometheus.Metric) {
+	if common.Val(s.cfg.Metrics) {
+		s.metrics.Collect(ch)
+	}
+}
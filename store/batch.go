@@ -0,0 +1,176 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"go.uber.org/multierr"
+)
+
+var ErrListNotSupported = errors.New("list not supported")
+
+// BatchResult is the result of an operation on a single key of a batch
+type BatchResult struct {
+	// Key is the key of the object the operation was performed on (the source key for copies)
+	Key string
+
+	// Err is the error returned by the operation (nil on success)
+	Err error
+}
+
+// CopyPair is a pair of source and destination keys to copy
+type CopyPair struct {
+	SrcKey string
+	DstKey string
+}
+
+// Lister is a store that can enumerate its keys natively.
+type Lister interface {
+	// List returns the keys of all objects which key starts with the given prefix, sorted in lexicographical order.
+	List(ctx context.Context, prefix string) ([]string, error)
+}
+
+// BatchDeleter is a store that can delete multiple objects natively.
+type BatchDeleter interface {
+	// DeleteBatch deletes the objects with the given keys.
+	// It returns a result for each key, in the same order as keys.
+	DeleteBatch(ctx context.Context, keys []string, opts ...BatchOption) []*BatchResult
+}
+
+// BatchCopier is a store that can copy multiple objects natively.
+type BatchCopier interface {
+	// CopyBatch copies the objects for the given pairs.
+	// It returns a result for each pair, in the same order as pairs.
+	CopyBatch(ctx context.Context, pairs []*CopyPair, opts ...BatchOption) []*BatchResult
+}
+
+// BatchConfig is the configuration of a batch operation
+type BatchConfig struct {
+	// Concurrency is the maximum number of operations performed in parallel
+	Concurrency int
+}
+
+type BatchOption func(*BatchConfig)
+
+// WithConcurrency sets the maximum number of operations performed in parallel in a batch
+func WithConcurrency(n int) BatchOption {
+	return func(cfg *BatchConfig) {
+		cfg.Concurrency = n
+	}
+}
+
+// NewBatchConfig returns a new batch configuration with the given options applied
+func NewBatchConfig(opts ...BatchOption) *BatchConfig {
+	cfg := &BatchConfig{
+		Concurrency: 16,
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	if cfg.Concurrency < 1 {
+		cfg.Concurrency = 1
+	}
+	return cfg
+}
+
+// DeleteBatch deletes the objects with the given keys from the store.
+//
+// If the store implements BatchDeleter, it uses the native implementation,
+// otherwise it deletes the objects in parallel using individual Delete calls.
+func DeleteBatch(ctx context.Context, s Store, keys []string, opts ...BatchOption) []*BatchResult {
+	if bd, ok := s.(BatchDeleter); ok {
+		return bd.DeleteBatch(ctx, keys, opts...)
+	}
+
+	return RunBatch(ctx, len(keys), NewBatchConfig(opts...).Concurrency, func(ctx context.Context, i int) *BatchResult {
+		return &BatchResult{Key: keys[i], Err: s.Delete(ctx, keys[i])}
+	})
+}
+
+// CopyBatch copies the objects for the given pairs in the store.
+//
+// If the store implements BatchCopier, it uses the native implementation,
+// otherwise it copies the objects in parallel using individual Copy calls.
+func CopyBatch(ctx context.Context, s Store, pairs []*CopyPair, opts ...BatchOption) []*BatchResult {
+	if bc, ok := s.(BatchCopier); ok {
+		return bc.CopyBatch(ctx, pairs, opts...)
+	}
+
+	return RunBatch(ctx, len(pairs), NewBatchConfig(opts...).Concurrency, func(ctx context.Context, i int) *BatchResult {
+		return &BatchResult{Key: pairs[i].SrcKey, Err: s.Copy(ctx, pairs[i].SrcKey, pairs[i].DstKey)}
+	})
+}
+
+// List returns the keys of all objects in the store which key starts with the given prefix.
+//
+// It returns ErrListNotSupported if the store does not implement Lister.
+func List(ctx context.Context, s Store, prefix string) ([]string, error) {
+	l, ok := s.(Lister)
+	if !ok {
+		return nil, ErrListNotSupported
+	}
+	return l.List(ctx, prefix)
+}
+
+// DeletePrefix deletes all objects in the store which key starts with the given prefix.
+//
+// It enumerates the keys using the store native listing and then deletes them using DeleteBatch.
+// It returns ErrListNotSupported if the store does not implement Lister.
+func DeletePrefix(ctx context.Context, s Store, prefix string, opts ...BatchOption) ([]*BatchResult, error) {
+	keys, err := List(ctx, s, prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(keys) == 0 {
+		return []*BatchResult{}, nil
+	}
+
+	return DeleteBatch(ctx, s, keys, opts...), nil
+}
+
+// BatchError returns the combination of all errors in the given results (nil if all operations succeeded)
+func BatchError(results []*BatchResult) error {
+	var errs []error
+	for _, res := range results {
+		if res.Err != nil {
+			errs = append(errs, res.Err)
+		}
+	}
+	return multierr.Combine(errs...)
+}
+
+// RunBatch runs n operations using at most concurrency parallel workers.
+// It returns the results in the order of the operations.
+//
+// It is intended to be used by store implementations that can not perform batch operations natively.
+// A concurrency lower than 1 runs the operations sequentially.
+func RunBatch(ctx context.Context, n, concurrency int, op func(ctx context.Context, i int) *BatchResult) []*BatchResult {
+	results := make([]*BatchResult, n)
+	if n == 0 {
+		return results
+	}
+
+	concurrency = min(max(concurrency, 1), n)
+
+	indexes := make(chan int)
+	wg := sync.WaitGroup{}
+	wg.Add(concurrency)
+	for w := 0; w < concurrency; w++ {
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				results[i] = op(ctx, i)
+			}
+		}()
+	}
+
+	for i := 0; i < n; i++ {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	return results
+}
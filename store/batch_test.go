@@ -0,0 +1,106 @@
+package store_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/nmvalera/go-utils/app/svc"
+	"github.com/nmvalera/go-utils/store"
+	"github.com/nmvalera/go-utils/store/memory"
+	"github.com/nmvalera/go-utils/store/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestDeleteBatch(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStore := mock.NewMockStore(ctrl)
+
+	ctx := context.TODO()
+	mockStore.EXPECT().Delete(ctx, "test-key-1").Return(nil)
+	mockStore.EXPECT().Delete(ctx, "test-key-2").Return(errors.New("test-error"))
+	mockStore.EXPECT().Delete(ctx, "test-key-3").Return(nil)
+
+	results := store.DeleteBatch(ctx, mockStore, []string{"test-key-1", "test-key-2", "test-key-3"}, store.WithConcurrency(2))
+	require.Len(t, results, 3)
+	assert.Equal(t, "test-key-1", results[0].Key)
+	assert.NoError(t, results[0].Err)
+	assert.Equal(t, "test-key-2", results[1].Key)
+	assert.EqualError(t, results[1].Err, "test-error")
+	assert.Equal(t, "test-key-3", results[2].Key)
+	assert.NoError(t, results[2].Err)
+	assert.EqualError(t, store.BatchError(results), "test-error")
+}
+
+func TestCopyBatch(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStore := mock.NewMockStore(ctrl)
+
+	ctx := context.TODO()
+	mockStore.EXPECT().Copy(ctx, "test-src-1", "test-dst-1").Return(nil)
+	mockStore.EXPECT().Copy(ctx, "test-src-2", "test-dst-2").Return(store.ErrNotFound)
+
+	results := store.CopyBatch(ctx, mockStore, []*store.CopyPair{
+		{SrcKey: "test-src-1", DstKey: "test-dst-1"},
+		{SrcKey: "test-src-2", DstKey: "test-dst-2"},
+	})
+	require.Len(t, results, 2)
+	assert.NoError(t, results[0].Err)
+	assert.ErrorIs(t, results[1].Err, store.ErrNotFound)
+}
+
+func TestRunBatchInvalidConcurrency(t *testing.T) {
+	for _, concurrency := range []int{0, -1} {
+		results := store.RunBatch(context.TODO(), 3, concurrency, func(_ context.Context, i int) *store.BatchResult {
+			return &store.BatchResult{Key: fmt.Sprintf("test-key-%d", i)}
+		})
+		require.Len(t, results, 3)
+		for i, res := range results {
+			assert.Equal(t, fmt.Sprintf("test-key-%d", i), res.Key)
+		}
+	}
+}
+
+func TestDeletePrefixNotSupported(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	_, err := store.DeletePrefix(context.TODO(), mock.NewMockStore(ctrl), "test-prefix")
+	require.ErrorIs(t, err, store.ErrListNotSupported)
+}
+
+func TestDecoratorsBatch(t *testing.T) {
+	metricsStore := store.WithMetrics(store.WithLog(memory.New()))
+	metricsStore.(svc.Metricable).SetMetrics("test-system", "test-subsystem")
+	s := store.WithTags(metricsStore)
+
+	require.Implements(t, (*store.Lister)(nil), s)
+	require.Implements(t, (*store.BatchDeleter)(nil), s)
+	require.Implements(t, (*store.BatchCopier)(nil), s)
+
+	ctx := context.TODO()
+	require.NoError(t, s.Store(ctx, "dir/test-key", strings.NewReader("test-value"), nil))
+
+	results := store.CopyBatch(ctx, s, []*store.CopyPair{{SrcKey: "dir/test-key", DstKey: "dir/test-key-copy"}})
+	require.NoError(t, store.BatchError(results))
+
+	keys, err := store.List(ctx, s, "dir/")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"dir/test-key", "dir/test-key-copy"}, keys)
+
+	results, err = store.DeletePrefix(ctx, s, "dir/")
+	require.NoError(t, err)
+	require.NoError(t, store.BatchError(results))
+
+	keys, err = store.List(ctx, s, "")
+	require.NoError(t, err)
+	assert.Empty(t, keys)
+}
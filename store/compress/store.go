@@ -8,6 +8,7 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"strings"
 
 	store "github.com/nmvalera/go-utils/store"
 )
@@ -36,6 +37,20 @@ func New(s store.Store, opts ...Options) (*Store, error) {
 
 // Store stores the data in the store
 func (c *Store) Store(ctx context.Context, key string, reader io.Reader, headers *store.Headers) error {
+	return c.compress(reader, headers, func(reader io.Reader, headers *store.Headers) error {
+		return c.store.Store(ctx, c.key(key), reader, headers)
+	})
+}
+
+// StoreIfMatch stores the data in the store if its ETag is etag (see store.ConditionalStore)
+func (c *Store) StoreIfMatch(ctx context.Context, key string, reader io.Reader, headers *store.Headers, etag string) error {
+	return c.compress(reader, headers, func(reader io.Reader, headers *store.Headers) error {
+		return store.StoreIfMatch(ctx, c.store, c.key(key), reader, headers, etag)
+	})
+}
+
+// compress compresses the data with the store content encoding and calls storeFn with the compressed data
+func (c *Store) compress(reader io.Reader, headers *store.Headers, storeFn func(io.Reader, *store.Headers) error) error {
 	var compressedReader io.Reader
 
 	switch c.contentEncoding {
@@ -44,12 +59,11 @@ func (c *Store) Store(ctx context.Context, key string, reader io.Reader, headers
 	case store.ContentEncodingGzip:
 		buf := new(bytes.Buffer)
 		gw := gzip.NewWriter(buf)
-		defer func() { _ = gw.Close() }()
 		if _, err := io.Copy(gw, reader); err != nil {
 			return fmt.Errorf("failed to compress with gzip: %w", err)
 		}
 
-		if err := gw.Flush(); err != nil {
+		if err := gw.Close(); err != nil {
 			return fmt.Errorf("failed to compress with gzip: %w", err)
 		}
 
@@ -58,12 +72,11 @@ func (c *Store) Store(ctx context.Context, key string, reader io.Reader, headers
 	case store.ContentEncodingZlib:
 		buf := new(bytes.Buffer)
 		zw := zlib.NewWriter(buf)
-		defer func() { _ = zw.Close() }()
 		if _, err := io.Copy(zw, reader); err != nil {
 			return fmt.Errorf("failed to compress with zlib: %w", err)
 		}
 
-		if err := zw.Flush(); err != nil {
+		if err := zw.Close(); err != nil {
 			return fmt.Errorf("failed to compress with zlib: %w", err)
 		}
 
@@ -75,12 +88,11 @@ func (c *Store) Store(ctx context.Context, key string, reader io.Reader, headers
 		if err != nil {
 			return fmt.Errorf("failed to create flate writer: %w", err)
 		}
-		defer func() { _ = fw.Close() }()
 		if _, err := io.Copy(fw, reader); err != nil {
 			return fmt.Errorf("failed to compress with flate: %w", err)
 		}
 
-		if err := fw.Flush(); err != nil {
+		if err := fw.Close(); err != nil {
 			return fmt.Errorf("failed to compress with flate: %w", err)
 		}
 
@@ -94,7 +106,7 @@ func (c *Store) Store(ctx context.Context, key string, reader io.Reader, headers
 	}
 	headers.ContentEncoding = c.contentEncoding
 
-	return c.store.Store(ctx, c.key(key), compressedReader, headers)
+	return storeFn(compressedReader, headers)
 }
 
 // Load loads the data from the store
@@ -105,6 +117,26 @@ func (c *Store) Load(ctx context.Context, key string) (io.ReadCloser, *store.Hea
 		return nil, nil, err
 	}
 
+	return c.decompress(reader, headers)
+}
+
+// LoadWithETag loads the data from the store with its ETag (see store.ConditionalStore)
+// It is the responsibility of the caller to close the returned reader
+func (c *Store) LoadWithETag(ctx context.Context, key string) (io.ReadCloser, string, error) {
+	reader, etag, err := store.LoadWithETag(ctx, c.store, c.key(key))
+	if err != nil {
+		return nil, "", err
+	}
+
+	reader, _, err = c.decompress(reader, nil)
+	if err != nil {
+		return nil, "", err
+	}
+	return reader, etag, nil
+}
+
+// decompress decompresses the data loaded from the store with the store content encoding
+func (c *Store) decompress(reader io.ReadCloser, headers *store.Headers) (io.ReadCloser, *store.Headers, error) {
 	if headers == nil {
 		headers = &store.Headers{}
 	}
@@ -136,10 +168,65 @@ func (c *Store) Delete(ctx context.Context, key string) error {
 	return c.store.Delete(ctx, c.key(key))
 }
 
+// DeleteIfMatch deletes the data from the store if its ETag is etag (see store.ConditionalStore)
+func (c *Store) DeleteIfMatch(ctx context.Context, key, etag string) error {
+	return store.DeleteIfMatch(ctx, c.store, c.key(key), etag)
+}
+
 func (c *Store) Copy(ctx context.Context, srcKey, dstKey string) error {
 	return c.store.Copy(ctx, c.key(srcKey), c.key(dstKey))
 }
 
+// DeleteBatch deletes the objects with the given keys, natively if the wrapped store implements store.BatchDeleter
+func (c *Store) DeleteBatch(ctx context.Context, keys []string, opts ...store.BatchOption) []*store.BatchResult {
+	storeKeys := make([]string, len(keys))
+	for i, key := range keys {
+		storeKeys[i] = c.key(key)
+	}
+
+	results := store.DeleteBatch(ctx, c.store, storeKeys, opts...)
+	for i, res := range results {
+		res.Key = keys[i]
+	}
+	return results
+}
+
+// CopyBatch copies the objects for the given pairs, natively if the wrapped store implements store.BatchCopier
+func (c *Store) CopyBatch(ctx context.Context, pairs []*store.CopyPair, opts ...store.BatchOption) []*store.BatchResult {
+	storePairs := make([]*store.CopyPair, len(pairs))
+	for i, pair := range pairs {
+		storePairs[i] = &store.CopyPair{SrcKey: c.key(pair.SrcKey), DstKey: c.key(pair.DstKey)}
+	}
+
+	results := store.CopyBatch(ctx, c.store, storePairs, opts...)
+	for i, res := range results {
+		res.Key = pairs[i].SrcKey
+	}
+	return results
+}
+
+// List returns the keys of all objects which key starts with the given prefix
+// It only returns the keys of objects stored with the store content encoding (with the file extension removed)
+func (c *Store) List(ctx context.Context, prefix string) ([]string, error) {
+	keys, err := store.List(ctx, c.store, prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	ext := c.contentEncoding.FileExtension()
+	if ext == "" {
+		return keys, nil
+	}
+
+	res := make([]string, 0, len(keys))
+	for _, key := range keys {
+		if trimmed, ok := strings.CutSuffix(key, "."+ext); ok {
+			res = append(res, trimmed)
+		}
+	}
+	return res, nil
+}
+
 func (c *Store) key(key string) string {
 	return c.contentEncoding.FilePath(key)
 }
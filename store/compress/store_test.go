@@ -3,6 +3,7 @@ package compress
 import (
 	"bytes"
 	"context"
+	"io"
 	"testing"
 
 	store "github.com/nmvalera/go-utils/store"
@@ -15,6 +16,8 @@ import (
 
 func TestImplementsStore(t *testing.T) {
 	assert.Implements(t, (*store.Store)(nil), new(Store))
+	assert.Implements(t, (*store.BatchDeleter)(nil), new(Store))
+	assert.Implements(t, (*store.BatchCopier)(nil), new(Store))
 }
 
 func TestStore(t *testing.T) {
@@ -88,11 +91,91 @@ func TestStore(t *testing.T) {
 			reader, _, err := s.Load(ctx, tt.key)
 			require.NoError(t, err)
 
-			b := make([]byte, 512)
-			n, err := reader.Read(b)
+			b, err := io.ReadAll(reader)
 			require.NoError(t, err)
-			assert.Equal(t, len(tt.data), n)
-			assert.Equal(t, string(tt.data), string(b[:n]))
+			assert.Equal(t, string(tt.data), string(b))
 		})
 	}
 }
+
+func TestList(t *testing.T) {
+	memStore := memory.New()
+	s, err := New(memStore, WithContentEncoding(store.ContentEncodingGzip))
+	require.NoError(t, err)
+
+	ctx := context.TODO()
+	require.NoError(t, s.Store(ctx, "dir/test1", bytes.NewReader([]byte("test")), nil))
+	require.NoError(t, memStore.Store(ctx, "dir/test2", bytes.NewReader([]byte("test")), nil))
+
+	keys, err := store.List(ctx, s, "dir/")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"dir/test1"}, keys)
+}
+
+func TestConditional(t *testing.T) {
+	memStore := memory.New()
+	s, err := New(memStore, WithContentEncoding(store.ContentEncodingGzip))
+	require.NoError(t, err)
+
+	ctx := context.TODO()
+	require.NoError(t, s.StoreIfMatch(ctx, "test", bytes.NewReader([]byte("message to compress")), nil, ""))
+	require.ErrorIs(t, s.StoreIfMatch(ctx, "test", bytes.NewReader([]byte("message to compress")), nil, ""), store.ErrPreconditionFailed)
+
+	reader, etag, err := s.LoadWithETag(ctx, "test")
+	require.NoError(t, err)
+	b, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	require.NoError(t, reader.Close())
+	assert.Equal(t, "message to compress", string(b))
+
+	_, memETag, err := store.LoadWithETag(ctx, memStore, "test.gz")
+	require.NoError(t, err)
+	assert.Equal(t, memETag, etag)
+
+	require.NoError(t, s.DeleteIfMatch(ctx, "test", etag))
+	_, _, err = s.LoadWithETag(ctx, "test")
+	require.ErrorIs(t, err, store.ErrNotFound)
+}
+
+// memoryStore is embedded under another name than its Store method
+type memoryStore = memory.Store
+
+// batchStore is a memory store recording the native batch operations
+type batchStore struct {
+	*memoryStore
+	deleted []string
+	copied  []*store.CopyPair
+}
+
+func (s *batchStore) DeleteBatch(ctx context.Context, keys []string, _ ...store.BatchOption) []*store.BatchResult {
+	s.deleted = append(s.deleted, keys...)
+	return store.DeleteBatch(ctx, s.memoryStore, keys)
+}
+
+func (s *batchStore) CopyBatch(ctx context.Context, pairs []*store.CopyPair, _ ...store.BatchOption) []*store.BatchResult {
+	s.copied = append(s.copied, pairs...)
+	return store.CopyBatch(ctx, s.memoryStore, pairs)
+}
+
+func TestBatch(t *testing.T) {
+	batch := &batchStore{memoryStore: memory.New().(*memory.Store)}
+	s, err := New(batch, WithContentEncoding(store.ContentEncodingGzip))
+	require.NoError(t, err)
+
+	ctx := context.TODO()
+	require.NoError(t, s.Store(ctx, "test1", bytes.NewReader([]byte("test")), nil))
+
+	results := store.CopyBatch(ctx, s, []*store.CopyPair{{SrcKey: "test1", DstKey: "test2"}})
+	require.NoError(t, store.BatchError(results))
+	assert.Equal(t, "test1", results[0].Key)
+	assert.Equal(t, []*store.CopyPair{{SrcKey: "test1.gz", DstKey: "test2.gz"}}, batch.copied, "Copies should be forwarded to the wrapped store")
+
+	results = store.DeleteBatch(ctx, s, []string{"test1", "test2"})
+	require.NoError(t, store.BatchError(results))
+	assert.Equal(t, "test1", results[0].Key)
+	assert.Equal(t, "test2", results[1].Key)
+	assert.Equal(t, []string{"test1.gz", "test2.gz"}, batch.deleted, "Deletes should be forwarded to the wrapped store")
+
+	_, _, err = s.Load(ctx, "test2")
+	require.ErrorIs(t, err, store.ErrNotFound)
+}
@@ -0,0 +1,66 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"io"
+)
+
+// ErrPreconditionFailed is returned by a ConditionalStore when the object has been modified since it has been loaded
+var ErrPreconditionFailed = errors.New("precondition failed")
+
+// ErrConditionalNotSupported is returned when a conditional operation is performed on a store that is not a ConditionalStore
+var ErrConditionalNotSupported = errors.New("conditional writes not supported")
+
+// ConditionalStore is a store supporting conditional writes (e.g. If-Match/If-None-Match on an ETag)
+type ConditionalStore interface {
+	// LoadWithETag loads an object from the store with its ETag
+	//
+	// It returns ErrNotFound if the object does not exist.
+	// It is the responsibility of the caller to close the returned reader
+	LoadWithETag(ctx context.Context, key string) (io.ReadCloser, string, error)
+
+	// StoreIfMatch stores an object only if its current ETag is etag,
+	// or only if it does not exist when etag is empty
+	//
+	// It returns ErrPreconditionFailed otherwise.
+	StoreIfMatch(ctx context.Context, key string, reader io.Reader, headers *Headers, etag string) error
+
+	// DeleteIfMatch deletes an object only if its current ETag is etag
+	//
+	// It returns ErrPreconditionFailed otherwise.
+	DeleteIfMatch(ctx context.Context, key, etag string) error
+}
+
+// LoadWithETag loads an object from the store with its ETag.
+//
+// It returns ErrConditionalNotSupported if the store does not implement ConditionalStore.
+func LoadWithETag(ctx context.Context, s Store, key string) (io.ReadCloser, string, error) {
+	cs, ok := s.(ConditionalStore)
+	if !ok {
+		return nil, "", ErrConditionalNotSupported
+	}
+	return cs.LoadWithETag(ctx, key)
+}
+
+// StoreIfMatch stores an object only if its current ETag is etag (or if it does not exist when etag is empty).
+//
+// It returns ErrConditionalNotSupported if the store does not implement ConditionalStore.
+func StoreIfMatch(ctx context.Context, s Store, key string, reader io.Reader, headers *Headers, etag string) error {
+	cs, ok := s.(ConditionalStore)
+	if !ok {
+		return ErrConditionalNotSupported
+	}
+	return cs.StoreIfMatch(ctx, key, reader, headers, etag)
+}
+
+// DeleteIfMatch deletes an object only if its current ETag is etag.
+//
+// It returns ErrConditionalNotSupported if the store does not implement ConditionalStore.
+func DeleteIfMatch(ctx context.Context, s Store, key, etag string) error {
+	cs, ok := s.(ConditionalStore)
+	if !ok {
+		return ErrConditionalNotSupported
+	}
+	return cs.DeleteIfMatch(ctx, key, etag)
+}
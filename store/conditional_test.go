@@ -0,0 +1,55 @@
+package store_test
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/nmvalera/go-utils/app/svc"
+	"github.com/nmvalera/go-utils/store"
+	"github.com/nmvalera/go-utils/store/memory"
+	"github.com/nmvalera/go-utils/store/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestConditionalNotSupported(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	s := store.WithTags(mock.NewMockStore(ctrl))
+	_, _, err := store.LoadWithETag(context.TODO(), s, "test-key")
+	require.ErrorIs(t, err, store.ErrConditionalNotSupported)
+	err = store.StoreIfMatch(context.TODO(), s, "test-key", strings.NewReader("test-value"), nil, "")
+	require.ErrorIs(t, err, store.ErrConditionalNotSupported)
+	err = store.DeleteIfMatch(context.TODO(), s, "test-key", "test-etag")
+	require.ErrorIs(t, err, store.ErrConditionalNotSupported)
+}
+
+func TestDecoratorsConditional(t *testing.T) {
+	metricsStore := store.WithMetrics(store.WithLog(store.WithTracing(memory.New())))
+	metricsStore.(svc.Metricable).SetMetrics("test-system", "test-subsystem")
+	s := store.WithTags(metricsStore)
+	require.Implements(t, (*store.ConditionalStore)(nil), s)
+	cs := s.(store.ConditionalStore)
+
+	ctx := context.TODO()
+	require.NoError(t, cs.StoreIfMatch(ctx, "test-key", strings.NewReader("test-value"), nil, ""))
+	err := cs.StoreIfMatch(ctx, "test-key", strings.NewReader("test-value"), nil, "")
+	require.ErrorIs(t, err, store.ErrPreconditionFailed)
+
+	reader, etag, err := cs.LoadWithETag(ctx, "test-key")
+	require.NoError(t, err)
+	b, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	require.NoError(t, reader.Close())
+	assert.Equal(t, "test-value", string(b))
+
+	require.ErrorIs(t, cs.DeleteIfMatch(ctx, "test-key", "stale-etag"), store.ErrPreconditionFailed)
+	require.NoError(t, cs.DeleteIfMatch(ctx, "test-key", etag))
+
+	_, _, err = cs.LoadWithETag(ctx, "test-key")
+	require.ErrorIs(t, err, store.ErrNotFound)
+}
@@ -2,10 +2,14 @@ package file
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
+	"io/fs"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
 
 	"github.com/nmvalera/go-utils/store"
 )
@@ -98,6 +102,46 @@ func (f *Store) Delete(_ context.Context, key string) error {
 	return os.Remove(filePath)
 }
 
+// List returns the keys of all objects which key starts with the given prefix by walking the data directory
+func (f *Store) List(_ context.Context, prefix string) ([]string, error) {
+	// Only walk the deepest directory containing all keys with the prefix
+	root := f.dataDir
+	if i := strings.LastIndex(prefix, "/"); i >= 0 {
+		root = f.filePath(prefix[:i])
+	}
+
+	keys := make([]string, 0)
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+
+		if d.IsDir() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(f.dataDir, path)
+		if err != nil {
+			return err
+		}
+
+		key := filepath.ToSlash(rel)
+		if strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list files: %w", err)
+	}
+
+	sort.Strings(keys)
+	return keys, nil
+}
+
 func (f *Store) filePath(key string) string {
 	return filepath.Join(f.dataDir, key)
 }
@@ -124,3 +124,33 @@ func TestFileStore(t *testing.T) {
 		})
 	}
 }
+
+func TestFileStoreList(t *testing.T) {
+	s := New(t.TempDir())
+	ctx := context.Background()
+
+	for _, key := range []string{"a/b/2", "a/b/1", "a/c", "ab", "b/1"} {
+		require.NoError(t, s.Store(ctx, key, bytes.NewReader([]byte(key)), nil))
+	}
+
+	keys, err := s.List(ctx, "a/b/")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a/b/1", "a/b/2"}, keys)
+
+	keys, err = s.List(ctx, "a")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a/b/1", "a/b/2", "a/c", "ab"}, keys)
+
+	keys, err = s.List(ctx, "unknown/")
+	require.NoError(t, err)
+	assert.Empty(t, keys)
+
+	results, err := store.DeletePrefix(ctx, s, "a/")
+	require.NoError(t, err)
+	require.Len(t, results, 3)
+	require.NoError(t, store.BatchError(results))
+
+	keys, err = s.List(ctx, "")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"ab", "b/1"}, keys)
+}
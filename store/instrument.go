@@ -2,13 +2,18 @@ package store
 
 import (
 	"context"
+	"errors"
 	"io"
+	"sync"
 	"time"
 
 	"github.com/nmvalera/go-utils/app/svc"
 	"github.com/nmvalera/go-utils/log"
 	"github.com/nmvalera/go-utils/tag"
+	"github.com/nmvalera/go-utils/tracing"
 	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 )
 
@@ -34,6 +39,18 @@ func (s *taggable) Delete(ctx context.Context, key string) error {
 	return s.store.Delete(s.context(ctx, key, nil), key)
 }
 
+func (s *taggable) LoadWithETag(ctx context.Context, key string) (io.ReadCloser, string, error) {
+	return LoadWithETag(s.context(ctx, key, nil), s.store, key)
+}
+
+func (s *taggable) StoreIfMatch(ctx context.Context, key string, reader io.Reader, headers *Headers, etag string) error {
+	return StoreIfMatch(s.context(ctx, key, headers), s.store, key, reader, headers, etag)
+}
+
+func (s *taggable) DeleteIfMatch(ctx context.Context, key, etag string) error {
+	return DeleteIfMatch(s.context(ctx, key, nil), s.store, key, etag)
+}
+
 func (s *taggable) Copy(ctx context.Context, srcKey, dstKey string) error {
 	tags := []*tag.Tag{
 		tag.Key("store.src_key").String(srcKey),
@@ -42,6 +59,22 @@ func (s *taggable) Copy(ctx context.Context, srcKey, dstKey string) error {
 	return s.store.Copy(s.Context(ctx, tags...), srcKey, dstKey)
 }
 
+func (s *taggable) List(ctx context.Context, prefix string) ([]string, error) {
+	return List(s.Context(ctx, tag.Key("store.prefix").String(prefix)), s.store, prefix)
+}
+
+func (s *taggable) DeleteBatch(ctx context.Context, keys []string, opts ...BatchOption) []*BatchResult {
+	return DeleteBatch(s.batchContext(ctx, len(keys)), s.store, keys, opts...)
+}
+
+func (s *taggable) CopyBatch(ctx context.Context, pairs []*CopyPair, opts ...BatchOption) []*BatchResult {
+	return CopyBatch(s.batchContext(ctx, len(pairs)), s.store, pairs, opts...)
+}
+
+func (s *taggable) batchContext(ctx context.Context, size int) context.Context {
+	return s.Context(ctx, tag.Key("store.batch_size").Int64(int64(size)))
+}
+
 func (s *taggable) context(ctx context.Context, key string, headers *Headers) context.Context {
 	tags := []*tag.Tag{
 		tag.Key("store.key").String(key),
@@ -63,26 +96,108 @@ func (s *taggable) context(ctx context.Context, key string, headers *Headers) co
 type metrics struct {
 	store Store
 
-	loadCount   prometheus.Counter
-	storeCount  prometheus.Counter
-	copyCount   prometheus.Counter
-	deleteCount prometheus.Counter
+	opsTotal     *prometheus.CounterVec
+	opsErrors    *prometheus.CounterVec
+	opsDuration  *prometheus.HistogramVec
+	opsInFlight  *prometheus.GaugeVec
+	bytesRead    prometheus.Counter
+	bytesWritten prometheus.Counter
+	objectSize   *prometheus.HistogramVec
 
+	// Deprecated: per-operation series kept for compatibility with existing dashboards,
+	// use the series labelled by operation instead
+	loadCount      prometheus.Counter
+	storeCount     prometheus.Counter
+	copyCount      prometheus.Counter
+	deleteCount    prometheus.Counter
 	loadErrCount   prometheus.Counter
 	storeErrCount  prometheus.Counter
 	copyErrCount   prometheus.Counter
 	deleteErrCount prometheus.Counter
-
-	loadDuration  prometheus.Histogram
-	storeDuration prometheus.Histogram
-	copyDuration  prometheus.Histogram
+	loadDuration   prometheus.Histogram
+	storeDuration  prometheus.Histogram
+	copyDuration   prometheus.Histogram
 }
 
+// WithMetrics is a decorator that instruments a store with Prometheus metrics.
+// - operations_total: number of operations (per operation)
+// - operation_errors_total: number of failed operations (per operation and error kind)
+// - operation_duration_seconds: duration of operations (per operation)
+// - operations_in_flight: number of operations currently in progress (per operation)
+// - bytes_read_total: number of bytes read from loaded objects
+// - bytes_written_total: number of bytes written to stored objects
+// - object_size_bytes: size of loaded and stored objects (per operation)
+//
+// Tags passed to SetMetrics are attached to these metrics as const labels.
+//
+// For Load, the operation is in flight until the returned reader is closed.
+//
+// Deprecated series load_count, store_count, delete_count, copy_count, load_err_count, store_err_count,
+// delete_err_count, copy_err_count, load_duration_seconds, store_duration_seconds and copy_duration_seconds
+// are still exposed, they will be removed in a future release.
 func WithMetrics(store Store) Store {
 	return &metrics{store: store}
 }
 
-func (m *metrics) SetMetrics(system, subsystem string, _ ...*tag.Tag) {
+func (m *metrics) SetMetrics(system, subsystem string, tags ...*tag.Tag) {
+	m.setDeprecatedMetrics(system, subsystem)
+
+	constLabels := prometheus.Labels(tag.Labels(tags...))
+
+	m.opsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace:   system,
+		Subsystem:   subsystem,
+		Name:        "operations_total",
+		Help:        "The total number of operations on the store (per operation)",
+		ConstLabels: constLabels,
+	}, []string{"operation"})
+	m.opsErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace:   system,
+		Subsystem:   subsystem,
+		Name:        "operation_errors_total",
+		Help:        "The number of operations on the store that failed (per operation and error kind)",
+		ConstLabels: constLabels,
+	}, []string{"operation", "kind"})
+	m.opsDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace:   system,
+		Subsystem:   subsystem,
+		Name:        "operation_duration_seconds",
+		Help:        "The duration of operations on the store in seconds (per operation)",
+		ConstLabels: constLabels,
+	}, []string{"operation"})
+	m.opsInFlight = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace:   system,
+		Subsystem:   subsystem,
+		Name:        "operations_in_flight",
+		Help:        "The number of operations on the store currently in progress (per operation)",
+		ConstLabels: constLabels,
+	}, []string{"operation"})
+	m.bytesRead = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace:   system,
+		Subsystem:   subsystem,
+		Name:        "bytes_read_total",
+		Help:        "The total number of bytes read from objects loaded from the store",
+		ConstLabels: constLabels,
+	})
+	m.bytesWritten = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace:   system,
+		Subsystem:   subsystem,
+		Name:        "bytes_written_total",
+		Help:        "The total number of bytes written to objects stored in the store",
+		ConstLabels: constLabels,
+	})
+	m.objectSize = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace:   system,
+		Subsystem:   subsystem,
+		Name:        "object_size_bytes",
+		Help:        "The size of objects loaded from or stored in the store in bytes (per operation)",
+		Buckets:     prometheus.ExponentialBuckets(1024, 4, 10), // 1KiB to 256MiB
+		ConstLabels: constLabels,
+	}, []string{"operation"})
+}
+
+// setDeprecatedMetrics sets the per-operation series exposed before the series labelled by operation
+func (m *metrics) setDeprecatedMetrics(system, subsystem string) {
 	m.loadCount = prometheus.NewCounter(prometheus.CounterOpts{
 		Namespace: system,
 		Subsystem: subsystem,
@@ -113,7 +228,6 @@ func (m *metrics) SetMetrics(system, subsystem string, _ ...*tag.Tag) {
 		Name:      "load_err_count",
 		Help:      "The number of objects that failed to load from the store",
 	})
-
 	m.storeErrCount = prometheus.NewCounter(prometheus.CounterOpts{
 		Namespace: system,
 		Subsystem: subsystem,
@@ -152,55 +266,155 @@ func (m *metrics) SetMetrics(system, subsystem string, _ ...*tag.Tag) {
 	})
 }
 
-func (m *metrics) Store(ctx context.Context, key string, reader io.Reader, headers *Headers) error {
+// track starts tracking an operation and returns a function to call with the operation result
+func (m *metrics) track(operation Operation) func(err error) {
+	op := string(operation)
 	start := time.Now()
-	err := m.store.Store(ctx, key, reader, headers)
-	duration := time.Since(start)
-	m.storeDuration.Observe(duration.Seconds())
+	m.opsInFlight.WithLabelValues(op).Inc()
+	return func(err error) {
+		m.opsInFlight.WithLabelValues(op).Dec()
+		m.observe(op, start, err)
+	}
+}
+
+func (m *metrics) observe(op string, start time.Time, err error) {
+	m.opsDuration.WithLabelValues(op).Observe(time.Since(start).Seconds())
+	m.opsTotal.WithLabelValues(op).Inc()
+	if err != nil {
+		m.opsErrors.WithLabelValues(op, errorKind(err)).Inc()
+	}
+}
+
+// observeDeprecated records the result of an operation on the deprecated series
+func observeDeprecated(count, errCount prometheus.Counter, duration prometheus.Histogram, start time.Time, err error) {
+	if duration != nil {
+		duration.Observe(time.Since(start).Seconds())
+	}
 	if err != nil {
-		m.storeErrCount.Inc()
+		errCount.Inc()
 	} else {
-		m.storeCount.Inc()
+		count.Inc()
+	}
+}
+
+func (m *metrics) Store(ctx context.Context, key string, reader io.Reader, headers *Headers) error {
+	return m.observeStore(reader, func(reader io.Reader) error {
+		return m.store.Store(ctx, key, reader, headers)
+	})
+}
+
+// StoreIfMatch is recorded as a store
+func (m *metrics) StoreIfMatch(ctx context.Context, key string, reader io.Reader, headers *Headers, etag string) error {
+	return m.observeStore(reader, func(reader io.Reader) error {
+		return StoreIfMatch(ctx, m.store, key, reader, headers, etag)
+	})
+}
+
+func (m *metrics) observeStore(reader io.Reader, storeFn func(io.Reader) error) error {
+	start := time.Now()
+	done := m.track(OperationStore)
+	cr := &countingReader{reader: reader}
+	err := storeFn(cr)
+	done(err)
+	observeDeprecated(m.storeCount, m.storeErrCount, m.storeDuration, start, err)
+	m.bytesWritten.Add(float64(cr.n))
+	if err == nil {
+		m.objectSize.WithLabelValues(string(OperationStore)).Observe(float64(cr.n))
 	}
 	return err
 }
 
 func (m *metrics) Load(ctx context.Context, key string) (io.ReadCloser, *Headers, error) {
+	op := string(OperationLoad)
 	start := time.Now()
+	m.opsInFlight.WithLabelValues(op).Inc()
 	reader, headers, err := m.store.Load(ctx, key)
-	duration := time.Since(start)
-	m.loadDuration.Observe(duration.Seconds())
-	if err != nil {
-		m.loadErrCount.Inc()
-	} else {
-		m.loadCount.Inc()
+	m.observe(op, start, err)
+	observeDeprecated(m.loadCount, m.loadErrCount, m.loadDuration, start, err)
+	return m.loadReader(reader, err), headers, err
+}
+
+// LoadWithETag is recorded as a load
+func (m *metrics) LoadWithETag(ctx context.Context, key string) (io.ReadCloser, string, error) {
+	op := string(OperationLoad)
+	start := time.Now()
+	m.opsInFlight.WithLabelValues(op).Inc()
+	reader, etag, err := LoadWithETag(ctx, m.store, key)
+	m.observe(op, start, err)
+	observeDeprecated(m.loadCount, m.loadErrCount, m.loadDuration, start, err)
+	return m.loadReader(reader, err), etag, err
+}
+
+// loadReader wraps the reader of a load, so the load is in flight until the reader is closed
+func (m *metrics) loadReader(reader io.ReadCloser, err error) io.ReadCloser {
+	op := string(OperationLoad)
+	if err != nil || reader == nil {
+		m.opsInFlight.WithLabelValues(op).Dec()
+		return reader
+	}
+
+	onClose := func(n int64, eof bool) {
+		m.opsInFlight.WithLabelValues(op).Dec()
+		m.observeLoad(n, eof)
+	}
+	return &countingReadCloser{countingReader: countingReader{reader: reader}, closer: reader, onClose: onClose}
+}
+
+func (m *metrics) observeLoad(n int64, eof bool) {
+	m.bytesRead.Add(float64(n))
+	// The object size is only known if the reader has been fully consumed
+	if eof {
+		m.objectSize.WithLabelValues(string(OperationLoad)).Observe(float64(n))
 	}
-	return reader, headers, err
 }
 
 func (m *metrics) Delete(ctx context.Context, key string) error {
+	done := m.track(OperationDelete)
 	err := m.store.Delete(ctx, key)
-	if err != nil {
-		m.deleteErrCount.Inc()
-	} else {
-		m.deleteCount.Inc()
-	}
+	done(err)
+	observeDeprecated(m.deleteCount, m.deleteErrCount, nil, time.Time{}, err)
+	return err
+}
+
+// DeleteIfMatch is recorded as a delete
+func (m *metrics) DeleteIfMatch(ctx context.Context, key, etag string) error {
+	done := m.track(OperationDelete)
+	err := DeleteIfMatch(ctx, m.store, key, etag)
+	done(err)
+	observeDeprecated(m.deleteCount, m.deleteErrCount, nil, time.Time{}, err)
 	return err
 }
 
 func (m *metrics) Copy(ctx context.Context, srcKey, dstKey string) error {
 	start := time.Now()
+	done := m.track(OperationCopy)
 	err := m.store.Copy(ctx, srcKey, dstKey)
-	duration := time.Since(start)
-	m.copyDuration.Observe(duration.Seconds())
-	if err != nil {
-		m.copyErrCount.Inc()
-	} else {
-		m.copyCount.Inc()
-	}
+	done(err)
+	observeDeprecated(m.copyCount, m.copyErrCount, m.copyDuration, start, err)
 	return err
 }
 
+func (m *metrics) List(ctx context.Context, prefix string) ([]string, error) {
+	done := m.track(OperationList)
+	keys, err := List(ctx, m.store, prefix)
+	done(err)
+	return keys, err
+}
+
+func (m *metrics) DeleteBatch(ctx context.Context, keys []string, opts ...BatchOption) []*BatchResult {
+	done := m.track(OperationDeleteBatch)
+	results := DeleteBatch(ctx, m.store, keys, opts...)
+	done(BatchError(results))
+	return results
+}
+
+func (m *metrics) CopyBatch(ctx context.Context, pairs []*CopyPair, opts ...BatchOption) []*BatchResult {
+	done := m.track(OperationCopyBatch)
+	results := CopyBatch(ctx, m.store, pairs, opts...)
+	done(BatchError(results))
+	return results
+}
+
 func (m *metrics) Describe(ch chan<- *prometheus.Desc) {
 	m.loadCount.Describe(ch)
 	m.storeCount.Describe(ch)
@@ -213,6 +427,13 @@ func (m *metrics) Describe(ch chan<- *prometheus.Desc) {
 	m.loadDuration.Describe(ch)
 	m.storeDuration.Describe(ch)
 	m.copyDuration.Describe(ch)
+	m.opsTotal.Describe(ch)
+	m.opsErrors.Describe(ch)
+	m.opsDuration.Describe(ch)
+	m.opsInFlight.Describe(ch)
+	m.bytesRead.Describe(ch)
+	m.bytesWritten.Describe(ch)
+	m.objectSize.Describe(ch)
 }
 
 func (m *metrics) Collect(ch chan<- prometheus.Metric) {
@@ -227,6 +448,58 @@ func (m *metrics) Collect(ch chan<- prometheus.Metric) {
 	m.loadDuration.Collect(ch)
 	m.storeDuration.Collect(ch)
 	m.copyDuration.Collect(ch)
+	m.opsTotal.Collect(ch)
+	m.opsErrors.Collect(ch)
+	m.opsDuration.Collect(ch)
+	m.opsInFlight.Collect(ch)
+	m.bytesRead.Collect(ch)
+	m.bytesWritten.Collect(ch)
+	m.objectSize.Collect(ch)
+}
+
+// errorKind classifies an error for metrics labeling
+func errorKind(err error) string {
+	switch {
+	case errors.Is(err, ErrNotFound):
+		return "not_found"
+	case errors.Is(err, ErrPreconditionFailed):
+		return "precondition_failed"
+	case errors.Is(err, context.Canceled):
+		return "canceled"
+	case errors.Is(err, context.DeadlineExceeded):
+		return "timeout"
+	default:
+		return "other"
+	}
+}
+
+// countingReader counts the bytes read from the underlying reader
+type countingReader struct {
+	reader io.Reader
+	n      int64
+	eof    bool
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	r.n += int64(n)
+	if err == io.EOF {
+		r.eof = true
+	}
+	return n, err
+}
+
+// countingReadCloser counts the bytes read from the underlying reader and reports them on Close
+type countingReadCloser struct {
+	countingReader
+	closer  io.Closer
+	onClose func(n int64, eof bool)
+	once    sync.Once
+}
+
+func (r *countingReadCloser) Close() error {
+	r.once.Do(func() { r.onClose(r.n, r.eof) })
+	return r.closer.Close()
 }
 
 type loggable struct {
@@ -267,6 +540,36 @@ func (l *loggable) Delete(ctx context.Context, key string) error {
 	return err
 }
 
+func (l *loggable) LoadWithETag(ctx context.Context, key string) (io.ReadCloser, string, error) {
+	logger := log.LoggerFromContext(ctx)
+	logger.Debug("Load store object with ETag")
+	reader, etag, err := LoadWithETag(ctx, l.store, key)
+	if err != nil {
+		logger.Error("Failed to load store object with ETag", zap.Error(err))
+	}
+	return reader, etag, err
+}
+
+func (l *loggable) StoreIfMatch(ctx context.Context, key string, reader io.Reader, headers *Headers, etag string) error {
+	logger := log.LoggerFromContext(ctx)
+	logger.Debug("Store store object if ETag matches", zap.String("etag", etag))
+	err := StoreIfMatch(ctx, l.store, key, reader, headers, etag)
+	if err != nil && !errors.Is(err, ErrPreconditionFailed) {
+		logger.Error("Failed to store store object if ETag matches", zap.Error(err))
+	}
+	return err
+}
+
+func (l *loggable) DeleteIfMatch(ctx context.Context, key, etag string) error {
+	logger := log.LoggerFromContext(ctx)
+	logger.Debug("Delete store object if ETag matches", zap.String("etag", etag))
+	err := DeleteIfMatch(ctx, l.store, key, etag)
+	if err != nil && !errors.Is(err, ErrPreconditionFailed) {
+		logger.Error("Failed to delete store object if ETag matches", zap.Error(err))
+	}
+	return err
+}
+
 func (l *loggable) Copy(ctx context.Context, srcKey, dstKey string) error {
 	logger := log.LoggerFromContext(ctx)
 	logger.Debug("Copy store object")
@@ -276,3 +579,130 @@ func (l *loggable) Copy(ctx context.Context, srcKey, dstKey string) error {
 	}
 	return err
 }
+
+func (l *loggable) List(ctx context.Context, prefix string) ([]string, error) {
+	logger := log.LoggerFromContext(ctx)
+	logger.Debug("List store objects")
+	keys, err := List(ctx, l.store, prefix)
+	if err != nil {
+		logger.Error("Failed to list store objects", zap.Error(err))
+	}
+	return keys, err
+}
+
+func (l *loggable) DeleteBatch(ctx context.Context, keys []string, opts ...BatchOption) []*BatchResult {
+	logger := log.LoggerFromContext(ctx)
+	logger.Debug("Delete batch of store objects", zap.Int("count", len(keys)))
+	results := DeleteBatch(ctx, l.store, keys, opts...)
+	if err := BatchError(results); err != nil {
+		logger.Error("Failed to delete some store objects", zap.Error(err))
+	}
+	return results
+}
+
+func (l *loggable) CopyBatch(ctx context.Context, pairs []*CopyPair, opts ...BatchOption) []*BatchResult {
+	logger := log.LoggerFromContext(ctx)
+	logger.Debug("Copy batch of store objects", zap.Int("count", len(pairs)))
+	results := CopyBatch(ctx, l.store, pairs, opts...)
+	if err := BatchError(results); err != nil {
+		logger.Error("Failed to copy some store objects", zap.Error(err))
+	}
+	return results
+}
+
+type traced struct {
+	store Store
+}
+
+// WithTracing is a decorator that creates a client span "store.<operation>" for every operation on a store
+// - store.operation: operation (e.g. "load")
+// - store.key: key of the object (for Store, Load and Delete)
+// - store.src_key and store.dst_key: keys of the objects (for Copy)
+// - store.prefix: prefix of the keys (for List)
+// - store.batch_size: number of objects (for DeleteBatch and CopyBatch)
+// - store.if_match: expected ETag of the object (for StoreIfMatch and DeleteIfMatch)
+//
+// Tags attached to the operation context are also recorded as span attributes (see tracing.StartSpan)
+func WithTracing(store Store) Store {
+	return &traced{store: store}
+}
+
+func (t *traced) start(ctx context.Context, operation Operation, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return tracing.StartSpan(
+		ctx,
+		"store."+string(operation),
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attribute.String("store.operation", string(operation))),
+		trace.WithAttributes(attrs...),
+	)
+}
+
+func (t *traced) Store(ctx context.Context, key string, reader io.Reader, headers *Headers) error {
+	ctx, span := t.start(ctx, OperationStore, attribute.String("store.key", key))
+	err := t.store.Store(ctx, key, reader, headers)
+	tracing.End(span, err)
+	return err
+}
+
+func (t *traced) Load(ctx context.Context, key string) (io.ReadCloser, *Headers, error) {
+	ctx, span := t.start(ctx, OperationLoad, attribute.String("store.key", key))
+	reader, headers, err := t.store.Load(ctx, key)
+	tracing.End(span, err)
+	return reader, headers, err
+}
+
+func (t *traced) Delete(ctx context.Context, key string) error {
+	ctx, span := t.start(ctx, OperationDelete, attribute.String("store.key", key))
+	err := t.store.Delete(ctx, key)
+	tracing.End(span, err)
+	return err
+}
+
+func (t *traced) LoadWithETag(ctx context.Context, key string) (io.ReadCloser, string, error) {
+	ctx, span := t.start(ctx, OperationLoad, attribute.String("store.key", key))
+	reader, etag, err := LoadWithETag(ctx, t.store, key)
+	tracing.End(span, err)
+	return reader, etag, err
+}
+
+func (t *traced) StoreIfMatch(ctx context.Context, key string, reader io.Reader, headers *Headers, etag string) error {
+	ctx, span := t.start(ctx, OperationStore, attribute.String("store.key", key), attribute.String("store.if_match", etag))
+	err := StoreIfMatch(ctx, t.store, key, reader, headers, etag)
+	tracing.End(span, err)
+	return err
+}
+
+func (t *traced) DeleteIfMatch(ctx context.Context, key, etag string) error {
+	ctx, span := t.start(ctx, OperationDelete, attribute.String("store.key", key), attribute.String("store.if_match", etag))
+	err := DeleteIfMatch(ctx, t.store, key, etag)
+	tracing.End(span, err)
+	return err
+}
+
+func (t *traced) Copy(ctx context.Context, srcKey, dstKey string) error {
+	ctx, span := t.start(ctx, OperationCopy, attribute.String("store.src_key", srcKey), attribute.String("store.dst_key", dstKey))
+	err := t.store.Copy(ctx, srcKey, dstKey)
+	tracing.End(span, err)
+	return err
+}
+
+func (t *traced) List(ctx context.Context, prefix string) ([]string, error) {
+	ctx, span := t.start(ctx, OperationList, attribute.String("store.prefix", prefix))
+	keys, err := List(ctx, t.store, prefix)
+	tracing.End(span, err)
+	return keys, err
+}
+
+func (t *traced) DeleteBatch(ctx context.Context, keys []string, opts ...BatchOption) []*BatchResult {
+	ctx, span := t.start(ctx, OperationDeleteBatch, attribute.Int("store.batch_size", len(keys)))
+	results := DeleteBatch(ctx, t.store, keys, opts...)
+	tracing.End(span, BatchError(results))
+	return results
+}
+
+func (t *traced) CopyBatch(ctx context.Context, pairs []*CopyPair, opts ...BatchOption) []*BatchResult {
+	ctx, span := t.start(ctx, OperationCopyBatch, attribute.Int("store.batch_size", len(pairs)))
+	results := CopyBatch(ctx, t.store, pairs, opts...)
+	tracing.End(span, BatchError(results))
+	return results
+}
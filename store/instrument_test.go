@@ -9,11 +9,18 @@ import (
 	"github.com/nmvalera/go-utils/app/svc"
 	kkrtgomock "github.com/nmvalera/go-utils/gomock"
 	"github.com/nmvalera/go-utils/store"
+	"github.com/nmvalera/go-utils/store/memory"
 	"github.com/nmvalera/go-utils/store/mock"
 	"github.com/nmvalera/go-utils/tag"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/mock/gomock"
 )
 
@@ -21,6 +28,7 @@ func TestImplementsInterface(t *testing.T) {
 	assert.Implements(t, (*store.Store)(nil), store.WithTags(nil))
 	assert.Implements(t, (*store.Store)(nil), store.WithMetrics(nil))
 	assert.Implements(t, (*store.Store)(nil), store.WithLog(nil))
+	assert.Implements(t, (*store.Store)(nil), store.WithTracing(nil))
 }
 
 func TestWithTags(t *testing.T) {
@@ -116,20 +124,33 @@ func TestWithMetrics(t *testing.T) {
 	require.Implements(t, (*svc.Metricable)(nil), metricsStore)
 	require.Implements(t, (*svc.MetricsCollector)(nil), metricsStore)
 
-	metricsStore.(svc.Metricable).SetMetrics("test-system", "test-subsystem")
+	metricsStore.(svc.Metricable).SetMetrics("test-system", "test-subsystem", tag.Key("component").String("test-component"))
+
+	reg := prometheus.NewRegistry()
+	require.NoError(t, reg.Register(metricsStore.(prometheus.Collector)))
 
 	ctx := context.TODO()
-	reader := io.NopCloser(strings.NewReader("test-value"))
 	headers := new(store.Headers)
-	mockStore.EXPECT().Store(ctx, "test-key", reader, headers).Return(nil)
-	err := metricsStore.Store(ctx, "test-key", reader, headers)
+	mockStore.EXPECT().Store(ctx, "test-key", gomock.Any(), headers).DoAndReturn(func(_ context.Context, _ string, reader io.Reader, _ *store.Headers) error {
+		_, err := io.ReadAll(reader)
+		return err
+	})
+	err := metricsStore.Store(ctx, "test-key", strings.NewReader("test-value"), headers)
 	require.NoError(t, err)
 
-	mockStore.EXPECT().Load(ctx, "test-key").Return(reader, headers, nil)
+	mockStore.EXPECT().Load(ctx, "test-key").Return(io.NopCloser(strings.NewReader("test-value-load")), headers, nil)
 	resReader, resHeaders, err := metricsStore.Load(ctx, "test-key")
 	require.NoError(t, err)
-	require.Equal(t, reader, resReader)
 	require.Equal(t, headers, resHeaders)
+	b, err := io.ReadAll(resReader)
+	require.NoError(t, err)
+	assert.Equal(t, "test-value-load", string(b))
+	assert.Equal(t, float64(1), gatherValues(t, reg)["test-system_test-subsystem_operations_in_flight/load"], "Load should be in flight until the reader is closed")
+	require.NoError(t, resReader.Close())
+
+	mockStore.EXPECT().Load(ctx, "test-key-missing").Return(nil, nil, store.ErrNotFound)
+	_, _, err = metricsStore.Load(ctx, "test-key-missing")
+	require.ErrorIs(t, err, store.ErrNotFound)
 
 	mockStore.EXPECT().Delete(ctx, "test-key").Return(nil)
 	err = metricsStore.Delete(ctx, "test-key")
@@ -150,7 +171,7 @@ func TestWithMetrics(t *testing.T) {
 		descs = append(descs, desc)
 	}
 
-	require.Len(t, descs, 11)
+	require.Len(t, descs, 18)
 
 	assert.Equal(t, "Desc{fqName: \"test-system_test-subsystem_load_count\", help: \"The number of objects successfully loaded from the store\", constLabels: {}, variableLabels: {}}", descs[0].String())
 	assert.Equal(t, "Desc{fqName: \"test-system_test-subsystem_store_count\", help: \"The number of objects successfully stored in the store\", constLabels: {}, variableLabels: {}}", descs[1].String())
@@ -163,19 +184,59 @@ func TestWithMetrics(t *testing.T) {
 	assert.Equal(t, "Desc{fqName: \"test-system_test-subsystem_load_duration_seconds\", help: \"The duration of the load method (in seconds)\", constLabels: {}, variableLabels: {}}", descs[8].String())
 	assert.Equal(t, "Desc{fqName: \"test-system_test-subsystem_store_duration_seconds\", help: \"The duration of the store method (in seconds)\", constLabels: {}, variableLabels: {}}", descs[9].String())
 	assert.Equal(t, "Desc{fqName: \"test-system_test-subsystem_copy_duration_seconds\", help: \"The duration of the copy method (in seconds)\", constLabels: {}, variableLabels: {}}", descs[10].String())
+	assert.Equal(t, "Desc{fqName: \"test-system_test-subsystem_operations_total\", help: \"The total number of operations on the store (per operation)\", constLabels: {component=\"test-component\"}, variableLabels: {operation}}", descs[11].String())
+	assert.Equal(t, "Desc{fqName: \"test-system_test-subsystem_operation_errors_total\", help: \"The number of operations on the store that failed (per operation and error kind)\", constLabels: {component=\"test-component\"}, variableLabels: {operation,kind}}", descs[12].String())
+	assert.Equal(t, "Desc{fqName: \"test-system_test-subsystem_operation_duration_seconds\", help: \"The duration of operations on the store in seconds (per operation)\", constLabels: {component=\"test-component\"}, variableLabels: {operation}}", descs[13].String())
+	assert.Equal(t, "Desc{fqName: \"test-system_test-subsystem_operations_in_flight\", help: \"The number of operations on the store currently in progress (per operation)\", constLabels: {component=\"test-component\"}, variableLabels: {operation}}", descs[14].String())
+	assert.Equal(t, "Desc{fqName: \"test-system_test-subsystem_bytes_read_total\", help: \"The total number of bytes read from objects loaded from the store\", constLabels: {component=\"test-component\"}, variableLabels: {}}", descs[15].String())
+	assert.Equal(t, "Desc{fqName: \"test-system_test-subsystem_bytes_written_total\", help: \"The total number of bytes written to objects stored in the store\", constLabels: {component=\"test-component\"}, variableLabels: {}}", descs[16].String())
+	assert.Equal(t, "Desc{fqName: \"test-system_test-subsystem_object_size_bytes\", help: \"The size of objects loaded from or stored in the store in bytes (per operation)\", constLabels: {component=\"test-component\"}, variableLabels: {operation}}", descs[17].String())
+
+	values := gatherValues(t, reg)
+	assert.Equal(t, float64(1), values["test-system_test-subsystem_operations_total/store"])
+	assert.Equal(t, float64(2), values["test-system_test-subsystem_operations_total/load"])
+	assert.Equal(t, float64(1), values["test-system_test-subsystem_operations_total/delete"])
+	assert.Equal(t, float64(1), values["test-system_test-subsystem_operations_total/copy"])
+	assert.Equal(t, float64(1), values["test-system_test-subsystem_operation_errors_total/not_found/load"])
+	assert.Equal(t, float64(0), values["test-system_test-subsystem_operations_in_flight/load"])
+	assert.Equal(t, float64(len("test-value-load")), values["test-system_test-subsystem_bytes_read_total"])
+	assert.Equal(t, float64(len("test-value")), values["test-system_test-subsystem_bytes_written_total"])
+	assert.Equal(t, float64(1), values["test-system_test-subsystem_object_size_bytes/store"])
+	assert.Equal(t, float64(1), values["test-system_test-subsystem_object_size_bytes/load"])
+
+	assert.Equal(t, float64(1), values["test-system_test-subsystem_store_count"])
+	assert.Equal(t, float64(1), values["test-system_test-subsystem_load_count"])
+	assert.Equal(t, float64(1), values["test-system_test-subsystem_load_err_count"])
+	assert.Equal(t, float64(1), values["test-system_test-subsystem_delete_count"])
+	assert.Equal(t, float64(1), values["test-system_test-subsystem_copy_count"])
+	assert.Equal(t, float64(2), values["test-system_test-subsystem_load_duration_seconds"])
+}
 
-	chMetrics := make(chan prometheus.Metric)
-	go func() {
-		metricsStore.(svc.MetricsCollector).Collect(chMetrics)
-		close(chMetrics)
-	}()
+// gatherValues returns the value of every gathered metric, keyed by name and variable label values
+func gatherValues(t *testing.T, reg *prometheus.Registry) map[string]float64 {
+	families, err := reg.Gather()
+	require.NoError(t, err)
 
-	metrics := make([]prometheus.Metric, 0)
-	for metric := range chMetrics {
-		metrics = append(metrics, metric)
+	values := make(map[string]float64)
+	for _, family := range families {
+		for _, metric := range family.GetMetric() {
+			name := family.GetName()
+			for _, label := range metric.GetLabel() {
+				if label.GetName() != "component" {
+					name += "/" + label.GetValue()
+				}
+			}
+			switch {
+			case metric.GetCounter() != nil:
+				values[name] = metric.GetCounter().GetValue()
+			case metric.GetGauge() != nil:
+				values[name] = metric.GetGauge().GetValue()
+			case metric.GetHistogram() != nil:
+				values[name] = float64(metric.GetHistogram().GetSampleCount())
+			}
+		}
 	}
-
-	require.Len(t, metrics, 11)
+	return values
 }
 
 func TestWithLog(t *testing.T) {
@@ -209,3 +270,39 @@ func TestWithLog(t *testing.T) {
 	err = logStore.Copy(ctx, "test-src-key", "test-dst-key")
 	require.NoError(t, err)
 }
+
+func TestWithTracing(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+
+	tracedStore := store.WithTracing(memory.New())
+	require.Implements(t, (*store.Store)(nil), tracedStore)
+
+	ctx := tag.WithTags(context.Background(), tag.Key("component").String("test-component"))
+	err := tracedStore.Store(ctx, "test-key", strings.NewReader("test-value"), new(store.Headers))
+	require.NoError(t, err)
+
+	_, _, err = tracedStore.Load(ctx, "unknown-key")
+	require.ErrorIs(t, err, store.ErrNotFound)
+
+	results := store.DeleteBatch(ctx, tracedStore, []string{"test-key"})
+	require.NoError(t, store.BatchError(results))
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 3)
+
+	assert.Equal(t, "store.store", spans[0].Name())
+	assert.Equal(t, trace.SpanKindClient, spans[0].SpanKind())
+	assert.Subset(t, spans[0].Attributes(), []attribute.KeyValue{
+		attribute.String("store.operation", "store"),
+		attribute.String("store.key", "test-key"),
+		attribute.String("component", "test-component"),
+	})
+	assert.Equal(t, codes.Unset, spans[0].Status().Code)
+
+	assert.Equal(t, "store.load", spans[1].Name())
+	assert.Equal(t, codes.Error, spans[1].Status().Code)
+
+	assert.Equal(t, "store.delete_batch", spans[2].Name())
+	assert.Contains(t, spans[2].Attributes(), attribute.Int("store.batch_size", 1))
+}
@@ -4,17 +4,27 @@ import (
 	"bytes"
 	"context"
 	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
 
 	store "github.com/nmvalera/go-utils/store"
 )
 
 type Store struct {
+	mu   sync.RWMutex
 	data map[string][]byte
+
+	// etags are versions of the objects, so an object written twice with the same data gets a new ETag
+	etags   map[string]string
+	version int
 }
 
 func New() store.Store {
 	return &Store{
-		data: make(map[string][]byte),
+		data:  make(map[string][]byte),
+		etags: make(map[string]string),
 	}
 }
 
@@ -25,12 +35,16 @@ func (s *Store) Store(_ context.Context, key string, reader io.Reader, _ *store.
 		return err
 	}
 
-	s.data[key] = data
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.set(key, data)
 	return nil
 }
 
 // Load loads the data from the memory store
 func (s *Store) Load(_ context.Context, key string) (io.ReadCloser, *store.Headers, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
 	data, ok := s.data[key]
 	if !ok {
 		return nil, nil, store.ErrNotFound
@@ -40,16 +54,84 @@ func (s *Store) Load(_ context.Context, key string) (io.ReadCloser, *store.Heade
 }
 
 func (s *Store) Copy(_ context.Context, srcKey, dstKey string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 	data, ok := s.data[srcKey]
 	if !ok {
 		return store.ErrNotFound
 	}
 
-	s.data[dstKey] = data
+	s.set(dstKey, data)
 	return nil
 }
 
 func (s *Store) Delete(_ context.Context, key string) error {
-	delete(s.data, key)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.unset(key)
 	return nil
 }
+
+// LoadWithETag loads the data from the memory store with its ETag
+func (s *Store) LoadWithETag(_ context.Context, key string) (io.ReadCloser, string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	data, ok := s.data[key]
+	if !ok {
+		return nil, "", store.ErrNotFound
+	}
+
+	return io.NopCloser(bytes.NewReader(data)), s.etags[key], nil
+}
+
+// StoreIfMatch stores the data in the memory store if its ETag is etag (or if it does not exist when etag is empty)
+func (s *Store) StoreIfMatch(_ context.Context, key string, reader io.Reader, _ *store.Headers, etag string) error {
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.etags[key] != etag {
+		return store.ErrPreconditionFailed
+	}
+	s.set(key, data)
+	return nil
+}
+
+// DeleteIfMatch deletes the data from the memory store if its ETag is etag
+func (s *Store) DeleteIfMatch(_ context.Context, key, etag string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.data[key]; !ok || s.etags[key] != etag {
+		return store.ErrPreconditionFailed
+	}
+	s.unset(key)
+	return nil
+}
+
+func (s *Store) set(key string, data []byte) {
+	s.version++
+	s.data[key] = data
+	s.etags[key] = strconv.Itoa(s.version)
+}
+
+func (s *Store) unset(key string) {
+	delete(s.data, key)
+	delete(s.etags, key)
+}
+
+// List returns the keys of all objects which key starts with the given prefix by scanning the memory store
+func (s *Store) List(_ context.Context, prefix string) ([]string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	keys := make([]string, 0)
+	for key := range s.data {
+		if strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
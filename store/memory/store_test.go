@@ -13,6 +13,7 @@ import (
 
 func TestImplementsStore(t *testing.T) {
 	assert.Implements(t, (*store.Store)(nil), new(Store))
+	assert.Implements(t, (*store.ConditionalStore)(nil), new(Store))
 }
 
 func TestStoreAndLoad(t *testing.T) {
@@ -28,3 +29,52 @@ func TestStoreAndLoad(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, []byte("test"), data)
 }
+
+func TestList(t *testing.T) {
+	s := New()
+	ctx := context.Background()
+
+	for _, key := range []string{"a/2", "a/1", "b/1"} {
+		require.NoError(t, s.Store(ctx, key, bytes.NewReader([]byte(key)), nil))
+	}
+
+	keys, err := store.List(ctx, s, "a/")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a/1", "a/2"}, keys)
+
+	results, err := store.DeletePrefix(ctx, s, "a/")
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	require.NoError(t, store.BatchError(results))
+
+	keys, err = store.List(ctx, s, "")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"b/1"}, keys)
+}
+
+func TestConditionalWrites(t *testing.T) {
+	ctx := context.Background()
+	s := New().(*Store)
+
+	require.NoError(t, s.StoreIfMatch(ctx, "test", bytes.NewReader([]byte("a")), nil, ""))
+	err := s.StoreIfMatch(ctx, "test", bytes.NewReader([]byte("b")), nil, "")
+	assert.ErrorIs(t, err, store.ErrPreconditionFailed, "Storing an existing object without ETag should fail")
+
+	reader, etag, err := s.LoadWithETag(ctx, "test")
+	require.NoError(t, err)
+	reader.Close()
+
+	// Storing the same data changes the ETag
+	require.NoError(t, s.Store(ctx, "test", bytes.NewReader([]byte("a")), nil))
+	err = s.StoreIfMatch(ctx, "test", bytes.NewReader([]byte("b")), nil, etag)
+	assert.ErrorIs(t, err, store.ErrPreconditionFailed, "Storing with a stale ETag should fail")
+	assert.ErrorIs(t, s.DeleteIfMatch(ctx, "test", etag), store.ErrPreconditionFailed, "Deleting with a stale ETag should fail")
+
+	reader, etag, err = s.LoadWithETag(ctx, "test")
+	require.NoError(t, err)
+	reader.Close()
+	require.NoError(t, s.DeleteIfMatch(ctx, "test", etag))
+
+	_, _, err = s.LoadWithETag(ctx, "test")
+	assert.ErrorIs(t, err, store.ErrNotFound)
+}
@@ -0,0 +1,313 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	store "github.com/nmvalera/go-utils/store"
+	"github.com/nmvalera/go-utils/tag"
+	"github.com/prometheus/client_golang/prometheus"
+	"golang.org/x/time/rate"
+)
+
+const (
+	limitOps   = "ops"
+	limitBytes = "bytes"
+)
+
+// Store is a store decorator that enforces operations/sec and bytes/sec limits per operation.
+//
+// Limits are enforced using token buckets.
+// When a limit is reached, operations wait until tokens are available or the context is done.
+//
+// Batch operations consume one token of the ops limit of the corresponding single key operation per key,
+// and are then performed by the underlying store (natively if it supports it).
+//
+// Bytes limits apply to the data streamed by Store and Load. For Load, the bytes limit is applied
+// while the caller reads the returned reader, using the context passed to Load.
+type Store struct {
+	store store.Store
+
+	opsLimiters   map[store.Operation]*rate.Limiter
+	bytesLimiters map[store.Operation]*rate.Limiter
+
+	throttledDuration *prometheus.CounterVec
+	throttledCount    *prometheus.CounterVec
+}
+
+type Options func(*Store) error
+
+func New(s store.Store, opts ...Options) (*Store, error) {
+	rs := &Store{
+		store:         s,
+		opsLimiters:   make(map[store.Operation]*rate.Limiter),
+		bytesLimiters: make(map[store.Operation]*rate.Limiter),
+	}
+
+	for _, opt := range opts {
+		if err := opt(rs); err != nil {
+			return nil, err
+		}
+	}
+
+	// Set default metrics, so the store can be used without calling SetMetrics
+	rs.SetMetrics("", "")
+
+	return rs, nil
+}
+
+func (s *Store) SetMetrics(system, subsystem string, tags ...*tag.Tag) {
+	constLabels := prometheus.Labels(tag.Labels(tags...))
+
+	s.throttledDuration = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace:   system,
+		Subsystem:   subsystem,
+		Name:        "throttled_seconds_total",
+		Help:        "The total time spent waiting for rate limits in seconds (per operation and limit)",
+		ConstLabels: constLabels,
+	}, []string{"operation", "limit"})
+	s.throttledCount = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace:   system,
+		Subsystem:   subsystem,
+		Name:        "throttled_total",
+		Help:        "The number of times an operation had to wait for a rate limit (per operation and limit)",
+		ConstLabels: constLabels,
+	}, []string{"operation", "limit"})
+}
+
+// Store stores the data in the store, waiting for the ops limit and throttling the reader to the bytes limit
+func (s *Store) Store(ctx context.Context, key string, reader io.Reader, headers *store.Headers) error {
+	if err := s.waitOps(ctx, store.OperationStore); err != nil {
+		return err
+	}
+
+	return s.store.Store(ctx, key, s.throttleStore(ctx, reader), headers)
+}
+
+// StoreIfMatch stores the data in the store if its ETag is etag (see store.ConditionalStore),
+// waiting for the store ops limit and throttling the reader to the store bytes limit
+func (s *Store) StoreIfMatch(ctx context.Context, key string, reader io.Reader, headers *store.Headers, etag string) error {
+	if err := s.waitOps(ctx, store.OperationStore); err != nil {
+		return err
+	}
+
+	return store.StoreIfMatch(ctx, s.store, key, s.throttleStore(ctx, reader), headers, etag)
+}
+
+func (s *Store) throttleStore(ctx context.Context, reader io.Reader) io.Reader {
+	if limiter, ok := s.bytesLimiters[store.OperationStore]; ok {
+		return &throttledReader{ctx: ctx, reader: reader, limiter: limiter, wait: s.waitBytesFunc(store.OperationStore)}
+	}
+	return reader
+}
+
+// Load loads the data from the store, waiting for the ops limit and throttling the returned reader to the bytes limit
+// It is the responsibility of the caller to close the returned reader
+func (s *Store) Load(ctx context.Context, key string) (io.ReadCloser, *store.Headers, error) {
+	if err := s.waitOps(ctx, store.OperationLoad); err != nil {
+		return nil, nil, err
+	}
+
+	reader, headers, err := s.store.Load(ctx, key)
+	if err != nil || reader == nil {
+		return reader, headers, err
+	}
+
+	return s.throttleLoad(ctx, reader), headers, nil
+}
+
+// LoadWithETag loads the data from the store with its ETag (see store.ConditionalStore),
+// waiting for the load ops limit and throttling the returned reader to the load bytes limit
+// It is the responsibility of the caller to close the returned reader
+func (s *Store) LoadWithETag(ctx context.Context, key string) (io.ReadCloser, string, error) {
+	if err := s.waitOps(ctx, store.OperationLoad); err != nil {
+		return nil, "", err
+	}
+
+	reader, etag, err := store.LoadWithETag(ctx, s.store, key)
+	if err != nil || reader == nil {
+		return reader, etag, err
+	}
+
+	return s.throttleLoad(ctx, reader), etag, nil
+}
+
+func (s *Store) throttleLoad(ctx context.Context, reader io.ReadCloser) io.ReadCloser {
+	if limiter, ok := s.bytesLimiters[store.OperationLoad]; ok {
+		return &throttledReadCloser{
+			throttledReader: throttledReader{ctx: ctx, reader: reader, limiter: limiter, wait: s.waitBytesFunc(store.OperationLoad)},
+			closer:          reader,
+		}
+	}
+	return reader
+}
+
+func (s *Store) Delete(ctx context.Context, key string) error {
+	if err := s.waitOps(ctx, store.OperationDelete); err != nil {
+		return err
+	}
+	return s.store.Delete(ctx, key)
+}
+
+// DeleteIfMatch deletes the data from the store if its ETag is etag (see store.ConditionalStore), waiting for the delete ops limit
+func (s *Store) DeleteIfMatch(ctx context.Context, key, etag string) error {
+	if err := s.waitOps(ctx, store.OperationDelete); err != nil {
+		return err
+	}
+	return store.DeleteIfMatch(ctx, s.store, key, etag)
+}
+
+func (s *Store) Copy(ctx context.Context, srcKey, dstKey string) error {
+	if err := s.waitOps(ctx, store.OperationCopy); err != nil {
+		return err
+	}
+	return s.store.Copy(ctx, srcKey, dstKey)
+}
+
+// List lists the keys of the underlying store, waiting for the list ops limit
+func (s *Store) List(ctx context.Context, prefix string) ([]string, error) {
+	if err := s.waitOps(ctx, store.OperationList); err != nil {
+		return nil, err
+	}
+	return store.List(ctx, s.store, prefix)
+}
+
+// DeleteBatch deletes the objects with the given keys, waiting for one delete ops token per key
+func (s *Store) DeleteBatch(ctx context.Context, keys []string, opts ...store.BatchOption) []*store.BatchResult {
+	if err := s.waitOpsN(ctx, store.OperationDelete, len(keys)); err != nil {
+		return batchErr(keys, err)
+	}
+	return store.DeleteBatch(ctx, s.store, keys, opts...)
+}
+
+// CopyBatch copies the objects for the given pairs, waiting for one copy ops token per pair
+func (s *Store) CopyBatch(ctx context.Context, pairs []*store.CopyPair, opts ...store.BatchOption) []*store.BatchResult {
+	if err := s.waitOpsN(ctx, store.OperationCopy, len(pairs)); err != nil {
+		keys := make([]string, len(pairs))
+		for i, pair := range pairs {
+			keys[i] = pair.SrcKey
+		}
+		return batchErr(keys, err)
+	}
+	return store.CopyBatch(ctx, s.store, pairs, opts...)
+}
+
+func batchErr(keys []string, err error) []*store.BatchResult {
+	results := make([]*store.BatchResult, len(keys))
+	for i, key := range keys {
+		results[i] = &store.BatchResult{Key: key, Err: err}
+	}
+	return results
+}
+
+func (s *Store) waitOps(ctx context.Context, op store.Operation) error {
+	return s.waitOpsN(ctx, op, 1)
+}
+
+// waitOpsN waits for n ops tokens, by chunks of at most the burst size so large batches can be served
+func (s *Store) waitOpsN(ctx context.Context, op store.Operation, n int) error {
+	limiter, ok := s.opsLimiters[op]
+	if !ok {
+		return nil
+	}
+	for n > 0 {
+		chunk := min(n, limiter.Burst())
+		if err := s.wait(ctx, op, limitOps, limiter, chunk); err != nil {
+			return err
+		}
+		n -= chunk
+	}
+	return nil
+}
+
+func (s *Store) waitBytesFunc(op store.Operation) func(ctx context.Context, limiter *rate.Limiter, n int) error {
+	return func(ctx context.Context, limiter *rate.Limiter, n int) error {
+		return s.wait(ctx, op, limitBytes, limiter, n)
+	}
+}
+
+func (s *Store) wait(ctx context.Context, op store.Operation, limit string, limiter *rate.Limiter, n int) error {
+	// Fast path: tokens are immediately available
+	if limiter.AllowN(time.Now(), n) {
+		return nil
+	}
+
+	start := time.Now()
+	err := limiter.WaitN(ctx, n)
+	s.throttledDuration.WithLabelValues(string(op), limit).Add(time.Since(start).Seconds())
+	s.throttledCount.WithLabelValues(string(op), limit).Inc()
+	return err
+}
+
+func (s *Store) Describe(ch chan<- *prometheus.Desc) {
+	s.throttledDuration.Describe(ch)
+	s.throttledCount.Describe(ch)
+}
+
+func (s *Store) Collect(ch chan<- prometheus.Metric) {
+	s.throttledDuration.Collect(ch)
+	s.throttledCount.Collect(ch)
+}
+
+// throttledReader is a reader that waits for the bytes limit before returning read bytes
+type throttledReader struct {
+	ctx     context.Context
+	reader  io.Reader
+	limiter *rate.Limiter
+	wait    func(ctx context.Context, limiter *rate.Limiter, n int) error
+}
+
+func (r *throttledReader) Read(p []byte) (int, error) {
+	// Never read more than the burst size, otherwise we could never get enough tokens
+	if burst := r.limiter.Burst(); len(p) > burst {
+		p = p[:burst]
+	}
+
+	n, err := r.reader.Read(p)
+	if n > 0 {
+		if waitErr := r.wait(r.ctx, r.limiter, n); waitErr != nil {
+			return n, waitErr
+		}
+	}
+	return n, err
+}
+
+type throttledReadCloser struct {
+	throttledReader
+	closer io.Closer
+}
+
+func (r *throttledReadCloser) Close() error {
+	return r.closer.Close()
+}
+
+// WithOpsLimit limits the number of operations per second for the given operation
+// burst is the maximum number of operations that can be performed at once
+func WithOpsLimit(op store.Operation, opsPerSecond float64, burst int) Options {
+	return func(s *Store) error {
+		if burst <= 0 {
+			return fmt.Errorf("invalid ops limit burst %d (must be positive)", burst)
+		}
+		s.opsLimiters[op] = rate.NewLimiter(rate.Limit(opsPerSecond), burst)
+		return nil
+	}
+}
+
+// WithBytesLimit limits the number of bytes per second streamed for the given operation
+// burst is the maximum number of bytes that can be streamed at once
+//
+// It only applies to operations streaming data (store and load)
+func WithBytesLimit(op store.Operation, bytesPerSecond float64, burst int) Options {
+	return func(s *Store) error {
+		if op != store.OperationStore && op != store.OperationLoad {
+			return fmt.Errorf("bytes limit not supported for operation %q", op)
+		}
+		if burst <= 0 {
+			return fmt.Errorf("invalid bytes limit burst %d (must be positive)", burst)
+		}
+		s.bytesLimiters[op] = rate.NewLimiter(rate.Limit(bytesPerSecond), burst)
+		return nil
+	}
+}
@@ -0,0 +1,122 @@
+package ratelimit
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	store "github.com/nmvalera/go-utils/store"
+	"github.com/nmvalera/go-utils/store/memory"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestImplementsStore(t *testing.T) {
+	assert.Implements(t, (*store.Store)(nil), new(Store))
+	assert.Implements(t, (*store.BatchDeleter)(nil), new(Store))
+	assert.Implements(t, (*store.BatchCopier)(nil), new(Store))
+}
+
+func TestOpsLimit(t *testing.T) {
+	s, err := New(memory.New(), WithOpsLimit(store.OperationDelete, 20, 1))
+	require.NoError(t, err)
+
+	ctx := context.TODO()
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		require.NoError(t, s.Delete(ctx, "test"))
+	}
+	assert.GreaterOrEqual(t, time.Since(start), 90*time.Millisecond)
+	assert.Equal(t, float64(2), testutil.ToFloat64(s.throttledCount.WithLabelValues("delete", "ops")))
+
+	// Operations without limit are not throttled
+	require.NoError(t, s.Store(ctx, "test", bytes.NewReader([]byte("test")), nil))
+	assert.Equal(t, 1, testutil.CollectAndCount(s.throttledCount))
+}
+
+func TestOpsLimitConditional(t *testing.T) {
+	s, err := New(memory.New(), WithOpsLimit(store.OperationStore, 20, 1))
+	require.NoError(t, err)
+
+	ctx := context.TODO()
+	start := time.Now()
+	require.NoError(t, s.StoreIfMatch(ctx, "test", bytes.NewReader([]byte("test")), nil, ""))
+	require.ErrorIs(t, s.StoreIfMatch(ctx, "test", bytes.NewReader([]byte("test")), nil, ""), store.ErrPreconditionFailed)
+	assert.GreaterOrEqual(t, time.Since(start), 40*time.Millisecond)
+	assert.Equal(t, float64(1), testutil.ToFloat64(s.throttledCount.WithLabelValues("store", "ops")))
+}
+
+func TestOpsLimitContextCanceled(t *testing.T) {
+	s, err := New(memory.New(), WithOpsLimit(store.OperationCopy, 0.001, 1))
+	require.NoError(t, err)
+
+	require.NoError(t, s.Store(context.TODO(), "src", bytes.NewReader([]byte("test")), nil))
+	require.NoError(t, s.Copy(context.TODO(), "src", "dst"))
+
+	ctx, cancel := context.WithTimeout(context.TODO(), 10*time.Millisecond)
+	defer cancel()
+	err = s.Copy(ctx, "src", "dst")
+	require.Error(t, err)
+}
+
+func TestOpsLimitInvalidBurst(t *testing.T) {
+	_, err := New(memory.New(), WithOpsLimit(store.OperationDelete, 20, 0))
+	require.Error(t, err)
+}
+
+func TestOpsLimitBatch(t *testing.T) {
+	s, err := New(memory.New(), WithOpsLimit(store.OperationDelete, 20, 2))
+	require.NoError(t, err)
+
+	ctx := context.TODO()
+	start := time.Now()
+	results := store.DeleteBatch(ctx, s, []string{"a", "b", "c", "d"})
+	require.Len(t, results, 4)
+	require.NoError(t, store.BatchError(results))
+	assert.GreaterOrEqual(t, time.Since(start), 90*time.Millisecond, "A batch should consume one token per key")
+	assert.Equal(t, float64(1), testutil.ToFloat64(s.throttledCount.WithLabelValues("delete", "ops")))
+
+	cctx, cancel := context.WithCancel(ctx)
+	cancel()
+	results = store.DeleteBatch(cctx, s, []string{"a", "b", "c"})
+	require.Len(t, results, 3)
+	for _, res := range results {
+		assert.Error(t, res.Err)
+	}
+}
+
+func TestBytesLimit(t *testing.T) {
+	s, err := New(
+		memory.New(),
+		WithBytesLimit(store.OperationStore, 200, 10),
+		WithBytesLimit(store.OperationLoad, 200, 10),
+	)
+	require.NoError(t, err)
+
+	data := bytes.Repeat([]byte("a"), 30)
+	ctx := context.TODO()
+
+	start := time.Now()
+	require.NoError(t, s.Store(ctx, "test", bytes.NewReader(data), nil))
+	assert.GreaterOrEqual(t, time.Since(start), 90*time.Millisecond)
+
+	start = time.Now()
+	reader, _, err := s.Load(ctx, "test")
+	require.NoError(t, err)
+	b, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	require.NoError(t, reader.Close())
+	assert.Equal(t, data, b)
+	assert.GreaterOrEqual(t, time.Since(start), 90*time.Millisecond)
+
+	assert.Greater(t, testutil.ToFloat64(s.throttledDuration.WithLabelValues("store", "bytes")), float64(0))
+	assert.Greater(t, testutil.ToFloat64(s.throttledDuration.WithLabelValues("load", "bytes")), float64(0))
+}
+
+func TestBytesLimitInvalidOperation(t *testing.T) {
+	_, err := New(memory.New(), WithBytesLimit(store.OperationDelete, 200, 10))
+	require.Error(t, err)
+}
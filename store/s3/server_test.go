@@ -0,0 +1,196 @@
+package s3
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/nmvalera/go-utils/common"
+	"github.com/nmvalera/go-utils/store"
+	s3testutils "github.com/nmvalera/go-utils/store/s3/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStoreWithServer(t *testing.T) {
+	srv := s3testutils.NewServer(t)
+	client := srv.Client()
+
+	s3Store, err := New(client, "test-bucket", WithKeyPrefix("test-prefix"))
+	require.NoError(t, err)
+
+	ctx := context.TODO()
+
+	t.Run("Store and Load", func(t *testing.T) {
+		err := s3Store.Store(
+			ctx,
+			"test-key",
+			io.MultiReader(strings.NewReader("test-"), strings.NewReader("data")), // unseekable reader
+			&store.Headers{
+				ContentType:     store.ContentTypeJSON,
+				ContentEncoding: store.ContentEncodingGzip,
+				KeyValue: map[string]string{
+					"test-meta": "test-value",
+				},
+			},
+		)
+		require.NoError(t, err)
+
+		reader, headers, err := s3Store.Load(ctx, "test-key")
+		require.NoError(t, err)
+		defer func() { _ = reader.Close() }()
+
+		b, err := io.ReadAll(reader)
+		require.NoError(t, err)
+		assert.Equal(t, "test-data", string(b))
+		assert.Equal(t, store.ContentTypeJSON, headers.ContentType)
+		assert.Equal(t, store.ContentEncodingGzip, headers.ContentEncoding)
+		assert.Equal(t, "test-value", headers.KeyValue["test-meta"])
+	})
+
+	t.Run("Load not found", func(t *testing.T) {
+		_, _, err := s3Store.Load(ctx, "test-key-unknown")
+		require.ErrorIs(t, err, store.ErrNotFound)
+	})
+
+	t.Run("Conditional writes", func(t *testing.T) {
+		cs := s3Store.(store.ConditionalStore)
+
+		require.NoError(t, cs.StoreIfMatch(ctx, "test-cond", strings.NewReader("a"), nil, ""))
+		err := cs.StoreIfMatch(ctx, "test-cond", strings.NewReader("b"), nil, "")
+		require.ErrorIs(t, err, store.ErrPreconditionFailed, "Storing an existing object without ETag should fail")
+
+		reader, etag, err := cs.LoadWithETag(ctx, "test-cond")
+		require.NoError(t, err)
+		_ = reader.Close()
+		require.NotEmpty(t, etag)
+
+		require.NoError(t, cs.StoreIfMatch(ctx, "test-cond", strings.NewReader("b"), nil, etag))
+		err = cs.StoreIfMatch(ctx, "test-cond", strings.NewReader("c"), nil, etag)
+		require.ErrorIs(t, err, store.ErrPreconditionFailed, "Storing with a stale ETag should fail")
+		require.ErrorIs(t, cs.DeleteIfMatch(ctx, "test-cond", etag), store.ErrPreconditionFailed, "Deleting with a stale ETag should fail")
+
+		reader, etag, err = cs.LoadWithETag(ctx, "test-cond")
+		require.NoError(t, err)
+		_ = reader.Close()
+		require.NoError(t, cs.DeleteIfMatch(ctx, "test-cond", etag))
+
+		_, _, err = cs.LoadWithETag(ctx, "test-cond")
+		require.ErrorIs(t, err, store.ErrNotFound)
+	})
+
+	t.Run("Head", func(t *testing.T) {
+		output, err := client.HeadObject(ctx, &s3.HeadObjectInput{
+			Bucket: common.Ptr("test-bucket"),
+			Key:    common.Ptr("test-prefix/test-key"),
+		})
+		require.NoError(t, err)
+		assert.Equal(t, int64(len("test-data")), common.Val(output.ContentLength))
+		assert.Equal(t, store.ContentTypeJSON.String(), common.Val(output.ContentType))
+	})
+
+	t.Run("Copy", func(t *testing.T) {
+		err := s3Store.Copy(ctx, "test-key", "test-key-copy")
+		require.NoError(t, err)
+
+		reader, headers, err := s3Store.Load(ctx, "test-key-copy")
+		require.NoError(t, err)
+		defer func() { _ = reader.Close() }()
+
+		b, err := io.ReadAll(reader)
+		require.NoError(t, err)
+		assert.Equal(t, "test-data", string(b))
+		assert.Equal(t, store.ContentTypeJSON, headers.ContentType)
+	})
+
+	t.Run("List", func(t *testing.T) {
+		for i := 0; i < 3; i++ {
+			err := s3Store.Store(ctx, fmt.Sprintf("dir/test-key-%d", i), strings.NewReader("test-data"), nil)
+			require.NoError(t, err)
+		}
+
+		keys, err := store.List(ctx, s3Store, "dir/")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"dir/test-key-0", "dir/test-key-1", "dir/test-key-2"}, keys)
+
+		// Paginate
+		output, err := client.ListObjectsV2(ctx, &s3.ListObjectsV2Input{
+			Bucket:  common.Ptr("test-bucket"),
+			Prefix:  common.Ptr("test-prefix/dir/"),
+			MaxKeys: common.Ptr(int32(2)),
+		})
+		require.NoError(t, err)
+		require.Len(t, output.Contents, 2)
+		require.True(t, common.Val(output.IsTruncated))
+
+		output, err = client.ListObjectsV2(ctx, &s3.ListObjectsV2Input{
+			Bucket:            common.Ptr("test-bucket"),
+			Prefix:            common.Ptr("test-prefix/dir/"),
+			ContinuationToken: output.NextContinuationToken,
+		})
+		require.NoError(t, err)
+		require.Len(t, output.Contents, 1)
+		assert.Equal(t, "test-prefix/dir/test-key-2", common.Val(output.Contents[0].Key))
+	})
+
+	t.Run("DeletePrefix", func(t *testing.T) {
+		results, err := store.DeletePrefix(ctx, s3Store, "dir/")
+		require.NoError(t, err)
+		require.Len(t, results, 3)
+		require.NoError(t, store.BatchError(results))
+
+		keys, err := store.List(ctx, s3Store, "")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"test-key", "test-key-copy"}, keys)
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		err := s3Store.Delete(ctx, "test-key")
+		require.NoError(t, err)
+
+		_, _, err = s3Store.Load(ctx, "test-key")
+		require.ErrorIs(t, err, store.ErrNotFound)
+	})
+
+	t.Run("Multipart", func(t *testing.T) {
+		upload, err := client.CreateMultipartUpload(ctx, &s3.CreateMultipartUploadInput{
+			Bucket: common.Ptr("test-bucket"),
+			Key:    common.Ptr("test-prefix/test-key-multipart"),
+		})
+		require.NoError(t, err)
+
+		parts := []types.CompletedPart{}
+		for i, data := range []string{"part-1,", "part-2"} {
+			output, err := client.UploadPart(ctx, &s3.UploadPartInput{
+				Bucket:     common.Ptr("test-bucket"),
+				Key:        common.Ptr("test-prefix/test-key-multipart"),
+				UploadId:   upload.UploadId,
+				PartNumber: common.Ptr(int32(i + 1)),
+				Body:       bytes.NewReader([]byte(data)),
+			})
+			require.NoError(t, err)
+			parts = append(parts, types.CompletedPart{ETag: output.ETag, PartNumber: common.Ptr(int32(i + 1))})
+		}
+
+		_, err = client.CompleteMultipartUpload(ctx, &s3.CompleteMultipartUploadInput{
+			Bucket:          common.Ptr("test-bucket"),
+			Key:             common.Ptr("test-prefix/test-key-multipart"),
+			UploadId:        upload.UploadId,
+			MultipartUpload: &types.CompletedMultipartUpload{Parts: parts},
+		})
+		require.NoError(t, err)
+
+		reader, _, err := s3Store.Load(ctx, "test-key-multipart")
+		require.NoError(t, err)
+		defer func() { _ = reader.Close() }()
+
+		b, err := io.ReadAll(reader)
+		require.NoError(t, err)
+		assert.Equal(t, "part-1,part-2", string(b))
+	})
+}
@@ -5,9 +5,12 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"net/http"
 	"path/filepath"
+	"strings"
 
 	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
 	"github.com/aws/smithy-go"
 	"github.com/nmvalera/go-utils/aws"
 	"github.com/nmvalera/go-utils/common"
@@ -41,6 +44,29 @@ func New(s3c aws.S3ObjectClient, bucket string, opts ...Options) (store.Store, e
 
 // Store stores the data in the S3 bucket
 func (s *Store) Store(ctx context.Context, key string, reader io.Reader, headers *store.Headers) error {
+	_, err := s.client.PutObject(ctx, s.putObjectInput(key, reader, headers))
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// StoreIfMatch stores the data in the S3 bucket if the ETag of the object is etag (If-Match),
+// or if the object does not exist when etag is empty (If-None-Match)
+func (s *Store) StoreIfMatch(ctx context.Context, key string, reader io.Reader, headers *store.Headers, etag string) error {
+	input := s.putObjectInput(key, reader, headers)
+	if etag != "" {
+		input.IfMatch = common.Ptr(etag)
+	} else {
+		input.IfNoneMatch = common.Ptr("*")
+	}
+
+	_, err := s.client.PutObject(ctx, input)
+	return preconditionErr(err)
+}
+
+func (s *Store) putObjectInput(key string, reader io.Reader, headers *store.Headers) *s3.PutObjectInput {
 	input := &s3.PutObjectInput{
 		Bucket: common.Ptr(s.bucket),
 		Key:    common.Ptr(s.path(key)),
@@ -62,27 +88,14 @@ func (s *Store) Store(ctx context.Context, key string, reader io.Reader, headers
 		}
 	}
 
-	// Store the object
-	_, err := s.client.PutObject(ctx, input)
-	if err != nil {
-		return err
-	}
-
-	return nil
+	return input
 }
 
 // Load loads the data from the S3 bucket
 // It is the responsibility of the caller to close the returned reader
 func (s *Store) Load(ctx context.Context, key string) (io.ReadCloser, *store.Headers, error) {
-	output, err := s.client.GetObject(ctx, &s3.GetObjectInput{
-		Bucket: common.Ptr(s.bucket),
-		Key:    common.Ptr(s.path(key)),
-	})
+	output, err := s.getObject(ctx, key)
 	if err != nil {
-		var aerr smithy.APIError
-		if errors.As(err, &aerr) && aerr.ErrorCode() == "NoSuchKey" {
-			return nil, nil, store.ErrNotFound
-		}
 		return nil, nil, err
 	}
 
@@ -103,6 +116,32 @@ func (s *Store) Load(ctx context.Context, key string) (io.ReadCloser, *store.Hea
 	return output.Body, headers, nil
 }
 
+// LoadWithETag loads the data from the S3 bucket with its ETag
+// It is the responsibility of the caller to close the returned reader
+func (s *Store) LoadWithETag(ctx context.Context, key string) (io.ReadCloser, string, error) {
+	output, err := s.getObject(ctx, key)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return output.Body, common.Val(output.ETag), nil
+}
+
+func (s *Store) getObject(ctx context.Context, key string) (*s3.GetObjectOutput, error) {
+	output, err := s.client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: common.Ptr(s.bucket),
+		Key:    common.Ptr(s.path(key)),
+	})
+	if err != nil {
+		var aerr smithy.APIError
+		if errors.As(err, &aerr) && aerr.ErrorCode() == "NoSuchKey" {
+			return nil, store.ErrNotFound
+		}
+		return nil, err
+	}
+	return output, nil
+}
+
 // Copy copies an object from one key to another
 func (s *Store) Copy(ctx context.Context, srcKey, dstKey string) error {
 	_, err := s.client.CopyObject(ctx, &s3.CopyObjectInput{
@@ -124,6 +163,118 @@ func (s *Store) Delete(ctx context.Context, key string) error {
 	return err
 }
 
+// DeleteIfMatch deletes the object from the S3 bucket if its ETag is etag (If-Match)
+func (s *Store) DeleteIfMatch(ctx context.Context, key, etag string) error {
+	_, err := s.client.DeleteObject(ctx, &s3.DeleteObjectInput{
+		Bucket:  common.Ptr(s.bucket),
+		Key:     common.Ptr(s.path(key)),
+		IfMatch: common.Ptr(etag),
+	})
+	return preconditionErr(err)
+}
+
+// preconditionErr maps the errors of conditional requests to store.ErrPreconditionFailed
+// (412 Precondition Failed, or 409 Conflict when a concurrent conditional write is in progress)
+func preconditionErr(err error) error {
+	var rerr interface{ HTTPStatusCode() int }
+	if errors.As(err, &rerr) {
+		switch rerr.HTTPStatusCode() {
+		case http.StatusPreconditionFailed, http.StatusConflict:
+			return store.ErrPreconditionFailed
+		}
+	}
+	var aerr smithy.APIError
+	if errors.As(err, &aerr) && aerr.ErrorCode() == "NoSuchKey" {
+		return store.ErrPreconditionFailed
+	}
+	return err
+}
+
+// maxDeleteObjects is the maximum number of keys S3 accepts in a single DeleteObjects request
+const maxDeleteObjects = 1000
+
+// DeleteBatch deletes multiple objects using S3 DeleteObjects requests
+// Keys are split into chunks of 1000 keys, chunks are deleted in parallel
+func (s *Store) DeleteBatch(ctx context.Context, keys []string, opts ...store.BatchOption) []*store.BatchResult {
+	results := make([]*store.BatchResult, len(keys))
+
+	chunks := (len(keys) + maxDeleteObjects - 1) / maxDeleteObjects
+	store.RunBatch(ctx, chunks, store.NewBatchConfig(opts...).Concurrency, func(ctx context.Context, i int) *store.BatchResult {
+		start := i * maxDeleteObjects
+		end := min(start+maxDeleteObjects, len(keys))
+		s.deleteObjects(ctx, keys[start:end], results[start:end])
+		return nil
+	})
+
+	return results
+}
+
+func (s *Store) deleteObjects(ctx context.Context, keys []string, results []*store.BatchResult) {
+	objects := make([]types.ObjectIdentifier, len(keys))
+	for i, key := range keys {
+		objects[i] = types.ObjectIdentifier{Key: common.Ptr(s.path(key))}
+	}
+
+	output, err := s.client.DeleteObjects(ctx, &s3.DeleteObjectsInput{
+		Bucket: common.Ptr(s.bucket),
+		Delete: &types.Delete{
+			Objects: objects,
+			Quiet:   common.Ptr(true),
+		},
+	})
+
+	// Map S3 keys to per-key errors
+	errs := make(map[string]error)
+	if err == nil {
+		for _, e := range output.Errors {
+			errs[common.Val(e.Key)] = fmt.Errorf("%s: %s", common.Val(e.Code), common.Val(e.Message))
+		}
+	}
+
+	for i, key := range keys {
+		results[i] = &store.BatchResult{Key: key, Err: err}
+		if err == nil {
+			results[i].Err = errs[s.path(key)]
+		}
+	}
+}
+
+// List returns the keys of all objects which key starts with the given prefix using S3 ListObjectsV2
+func (s *Store) List(ctx context.Context, prefix string) ([]string, error) {
+	keys := make([]string, 0)
+	paginator := s3.NewListObjectsV2Paginator(s.client, &s3.ListObjectsV2Input{
+		Bucket: common.Ptr(s.bucket),
+		Prefix: common.Ptr(s.listPrefix(prefix)),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, obj := range page.Contents {
+			keys = append(keys, s.key(common.Val(obj.Key)))
+		}
+	}
+	return keys, nil
+}
+
+// listPrefix returns the S3 prefix for the given key prefix
+// It does not use path() to preserve trailing slashes
+func (s *Store) listPrefix(prefix string) string {
+	if s.keyPrefix == "" {
+		return prefix
+	}
+	return strings.TrimSuffix(s.keyPrefix, "/") + "/" + prefix
+}
+
+// key returns the store key for the given S3 key
+func (s *Store) key(path string) string {
+	if s.keyPrefix == "" {
+		return path
+	}
+	return strings.TrimPrefix(path, strings.TrimSuffix(s.keyPrefix, "/")+"/")
+}
+
 func (s *Store) path(key string) string {
 	return filepath.Join(s.keyPrefix, key)
 }
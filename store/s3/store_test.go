@@ -8,6 +8,7 @@ import (
 	"testing"
 
 	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
 	"github.com/nmvalera/go-utils/aws/mock"
 	"github.com/nmvalera/go-utils/common"
 	"github.com/nmvalera/go-utils/store"
@@ -131,4 +132,55 @@ func TestStore(t *testing.T) {
 		err = s3Store.Delete(ctx, "test-key-delete")
 		assert.NoError(t, err)
 	})
+
+	t.Run("DeleteBatch", func(t *testing.T) {
+		mockS3Client.EXPECT().DeleteObjects(
+			ctx,
+			gomock.Cond(func(obj *s3.DeleteObjectsInput) bool {
+				match := obj.Bucket != nil && *obj.Bucket == testBucketName
+				match = match && obj.Delete != nil && len(obj.Delete.Objects) == 2
+				match = match && *obj.Delete.Objects[0].Key == "test-prefix/test-key-1"
+				match = match && *obj.Delete.Objects[1].Key == "test-prefix/test-key-2"
+				return match
+			}),
+		).Return(&s3.DeleteObjectsOutput{
+			Errors: []types.Error{
+				{Key: common.Ptr("test-prefix/test-key-2"), Code: common.Ptr("AccessDenied"), Message: common.Ptr("Access Denied")},
+			},
+		}, nil)
+
+		results := store.DeleteBatch(ctx, s3Store, []string{"test-key-1", "test-key-2"})
+		require.Len(t, results, 2)
+		assert.Equal(t, "test-key-1", results[0].Key)
+		assert.NoError(t, results[0].Err)
+		assert.Equal(t, "test-key-2", results[1].Key)
+		assert.EqualError(t, results[1].Err, "AccessDenied: Access Denied")
+	})
+
+	t.Run("List", func(t *testing.T) {
+		mockS3Client.EXPECT().ListObjectsV2(
+			gomock.Any(),
+			gomock.Cond(func(obj *s3.ListObjectsV2Input) bool {
+				return obj.Prefix != nil && *obj.Prefix == "test-prefix/dir/" && obj.ContinuationToken == nil
+			}),
+			gomock.Any(),
+		).Return(&s3.ListObjectsV2Output{
+			Contents:              []types.Object{{Key: common.Ptr("test-prefix/dir/a")}},
+			IsTruncated:           common.Ptr(true),
+			NextContinuationToken: common.Ptr("next"),
+		}, nil)
+		mockS3Client.EXPECT().ListObjectsV2(
+			gomock.Any(),
+			gomock.Cond(func(obj *s3.ListObjectsV2Input) bool {
+				return obj.ContinuationToken != nil && *obj.ContinuationToken == "next"
+			}),
+			gomock.Any(),
+		).Return(&s3.ListObjectsV2Output{
+			Contents: []types.Object{{Key: common.Ptr("test-prefix/dir/b")}},
+		}, nil)
+
+		keys, err := store.List(ctx, s3Store, "dir/")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"dir/a", "dir/b"}, keys)
+	})
 }
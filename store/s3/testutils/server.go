@@ -0,0 +1,710 @@
+package s3testutils
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/nmvalera/go-utils/store"
+	"github.com/nmvalera/go-utils/store/memory"
+)
+
+const s3Namespace = "http://s3.amazonaws.com/doc/2006-03-01/"
+
+// Server is an in-process HTTP server implementing the subset of the S3 API used by store/s3
+//
+// It supports PutObject, GetObject, HeadObject, DeleteObject, DeleteObjects, CopyObject, ListObjectsV2
+// and multipart uploads, using path-style addressing. Any bucket is considered to exist.
+// PutObject and DeleteObject support conditional requests (If-Match and If-None-Match: *).
+//
+// Object data is kept in a memory store, object metadata is kept alongside.
+type Server struct {
+	srv *httptest.Server
+
+	data store.Store
+
+	mu      sync.RWMutex
+	objects map[string]*object
+	uploads map[string]*upload
+
+	uploadID atomic.Uint64
+}
+
+type object struct {
+	contentType     string
+	contentEncoding string
+	metadata        map[string]string
+	etag            string
+	size            int64
+	lastModified    time.Time
+}
+
+type upload struct {
+	bucket string
+	key    string
+	obj    *object
+	parts  map[int][]byte
+}
+
+// NewServer starts a new fake S3 server
+// The server is closed when the test completes
+func NewServer(t interface{ Cleanup(func()) }) *Server {
+	s := &Server{
+		data:    memory.New(),
+		objects: make(map[string]*object),
+		uploads: make(map[string]*upload),
+	}
+	s.srv = httptest.NewTLSServer(http.HandlerFunc(s.handle))
+	t.Cleanup(s.srv.Close)
+	return s
+}
+
+// URL returns the base URL of the server
+func (s *Server) URL() string {
+	return s.srv.URL
+}
+
+// Client returns a S3 client configured to send requests to the server
+func (s *Server) Client(optFns ...func(*s3.Options)) *s3.Client {
+	opts := s3.Options{
+		BaseEndpoint: aws.String(s.srv.URL),
+		Region:       "us-east-1",
+		UsePathStyle: true,
+		Credentials:  credentials.NewStaticCredentialsProvider("test-access-key", "test-secret-key", ""),
+		HTTPClient:   s.srv.Client(),
+	}
+	return s3.New(opts, optFns...)
+}
+
+func (s *Server) handle(w http.ResponseWriter, r *http.Request) {
+	bucket, key, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/"), "/")
+	if bucket == "" {
+		writeError(w, http.StatusNotImplemented, "NotImplemented", "bucket listing is not supported")
+		return
+	}
+
+	q := r.URL.Query()
+	switch {
+	case key == "" && r.Method == http.MethodGet:
+		s.listObjects(w, r, bucket)
+	case key == "" && r.Method == http.MethodPost && q.Has("delete"):
+		s.deleteObjects(w, r, bucket)
+	case key == "":
+		writeError(w, http.StatusNotImplemented, "NotImplemented", "bucket operation is not supported")
+	case r.Method == http.MethodPost && q.Has("uploads"):
+		s.createMultipartUpload(w, r, bucket, key)
+	case r.Method == http.MethodPut && q.Has("uploadId"):
+		s.uploadPart(w, r, q.Get("uploadId"), q.Get("partNumber"))
+	case r.Method == http.MethodPost && q.Has("uploadId"):
+		s.completeMultipartUpload(w, r, q.Get("uploadId"))
+	case r.Method == http.MethodDelete && q.Has("uploadId"):
+		s.abortMultipartUpload(w, q.Get("uploadId"))
+	case r.Method == http.MethodPut && r.Header.Get("X-Amz-Copy-Source") != "":
+		s.copyObject(w, r, bucket, key)
+	case r.Method == http.MethodPut:
+		s.putObject(w, r, bucket, key)
+	case r.Method == http.MethodGet:
+		s.getObject(w, r, bucket, key, true)
+	case r.Method == http.MethodHead:
+		s.getObject(w, r, bucket, key, false)
+	case r.Method == http.MethodDelete:
+		s.deleteObject(w, r, bucket, key)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "MethodNotAllowed", "method not allowed")
+	}
+}
+
+func (s *Server) putObject(w http.ResponseWriter, r *http.Request, bucket, key string) {
+	body, err := readBody(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "IncompleteBody", err.Error())
+		return
+	}
+
+	obj := newObject(r.Header)
+	err = s.put(r.Context(), bucket, key, obj, body, r.Header)
+	if errors.Is(err, errPreconditionFailed) {
+		writePreconditionFailed(w)
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "InternalError", err.Error())
+		return
+	}
+
+	w.Header().Set("ETag", obj.etag)
+	w.WriteHeader(http.StatusOK)
+}
+
+// errPreconditionFailed is returned when the conditions of a request do not match the current object
+var errPreconditionFailed = errors.New("precondition failed")
+
+// put stores the object, h holds the conditions of the request if any
+func (s *Server) put(ctx context.Context, bucket, key string, obj *object, body []byte, h http.Header) error {
+	sum := md5.Sum(body)
+	obj.etag = strconv.Quote(hex.EncodeToString(sum[:]))
+	obj.size = int64(len(body))
+	obj.lastModified = time.Now().UTC()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !preconditionsMatch(h, s.objects[path(bucket, key)]) {
+		return errPreconditionFailed
+	}
+	if err := s.data.Store(ctx, path(bucket, key), bytes.NewReader(body), nil); err != nil {
+		return err
+	}
+	s.objects[path(bucket, key)] = obj
+	return nil
+}
+
+func (s *Server) getObject(w http.ResponseWriter, r *http.Request, bucket, key string, withBody bool) {
+	s.mu.RLock()
+	obj, ok := s.objects[path(bucket, key)]
+	var (
+		reader io.ReadCloser
+		err    error
+	)
+	if ok && withBody {
+		reader, _, err = s.data.Load(r.Context(), path(bucket, key))
+	}
+	s.mu.RUnlock()
+
+	if !ok {
+		if withBody {
+			writeError(w, http.StatusNotFound, "NoSuchKey", "The specified key does not exist.")
+		} else {
+			w.WriteHeader(http.StatusNotFound)
+		}
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "InternalError", err.Error())
+		return
+	}
+
+	h := w.Header()
+	if obj.contentType != "" {
+		h.Set("Content-Type", obj.contentType)
+	}
+	if obj.contentEncoding != "" {
+		h.Set("Content-Encoding", obj.contentEncoding)
+	}
+	for k, v := range obj.metadata {
+		h.Set("X-Amz-Meta-"+k, v)
+	}
+	h.Set("ETag", obj.etag)
+	h.Set("Last-Modified", obj.lastModified.Format(http.TimeFormat))
+	h.Set("Content-Length", strconv.FormatInt(obj.size, 10))
+	w.WriteHeader(http.StatusOK)
+
+	if withBody {
+		defer func() { _ = reader.Close() }()
+		_, _ = io.Copy(w, reader)
+	}
+}
+
+func (s *Server) deleteObject(w http.ResponseWriter, r *http.Request, bucket, key string) {
+	err := s.delete(r.Context(), bucket, key, r.Header)
+	if errors.Is(err, errPreconditionFailed) {
+		writePreconditionFailed(w)
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "InternalError", err.Error())
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// delete deletes the object, h holds the conditions of the request if any
+func (s *Server) delete(ctx context.Context, bucket, key string, h http.Header) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !preconditionsMatch(h, s.objects[path(bucket, key)]) {
+		return errPreconditionFailed
+	}
+	delete(s.objects, path(bucket, key))
+	return s.data.Delete(ctx, path(bucket, key))
+}
+
+type deleteRequest struct {
+	Quiet   bool `xml:"Quiet"`
+	Objects []struct {
+		Key string `xml:"Key"`
+	} `xml:"Object"`
+}
+
+type deleteResult struct {
+	XMLName xml.Name        `xml:"DeleteResult"`
+	Xmlns   string          `xml:"xmlns,attr"`
+	Deleted []deletedObject `xml:"Deleted"`
+	Errors  []deleteError   `xml:"Error"`
+}
+
+type deletedObject struct {
+	Key string `xml:"Key"`
+}
+
+type deleteError struct {
+	Key     string `xml:"Key"`
+	Code    string `xml:"Code"`
+	Message string `xml:"Message"`
+}
+
+func (s *Server) deleteObjects(w http.ResponseWriter, r *http.Request, bucket string) {
+	body, err := readBody(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "IncompleteBody", err.Error())
+		return
+	}
+
+	req := new(deleteRequest)
+	if err := xml.Unmarshal(body, req); err != nil {
+		writeError(w, http.StatusBadRequest, "MalformedXML", err.Error())
+		return
+	}
+
+	res := &deleteResult{Xmlns: s3Namespace}
+	for _, o := range req.Objects {
+		if err := s.delete(r.Context(), bucket, o.Key, nil); err != nil {
+			res.Errors = append(res.Errors, deleteError{Key: o.Key, Code: "InternalError", Message: err.Error()})
+			continue
+		}
+		if !req.Quiet {
+			res.Deleted = append(res.Deleted, deletedObject{Key: o.Key})
+		}
+	}
+
+	writeXML(w, http.StatusOK, res)
+}
+
+type copyObjectResult struct {
+	XMLName      xml.Name `xml:"CopyObjectResult"`
+	Xmlns        string   `xml:"xmlns,attr"`
+	ETag         string   `xml:"ETag"`
+	LastModified string   `xml:"LastModified"`
+}
+
+func (s *Server) copyObject(w http.ResponseWriter, r *http.Request, bucket, key string) {
+	src, err := url.PathUnescape(r.Header.Get("X-Amz-Copy-Source"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "InvalidArgument", err.Error())
+		return
+	}
+	srcBucket, srcKey, _ := strings.Cut(strings.TrimPrefix(src, "/"), "/")
+
+	s.mu.RLock()
+	srcObj, ok := s.objects[path(srcBucket, srcKey)]
+	var reader io.ReadCloser
+	if ok {
+		reader, _, err = s.data.Load(r.Context(), path(srcBucket, srcKey))
+	}
+	s.mu.RUnlock()
+
+	if !ok {
+		writeError(w, http.StatusNotFound, "NoSuchKey", "The specified key does not exist.")
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "InternalError", err.Error())
+		return
+	}
+	defer func() { _ = reader.Close() }()
+
+	body, err := io.ReadAll(reader)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "InternalError", err.Error())
+		return
+	}
+
+	obj := &object{
+		contentType:     srcObj.contentType,
+		contentEncoding: srcObj.contentEncoding,
+		metadata:        srcObj.metadata,
+	}
+	if r.Header.Get("X-Amz-Metadata-Directive") == "REPLACE" {
+		obj = newObject(r.Header)
+	}
+
+	if err := s.put(r.Context(), bucket, key, obj, body, nil); err != nil {
+		writeError(w, http.StatusInternalServerError, "InternalError", err.Error())
+		return
+	}
+
+	writeXML(w, http.StatusOK, &copyObjectResult{
+		Xmlns:        s3Namespace,
+		ETag:         obj.etag,
+		LastModified: obj.lastModified.Format(time.RFC3339),
+	})
+}
+
+type listBucketResult struct {
+	XMLName               xml.Name       `xml:"ListBucketResult"`
+	Xmlns                 string         `xml:"xmlns,attr"`
+	Name                  string         `xml:"Name"`
+	Prefix                string         `xml:"Prefix"`
+	KeyCount              int            `xml:"KeyCount"`
+	MaxKeys               int            `xml:"MaxKeys"`
+	IsTruncated           bool           `xml:"IsTruncated"`
+	ContinuationToken     string         `xml:"ContinuationToken,omitempty"`
+	NextContinuationToken string         `xml:"NextContinuationToken,omitempty"`
+	Contents              []listedObject `xml:"Contents"`
+	CommonPrefixes        []commonPrefix `xml:"CommonPrefixes"`
+	Delimiter             string         `xml:"Delimiter,omitempty"`
+	StartAfter            string         `xml:"StartAfter,omitempty"`
+}
+
+type listedObject struct {
+	Key          string `xml:"Key"`
+	LastModified string `xml:"LastModified"`
+	ETag         string `xml:"ETag"`
+	Size         int64  `xml:"Size"`
+	StorageClass string `xml:"StorageClass"`
+}
+
+type commonPrefix struct {
+	Prefix string `xml:"Prefix"`
+}
+
+func (s *Server) listObjects(w http.ResponseWriter, r *http.Request, bucket string) {
+	q := r.URL.Query()
+	prefix := q.Get("prefix")
+	delimiter := q.Get("delimiter")
+	maxKeys := 1000
+	if mk := q.Get("max-keys"); mk != "" {
+		n, err := strconv.Atoi(mk)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "InvalidArgument", err.Error())
+			return
+		}
+		maxKeys = n
+	}
+
+	// Continuation token is the last key returned in the previous page
+	startAfter := q.Get("start-after")
+	if token := q.Get("continuation-token"); token != "" {
+		startAfter = token
+	}
+
+	s.mu.RLock()
+	keys, err := store.List(r.Context(), s.data, path(bucket, prefix))
+	objects := make(map[string]*object, len(keys))
+	for _, k := range keys {
+		objects[k] = s.objects[k]
+	}
+	s.mu.RUnlock()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "InternalError", err.Error())
+		return
+	}
+
+	res := &listBucketResult{
+		Xmlns:             s3Namespace,
+		Name:              bucket,
+		Prefix:            prefix,
+		MaxKeys:           maxKeys,
+		Delimiter:         delimiter,
+		StartAfter:        q.Get("start-after"),
+		ContinuationToken: q.Get("continuation-token"),
+	}
+
+	seenPrefixes := make(map[string]bool)
+	for _, k := range keys {
+		key := strings.TrimPrefix(k, bucket+"/")
+		if key <= startAfter {
+			continue
+		}
+
+		if res.KeyCount == maxKeys {
+			res.IsTruncated = true
+			break
+		}
+
+		if delimiter != "" {
+			if i := strings.Index(key[len(prefix):], delimiter); i >= 0 {
+				cp := key[:len(prefix)+i+len(delimiter)]
+				if !seenPrefixes[cp] {
+					seenPrefixes[cp] = true
+					res.CommonPrefixes = append(res.CommonPrefixes, commonPrefix{Prefix: cp})
+					res.KeyCount++
+				}
+				startAfter = key
+				continue
+			}
+		}
+
+		obj := objects[k]
+		res.Contents = append(res.Contents, listedObject{
+			Key:          key,
+			LastModified: obj.lastModified.Format(time.RFC3339),
+			ETag:         obj.etag,
+			Size:         obj.size,
+			StorageClass: "STANDARD",
+		})
+		res.KeyCount++
+		startAfter = key
+	}
+
+	if res.IsTruncated {
+		res.NextContinuationToken = startAfter
+	}
+
+	writeXML(w, http.StatusOK, res)
+}
+
+type initiateMultipartUploadResult struct {
+	XMLName  xml.Name `xml:"InitiateMultipartUploadResult"`
+	Xmlns    string   `xml:"xmlns,attr"`
+	Bucket   string   `xml:"Bucket"`
+	Key      string   `xml:"Key"`
+	UploadID string   `xml:"UploadId"`
+}
+
+func (s *Server) createMultipartUpload(w http.ResponseWriter, r *http.Request, bucket, key string) {
+	id := strconv.FormatUint(s.uploadID.Add(1), 10)
+
+	s.mu.Lock()
+	s.uploads[id] = &upload{
+		bucket: bucket,
+		key:    key,
+		obj:    newObject(r.Header),
+		parts:  make(map[int][]byte),
+	}
+	s.mu.Unlock()
+
+	writeXML(w, http.StatusOK, &initiateMultipartUploadResult{
+		Xmlns:    s3Namespace,
+		Bucket:   bucket,
+		Key:      key,
+		UploadID: id,
+	})
+}
+
+func (s *Server) uploadPart(w http.ResponseWriter, r *http.Request, uploadID, partNumber string) {
+	n, err := strconv.Atoi(partNumber)
+	if err != nil || n < 1 {
+		writeError(w, http.StatusBadRequest, "InvalidArgument", fmt.Sprintf("invalid part number %q", partNumber))
+		return
+	}
+
+	body, err := readBody(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "IncompleteBody", err.Error())
+		return
+	}
+
+	s.mu.Lock()
+	u, ok := s.uploads[uploadID]
+	if ok {
+		u.parts[n] = body
+	}
+	s.mu.Unlock()
+
+	if !ok {
+		writeError(w, http.StatusNotFound, "NoSuchUpload", "The specified upload does not exist.")
+		return
+	}
+
+	sum := md5.Sum(body)
+	w.Header().Set("ETag", strconv.Quote(hex.EncodeToString(sum[:])))
+	w.WriteHeader(http.StatusOK)
+}
+
+type completeMultipartUpload struct {
+	Parts []struct {
+		PartNumber int `xml:"PartNumber"`
+	} `xml:"Part"`
+}
+
+type completeMultipartUploadResult struct {
+	XMLName xml.Name `xml:"CompleteMultipartUploadResult"`
+	Xmlns   string   `xml:"xmlns,attr"`
+	Bucket  string   `xml:"Bucket"`
+	Key     string   `xml:"Key"`
+	ETag    string   `xml:"ETag"`
+}
+
+func (s *Server) completeMultipartUpload(w http.ResponseWriter, r *http.Request, uploadID string) {
+	body, err := readBody(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "IncompleteBody", err.Error())
+		return
+	}
+
+	req := new(completeMultipartUpload)
+	if err := xml.Unmarshal(body, req); err != nil {
+		writeError(w, http.StatusBadRequest, "MalformedXML", err.Error())
+		return
+	}
+
+	s.mu.Lock()
+	u, ok := s.uploads[uploadID]
+	delete(s.uploads, uploadID)
+	s.mu.Unlock()
+
+	if !ok {
+		writeError(w, http.StatusNotFound, "NoSuchUpload", "The specified upload does not exist.")
+		return
+	}
+
+	sort.Slice(req.Parts, func(i, j int) bool { return req.Parts[i].PartNumber < req.Parts[j].PartNumber })
+
+	data := new(bytes.Buffer)
+	for _, p := range req.Parts {
+		part, ok := u.parts[p.PartNumber]
+		if !ok {
+			writeError(w, http.StatusBadRequest, "InvalidPart", fmt.Sprintf("part %d has not been uploaded", p.PartNumber))
+			return
+		}
+		data.Write(part)
+	}
+
+	if err := s.put(r.Context(), u.bucket, u.key, u.obj, data.Bytes(), nil); err != nil {
+		writeError(w, http.StatusInternalServerError, "InternalError", err.Error())
+		return
+	}
+
+	writeXML(w, http.StatusOK, &completeMultipartUploadResult{
+		Xmlns:  s3Namespace,
+		Bucket: u.bucket,
+		Key:    u.key,
+		ETag:   u.obj.etag,
+	})
+}
+
+func (s *Server) abortMultipartUpload(w http.ResponseWriter, uploadID string) {
+	s.mu.Lock()
+	_, ok := s.uploads[uploadID]
+	delete(s.uploads, uploadID)
+	s.mu.Unlock()
+
+	if !ok {
+		writeError(w, http.StatusNotFound, "NoSuchUpload", "The specified upload does not exist.")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func newObject(h http.Header) *object {
+	obj := &object{
+		contentType:     h.Get("Content-Type"),
+		contentEncoding: contentEncoding(h.Get("Content-Encoding")),
+		metadata:        make(map[string]string),
+	}
+	for k, v := range h {
+		if name, ok := strings.CutPrefix(strings.ToLower(k), "x-amz-meta-"); ok && len(v) > 0 {
+			obj.metadata[name] = v[0]
+		}
+	}
+	return obj
+}
+
+// contentEncoding removes the aws-chunked transfer encoding from a Content-Encoding header
+func contentEncoding(h string) string {
+	var encodings []string
+	for _, e := range strings.Split(h, ",") {
+		if e = strings.TrimSpace(e); e != "" && e != "aws-chunked" {
+			encodings = append(encodings, e)
+		}
+	}
+	return strings.Join(encodings, ",")
+}
+
+// readBody reads the request body, decoding aws-chunked encoded payloads
+func readBody(r *http.Request) ([]byte, error) {
+	if !strings.Contains(r.Header.Get("Content-Encoding"), "aws-chunked") && !strings.HasPrefix(r.Header.Get("X-Amz-Content-Sha256"), "STREAMING-") {
+		return io.ReadAll(r.Body)
+	}
+	return readChunked(bufio.NewReader(r.Body))
+}
+
+// readChunked decodes an aws-chunked payload
+// Chunk signatures and trailing checksums are not verified
+func readChunked(r *bufio.Reader) ([]byte, error) {
+	data := new(bytes.Buffer)
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return nil, fmt.Errorf("failed to read chunk header: %w", err)
+		}
+
+		sizeHex, _, _ := strings.Cut(strings.TrimSpace(line), ";")
+		size, err := strconv.ParseInt(sizeHex, 16, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid chunk size %q: %w", sizeHex, err)
+		}
+
+		if size == 0 {
+			// Trailing headers follow the last chunk, we ignore them
+			return data.Bytes(), nil
+		}
+
+		if _, err := io.CopyN(data, r, size); err != nil {
+			return nil, fmt.Errorf("failed to read chunk: %w", err)
+		}
+
+		if _, err := r.ReadString('\n'); err != nil {
+			return nil, fmt.Errorf("failed to read chunk trailer: %w", err)
+		}
+	}
+}
+
+type errorResponse struct {
+	XMLName xml.Name `xml:"Error"`
+	Code    string   `xml:"Code"`
+	Message string   `xml:"Message"`
+}
+
+// preconditionsMatch returns true if the If-Match and If-None-Match conditions of h match the current object (nil if none)
+func preconditionsMatch(h http.Header, current *object) bool {
+	if ifMatch := h.Get("If-Match"); ifMatch != "" {
+		return current != nil && current.etag == ifMatch
+	}
+	if h.Get("If-None-Match") == "*" {
+		return current == nil
+	}
+	return true
+}
+
+func writePreconditionFailed(w http.ResponseWriter) {
+	writeError(w, http.StatusPreconditionFailed, "PreconditionFailed", "At least one of the pre-conditions you specified did not hold")
+}
+
+func writeError(w http.ResponseWriter, status int, code, message string) {
+	writeXML(w, status, &errorResponse{Code: code, Message: message})
+}
+
+func writeXML(w http.ResponseWriter, status int, v any) {
+	b, err := xml.Marshal(v)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/xml")
+	w.WriteHeader(status)
+	_, _ = w.Write([]byte(xml.Header))
+	_, _ = w.Write(b)
+}
+
+func path(bucket, key string) string {
+	return bucket + "/" + key
+}
@@ -35,6 +35,20 @@ type Store interface {
 
 var ErrNotFound = errors.New("not found")
 
+// Operation identifies an operation performed on a store
+type Operation string
+
+const (
+	OperationStore  Operation = "store"
+	OperationLoad   Operation = "load"
+	OperationDelete Operation = "delete"
+	OperationCopy   Operation = "copy"
+
+	OperationList        Operation = "list"
+	OperationDeleteBatch Operation = "delete_batch"
+	OperationCopyBatch   Operation = "copy_batch"
+)
+
 // Headers are optional metadata about an object to store/load
 type Headers struct {
 	// ContentType is the type of the object
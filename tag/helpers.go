@@ -1,5 +1,7 @@
 package tag
 
+import "regexp"
+
 func MapTags(m map[string]any) []*Tag {
 	tags := make([]*Tag, 0, len(m))
 
@@ -23,3 +25,19 @@ func MapTags(m map[string]any) []*Tag {
 	}
 	return tags
 }
+
+var invalidLabelChars = regexp.MustCompile("[^a-zA-Z0-9_]")
+
+// Labels converts tags with scalar values into flat labels (e.g. Prometheus const labels)
+// Keys are sanitized by replacing all non-alphanumeric characters with underscores
+// Tags with object or map values are ignored
+func Labels(tags ...*Tag) map[string]string {
+	labels := make(map[string]string)
+	for _, t := range tags {
+		switch t.Value.Type {
+		case BOOL, INT64, FLOAT64, STRING:
+			labels[invalidLabelChars.ReplaceAllString(string(t.Key), "_")] = t.Value.String()
+		}
+	}
+	return labels
+}
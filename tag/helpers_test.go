@@ -120,3 +120,18 @@ func TestMapTags(t *testing.T) {
 		assert.Empty(t, MapTags(map[string]any{}))
 	})
 }
+
+func TestLabels(t *testing.T) {
+	labels := Labels(
+		Key("component").String("store"),
+		Key("store.key").String("test-key"),
+		Key("count").Int64(3),
+		Key("obj").Object(struct{}{}),
+		Key("map").Map(Key("inner").String("x")),
+	)
+	assert.Equal(t, map[string]string{
+		"component": "store",
+		"store_key": "test-key",
+		"count":     "3",
+	}, labels)
+}
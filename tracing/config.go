@@ -0,0 +1,115 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/nmvalera/go-utils/common"
+	"github.com/nmvalera/go-utils/config"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+const (
+	// ExporterNone disables tracing
+	ExporterNone = "none"
+	// ExporterStdout writes spans as JSON to stdout
+	ExporterStdout = "stdout"
+	// ExporterFile writes spans as JSON to a file (see Config.File)
+	ExporterFile = "file"
+	// ExporterOTLPHTTP sends spans to an OTLP collector over HTTP (see Config.Endpoint)
+	ExporterOTLPHTTP = "otlphttp"
+)
+
+// DefaultConfig returns a default Config (tracing disabled)
+func DefaultConfig() *Config {
+	return &Config{
+		Exporter:    common.Ptr(ExporterNone),
+		File:        common.Ptr("traces.json"),
+		Endpoint:    common.Ptr("localhost:4318"),
+		Insecure:    common.Ptr(false),
+		SampleRatio: common.Ptr(1.0),
+	}
+}
+
+// Config is the configuration to create a TracerProvider
+type Config struct {
+	Exporter    *string  `key:"exporter" env:"EXPORTER" flag:"exporter" desc:"Span exporter (one of none|stdout|file|otlphttp)"`
+	File        *string  `key:"file" env:"FILE" flag:"file" desc:"Path of the file spans are written to by the file exporter"`
+	Endpoint    *string  `key:"endpoint" env:"ENDPOINT" flag:"endpoint" desc:"Collector host:port spans are sent to by the otlphttp exporter"`
+	Insecure    *bool    `key:"insecure" env:"INSECURE" flag:"insecure" desc:"Disable TLS for the otlphttp exporter"`
+	SampleRatio *float64 `key:"sampleRatio" env:"SAMPLE_RATIO" flag:"sample-ratio" desc:"Ratio of root spans sampled (between 0 and 1)"`
+}
+
+func (cfg *Config) MarshalJSON() ([]byte, error) {
+	return config.Marshal(cfg)
+}
+
+// Enabled returns true if an exporter is configured
+func (cfg *Config) Enabled() bool {
+	return cfg != nil && cfg.Exporter != nil && *cfg.Exporter != "" && *cfg.Exporter != ExporterNone
+}
+
+// TracerProvider creates a TracerProvider exporting spans with the configured exporter
+//
+// The TracerProvider must be shut down to flush remaining spans and release the exporter
+func (cfg *Config) TracerProvider(opts ...sdktrace.TracerProviderOption) (*sdktrace.TracerProvider, error) {
+	exporter, err := cfg.exporter()
+	if err != nil {
+		return nil, err
+	}
+
+	ratio := 1.0
+	if cfg.SampleRatio != nil {
+		ratio = *cfg.SampleRatio
+	}
+
+	opts = append([]sdktrace.TracerProviderOption{
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(ratio))),
+	}, opts...)
+
+	return sdktrace.NewTracerProvider(opts...), nil
+}
+
+func (cfg *Config) exporter() (sdktrace.SpanExporter, error) {
+	switch common.Val(cfg.Exporter) {
+	case ExporterStdout:
+		return stdouttrace.New()
+	case ExporterFile:
+		f, err := os.OpenFile(common.Val(cfg.File), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open traces file: %w", err)
+		}
+		exporter, err := stdouttrace.New(stdouttrace.WithWriter(f))
+		if err != nil {
+			_ = f.Close()
+			return nil, err
+		}
+		return &fileExporter{SpanExporter: exporter, file: f}, nil
+	case ExporterOTLPHTTP:
+		opts := []otlptracehttp.Option{otlptracehttp.WithEndpoint(common.Val(cfg.Endpoint))}
+		if common.Val(cfg.Insecure) {
+			opts = append(opts, otlptracehttp.WithInsecure())
+		}
+		return otlptracehttp.New(context.Background(), opts...)
+	default:
+		return nil, fmt.Errorf("invalid tracing exporter %q (must be one of %q)", common.Val(cfg.Exporter), []string{ExporterStdout, ExporterFile, ExporterOTLPHTTP})
+	}
+}
+
+// fileExporter closes the file spans are written to on shutdown
+type fileExporter struct {
+	sdktrace.SpanExporter
+	file *os.File
+}
+
+func (e *fileExporter) Shutdown(ctx context.Context) error {
+	err := e.SpanExporter.Shutdown(ctx)
+	if closeErr := e.file.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
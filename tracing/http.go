@@ -0,0 +1,43 @@
+package tracing
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/nmvalera/go-utils/net/http/middleware"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Middleware creates a server span for every request
+//
+// The trace context of the caller is extracted from the request headers with the global propagator.
+// The span is marked as failed if the response status code is 5xx.
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		ctx := otel.GetTextMapPropagator().Extract(req.Context(), propagation.HeaderCarrier(req.Header))
+
+		ctx, span := StartSpan(
+			ctx,
+			fmt.Sprintf("HTTP %s", req.Method),
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				attribute.String("http.request.method", req.Method),
+				attribute.String("url.path", req.URL.Path),
+				attribute.String("server.address", req.Host),
+			),
+		)
+		defer span.End()
+
+		srw := middleware.NewStatusResponseWriter(rw)
+		next.ServeHTTP(srw, req.WithContext(ctx))
+
+		span.SetAttributes(attribute.Int("http.response.status_code", srw.Status()))
+		if srw.Status() >= http.StatusInternalServerError {
+			span.SetStatus(codes.Error, http.StatusText(srw.Status()))
+		}
+	})
+}
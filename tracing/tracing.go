@@ -0,0 +1,72 @@
+// Package tracing provides OpenTelemetry tracing utilities
+//
+// Spans are created with the global TracerProvider (see otel.SetTracerProvider), which app.App sets when tracing is configured.
+// Tags attached to the context (see tag.WithTags) are recorded as span attributes.
+package tracing
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/nmvalera/go-utils/tag"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// InstrumentationName is the name of the tracer used by the packages of this module
+const InstrumentationName = "github.com/nmvalera/go-utils"
+
+// Tracer returns the tracer of the global TracerProvider
+func Tracer() trace.Tracer {
+	return otel.Tracer(InstrumentationName)
+}
+
+// StartSpan starts a span with the tags attached to ctx as attributes
+func StartSpan(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	if tags := tag.FromContext(ctx); len(tags) > 0 {
+		opts = append(opts, trace.WithAttributes(TagsToAttributes(tags)...))
+	}
+	return Tracer().Start(ctx, name, opts...)
+}
+
+// End records err on the span, if not nil, and ends the span
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// TagsToAttributes converts tags to span attributes
+//
+// MAP tags are flattened using "<key>.<sub-key>" attribute keys
+func TagsToAttributes(tags []*tag.Tag) []attribute.KeyValue {
+	attrs := make([]attribute.KeyValue, 0, len(tags))
+	for _, t := range tags {
+		attrs = appendAttributes(attrs, string(t.Key), t)
+	}
+	return attrs
+}
+
+func appendAttributes(attrs []attribute.KeyValue, key string, t *tag.Tag) []attribute.KeyValue {
+	switch t.Value.Type {
+	case tag.BOOL:
+		return append(attrs, attribute.Bool(key, t.Value.Interface.(bool)))
+	case tag.INT64:
+		return append(attrs, attribute.Int64(key, t.Value.Interface.(int64)))
+	case tag.FLOAT64:
+		return append(attrs, attribute.Float64(key, t.Value.Interface.(float64)))
+	case tag.STRING:
+		return append(attrs, attribute.String(key, t.Value.Interface.(string)))
+	case tag.OBJECT:
+		return append(attrs, attribute.String(key, fmt.Sprintf("%v", t.Value.Interface)))
+	case tag.MAP:
+		for _, sub := range t.Value.Interface.(tag.Set) {
+			attrs = appendAttributes(attrs, key+"."+string(sub.Key), sub)
+		}
+	}
+	return attrs
+}
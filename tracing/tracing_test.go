@@ -0,0 +1,116 @@
+package tracing
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/nmvalera/go-utils/common"
+	"github.com/nmvalera/go-utils/tag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func setTestTracerProvider() *tracetest.SpanRecorder {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	return recorder
+}
+
+func TestTagsToAttributes(t *testing.T) {
+	attrs := TagsToAttributes([]*tag.Tag{
+		tag.Key("bool").Bool(true),
+		tag.Key("int").Int64(1),
+		tag.Key("float").Float64(1.5),
+		tag.Key("string").String("value"),
+		tag.Key("object").Object([]int{1, 2}),
+		tag.Key("map").Map(tag.Key("a").String("b"), tag.Key("c").Int64(2)),
+	})
+
+	assert.Equal(t, []attribute.KeyValue{
+		attribute.Bool("bool", true),
+		attribute.Int64("int", 1),
+		attribute.Float64("float", 1.5),
+		attribute.String("string", "value"),
+		attribute.String("object", "[1 2]"),
+		attribute.String("map.a", "b"),
+		attribute.Int64("map.c", 2),
+	}, attrs)
+}
+
+func TestStartSpan(t *testing.T) {
+	recorder := setTestTracerProvider()
+
+	ctx := tag.WithTags(context.Background(), tag.Key("component").String("test"))
+	_, span := StartSpan(ctx, "test-span")
+	End(span, errors.New("test error"))
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 1)
+	assert.Equal(t, "test-span", spans[0].Name())
+	assert.Contains(t, spans[0].Attributes(), attribute.String("component", "test"))
+	assert.Equal(t, codes.Error, spans[0].Status().Code)
+	assert.Equal(t, "test error", spans[0].Status().Description)
+}
+
+func TestMiddleware(t *testing.T) {
+	recorder := setTestTracerProvider()
+
+	h := Middleware(http.HandlerFunc(func(rw http.ResponseWriter, _ *http.Request) {
+		rw.WriteHeader(http.StatusServiceUnavailable)
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/test", http.NoBody)
+	req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	h.ServeHTTP(httptest.NewRecorder(), req)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 1)
+	assert.Equal(t, "HTTP GET", spans[0].Name())
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", spans[0].SpanContext().TraceID().String())
+	assert.Equal(t, "00f067aa0ba902b7", spans[0].Parent().SpanID().String())
+	assert.Contains(t, spans[0].Attributes(), attribute.String("url.path", "/test"))
+	assert.Contains(t, spans[0].Attributes(), attribute.Int("http.response.status_code", http.StatusServiceUnavailable))
+	assert.Equal(t, codes.Error, spans[0].Status().Code)
+}
+
+func TestConfigFileExporter(t *testing.T) {
+	cfg := DefaultConfig()
+	assert.False(t, cfg.Enabled())
+
+	cfg.Exporter = common.Ptr(ExporterFile)
+	cfg.File = common.Ptr(filepath.Join(t.TempDir(), "traces.json"))
+	require.True(t, cfg.Enabled())
+
+	tp, err := cfg.TracerProvider()
+	require.NoError(t, err)
+
+	_, span := tp.Tracer("test").Start(context.Background(), "test-span")
+	span.End()
+	require.NoError(t, tp.Shutdown(context.Background()))
+
+	b, err := os.ReadFile(*cfg.File)
+	require.NoError(t, err)
+	var exported struct{ Name string }
+	require.NoError(t, json.Unmarshal(b, &exported))
+	assert.Equal(t, "test-span", exported.Name)
+}
+
+func TestConfigInvalidExporter(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Exporter = common.Ptr("invalid")
+	_, err := cfg.TracerProvider()
+	require.Error(t, err)
+}
@@ -61,6 +61,10 @@ type Client struct {
 
 	wg sync.WaitGroup
 
+	// mux protects the channels that are re-created when the client is started again after Stop
+	mux    sync.RWMutex
+	closed bool // true once Stop has completed
+
 	stopOnce     sync.Once        // close stopped channel once
 	stopped      chan interface{} // closed on Stop
 	closeMsgCode int              // close message code
@@ -96,24 +100,46 @@ func NewClient(addr string, cfg *ClientConfig, decode func(io.Reader) (interface
 		return nil, fmt.Errorf("unsupported scheme for websocket connection: %s", u.Scheme)
 	}
 
-	return &Client{
-		dialer:           WithBaseURL(u)(NewDialer(cfg.Dialer)),
-		cfg:              cfg,
-		stopped:          make(chan interface{}),
-		pingReset:        make(chan struct{}),
-		outgoingPongs:    make(chan struct{}),
-		outgoingPings:    make(chan struct{}),
-		incomingMessages: make(chan *IncomingMessage, 100),
-		decode:           decode,
-		outgoingMessages: make(chan *outgoingMessage),
-		notifyErrors:     make(chan error, 1),
-	}, nil
+	c := &Client{
+		dialer: WithBaseURL(u)(NewDialer(cfg.Dialer)),
+		cfg:    cfg,
+		decode: decode,
+	}
+	c.init()
+
+	return c, nil
+}
+
+// init initializes the state of the client, so it can be started
+func (c *Client) init() {
+	c.stopOnce = sync.Once{}
+	c.stopped = make(chan interface{})
+	c.closeMsgCode = 0
+	c.closeOnce = sync.Once{}
+	c.closeErr = nil
+	c.pingReset = make(chan struct{})
+	c.outgoingPongs = make(chan struct{})
+	c.outgoingPings = make(chan struct{})
+	c.incomingMessages = make(chan *IncomingMessage, 100)
+	c.incomingErr = nil
+	c.outgoingMessages = make(chan *outgoingMessage)
+	c.notifyErrors = make(chan error, 1)
 }
 
 // Start the client
 // It establishes a connection to the server and starts the loops to handle incoming and
 // outgoing messages
+//
+// A stopped client can be started again (e.g. after a connection failure), in which case
+// Messages and Errors return new channels
 func (c *Client) Start(ctx context.Context) error {
+	c.mux.Lock()
+	if c.closed {
+		c.init()
+		c.closed = false
+	}
+	c.mux.Unlock()
+
 	// connect to the server
 	err := c.connect(ctx)
 	if err != nil {
@@ -154,8 +180,12 @@ func (c *Client) connect(ctx context.Context) error {
 // sendMessage is non-blocking
 // MUST not be called after calling close()
 func (c *Client) SendMessage(ctx context.Context, msgType int, encode func(io.Writer) error) error {
+	c.mux.RLock()
+	stopped, outgoingMessages := c.stopped, c.outgoingMessages
+	c.mux.RUnlock()
+
 	select {
-	case <-c.stopped:
+	case <-stopped:
 		return c.incomingErr
 	default:
 		msg := &outgoingMessage{
@@ -165,7 +195,7 @@ func (c *Client) SendMessage(ctx context.Context, msgType int, encode func(io.Wr
 			err:     make(chan error),
 		}
 		defer close(msg.err)
-		c.outgoingMessages <- msg
+		outgoingMessages <- msg
 		err := <-msg.err
 		return err
 	}
@@ -173,14 +203,18 @@ func (c *Client) SendMessage(ctx context.Context, msgType int, encode func(io.Wr
 
 // Messages returns a channel that receives incoming messages
 func (c *Client) Messages() <-chan *IncomingMessage {
+	c.mux.RLock()
+	defer c.mux.RUnlock()
 	return c.incomingMessages
 }
 
 // Errors notifies the users of any errors that occurs during the connection
 // Users SHOULD listen to this channel to be notified of any errors that occurs indicating that the connection is no longer usable
 // After receiving an error, the Client is no longer usable and the user MUST call Stop to ensure that the connection is properly closed
-// and resources are released
+// and resources are released (the client can then be started again)
 func (c *Client) Errors() <-chan error {
+	c.mux.RLock()
+	defer c.mux.RUnlock()
 	return c.notifyErrors
 }
 
@@ -385,13 +419,17 @@ func (c *Client) resetReadDeadline(timeout time.Duration) {
 // internal goroutines have finished and the its state is cleaned up.
 //
 // Users SHOULD call Stop to ensure that the connection is properly closed and resources are released.
-// Users MUST NOT call SendMessage after calling Stop.
+// Users MUST NOT call SendMessage after calling Stop, unless the client has been started again.
 func (c *Client) Stop(ctx context.Context) error {
 	c.closeOnce.Do(func() {
 		c.userStop()
 		close(c.outgoingMessages)
 		c.closeErr = c.waitLoops(ctx)
 		c.clean()
+
+		c.mux.Lock()
+		c.closed = true
+		c.mux.Unlock()
 	})
 	return c.closeErr
 }
@@ -143,6 +143,28 @@ func TestWsClientSendReceiveMsg(t *testing.T) {
 	require.NoError(t, err, "Stop should not error")
 }
 
+func TestWsClientRestart(t *testing.T) {
+	s, h := newServer(t)
+	defer s.Close()
+
+	client := newClient(t, s.URL)
+	require.NoError(t, client.Start(context.TODO()), "Start should not error")
+	require.NoError(t, client.Stop(context.Background()), "Stop should not error")
+	_, err := getNextError(h.errors, time.Second)
+	require.NoError(t, err, "Should receive an error")
+
+	// A stopped client can be started again
+	require.NoError(t, client.Start(context.TODO()), "Start after Stop should not error")
+
+	err = client.SendMessage(context.TODO(), websocket.TextMessage, func(w io.Writer) error { return json.NewEncoder(w).Encode(&Msg{Data: "hello"}) })
+	require.NoError(t, err, "SendMessage should not error")
+	req, err := h.getNextReq(time.Second)
+	require.NoError(t, err, "getNextReq should not error")
+	require.Equal(t, "hello", req.Data, "unexpected request data")
+
+	require.NoError(t, client.Stop(context.Background()), "Stop should not error")
+}
+
 func getNextMsg(ch <-chan *IncomingMessage, timeout time.Duration) (interface{}, error) {
 	select {
 	case msg := <-ch:
@@ -0,0 +1,427 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"github.com/nmvalera/go-utils/log"
+	"github.com/nmvalera/go-utils/tag"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+)
+
+var (
+	// ErrQueueFull is returned by Submit when the queue is full and the pool drops new tasks
+	ErrQueueFull = errors.New("worker pool queue is full")
+	// ErrNotRunning is returned by Submit when the pool is not running (not started, draining or stopped)
+	ErrNotRunning = errors.New("worker pool is not running")
+	// ErrAlreadyRunning is returned by Start when the pool has been started and not stopped yet
+	ErrAlreadyRunning = errors.New("worker pool is already running")
+)
+
+// Task is a unit of work executed by a Pool
+type Task func(ctx context.Context) error
+
+// QueuePolicy defines what Submit does when the queue is full
+type QueuePolicy int
+
+const (
+	// Block blocks Submit until there is room in the queue or the submit context is done (default)
+	Block QueuePolicy = iota
+	// Drop makes Submit return ErrQueueFull immediately
+	Drop
+)
+
+type item struct {
+	ctx        context.Context
+	task       Task
+	enqueuedAt time.Time
+}
+
+// Pool is a service executing tasks with a bounded number of workers and a bounded queue
+//
+// It implements svc.Runnable, svc.Drainable, svc.Checkable and svc.Metricable so it can be provided to an app.App.
+//
+// Tasks are executed with a context holding the values (logger, tags...) of the context passed to Submit,
+// but which is not canceled with it, as tasks usually outlive the submitter (e.g. an HTTP request).
+// A task context is canceled only if the task times out or if the pool stops before the task completes.
+type Pool struct {
+	concurrency int
+	queueSize   int
+	policy      QueuePolicy
+	taskTimeout time.Duration
+
+	// mux protects queue from being closed while tasks are submitted
+	mux      sync.RWMutex
+	queue    chan *item
+	closed   bool
+	closing  chan struct{}
+	closeOne *sync.Once
+	workers  sync.WaitGroup
+
+	// runCtx is canceled when Stop times out, so running tasks are canceled
+	// cancelRun is set from Start until Stop returns
+	runCtx    context.Context
+	cancelRun context.CancelFunc
+
+	queueDepth      prometheus.GaugeFunc
+	queueSaturation prometheus.GaugeFunc
+	busy            prometheus.Gauge
+	queueLatency    prometheus.Histogram
+	duration        prometheus.Histogram
+	tasks           prometheus.Counter
+	failures        prometheus.Counter
+	panics          prometheus.Counter
+	dropped         prometheus.Counter
+}
+
+type Option func(*Pool)
+
+// WithConcurrency sets the number of workers (default 1)
+func WithConcurrency(n int) Option {
+	return func(p *Pool) {
+		p.concurrency = n
+	}
+}
+
+// WithQueueSize sets the number of tasks that can wait for a worker (default 0, i.e. Submit waits for a free worker)
+func WithQueueSize(n int) Option {
+	return func(p *Pool) {
+		p.queueSize = n
+	}
+}
+
+// WithQueuePolicy sets the policy applied by Submit when the queue is full
+func WithQueuePolicy(policy QueuePolicy) Option {
+	return func(p *Pool) {
+		p.policy = policy
+	}
+}
+
+// WithTaskTimeout sets the maximum duration of each task (zero means no timeout)
+func WithTaskTimeout(timeout time.Duration) Option {
+	return func(p *Pool) {
+		p.taskTimeout = timeout
+	}
+}
+
+// NewPool creates a new Pool
+func NewPool(opts ...Option) *Pool {
+	p := &Pool{
+		concurrency: 1,
+		closed:      true,
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	if p.concurrency < 1 {
+		p.concurrency = 1
+	}
+
+	// Metrics are replaced when the pool is provided to an app (see SetMetrics)
+	p.SetMetrics("", "")
+
+	return p
+}
+
+// Start starts the workers
+//
+// It returns ErrAlreadyRunning if the pool has been started and not stopped yet.
+func (p *Pool) Start(ctx context.Context) error {
+	p.mux.Lock()
+	if p.cancelRun != nil {
+		p.mux.Unlock()
+		return ErrAlreadyRunning
+	}
+
+	log.LoggerFromContext(ctx).Info("Worker pool starting...", zap.Int("concurrency", p.concurrency), zap.Int("queue_size", p.queueSize))
+
+	p.runCtx, p.cancelRun = context.WithCancel(context.Background())
+	p.queue = make(chan *item, p.queueSize)
+	p.closing = make(chan struct{})
+	p.closeOne = new(sync.Once)
+	p.closed = false
+	p.mux.Unlock()
+
+	p.workers.Add(p.concurrency)
+	for range p.concurrency {
+		go p.work(p.queue)
+	}
+
+	return nil
+}
+
+// Submit enqueues a task
+//
+// If the queue is full, it blocks or returns ErrQueueFull depending on the QueuePolicy.
+// It returns ErrNotRunning if the pool is not running and the context error if ctx is done before the task is enqueued.
+func (p *Pool) Submit(ctx context.Context, task Task) error {
+	p.mux.RLock()
+	defer p.mux.RUnlock()
+
+	if p.closed {
+		return ErrNotRunning
+	}
+
+	it := &item{ctx: ctx, task: task, enqueuedAt: time.Now()}
+	if p.policy == Drop {
+		select {
+		case p.queue <- it:
+			return nil
+		default:
+			p.dropped.Inc()
+			return ErrQueueFull
+		}
+	}
+
+	select {
+	case p.queue <- it:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-p.closing:
+		return ErrNotRunning
+	}
+}
+
+// Drain stops accepting new tasks and waits for all queued and running tasks to complete
+func (p *Pool) Drain(ctx context.Context) error {
+	p.close()
+
+	done := make(chan struct{})
+	go func() {
+		p.workers.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Stop stops accepting new tasks and waits for all queued and running tasks to complete
+// If the context is done before, running tasks are canceled and remaining queued tasks are discarded
+//
+// Stopping a pool which has not been started does nothing
+func (p *Pool) Stop(ctx context.Context) error {
+	p.mux.RLock()
+	cancelRun := p.cancelRun
+	p.mux.RUnlock()
+	if cancelRun == nil {
+		return nil
+	}
+
+	logger := log.LoggerFromContext(ctx)
+	logger.Info("Worker pool stopping...")
+
+	err := p.Drain(ctx)
+	cancelRun()
+	if err != nil {
+		p.workers.Wait()
+		logger.Warn("Worker pool stopped before all tasks completed", zap.Error(err))
+	}
+
+	// All workers have exited, so the pool can be started again
+	p.mux.Lock()
+	p.cancelRun = nil
+	p.mux.Unlock()
+
+	return err
+}
+
+// Ready returns an error if the pool is not running
+//
+// A full queue is normal backpressure, so it does not make the pool unready (see the queue_saturation_ratio metric)
+func (p *Pool) Ready(_ context.Context) error {
+	p.mux.RLock()
+	defer p.mux.RUnlock()
+
+	if p.closed {
+		return ErrNotRunning
+	}
+	return nil
+}
+
+// close stops accepting new tasks, letting workers complete queued tasks
+func (p *Pool) close() {
+	p.mux.RLock()
+	closeOne, closing := p.closeOne, p.closing
+	p.mux.RUnlock()
+	if closeOne == nil {
+		return
+	}
+
+	closeOne.Do(func() {
+		// Unblock pending submits first so they release the read lock
+		close(closing)
+
+		p.mux.Lock()
+		p.closed = true
+		close(p.queue)
+		p.mux.Unlock()
+	})
+}
+
+func (p *Pool) work(queue <-chan *item) {
+	defer p.workers.Done()
+	for it := range queue {
+		select {
+		case <-p.runCtx.Done():
+			// The pool has been stopped, remaining tasks are discarded
+			continue
+		default:
+		}
+		p.run(it)
+	}
+}
+
+func (p *Pool) run(it *item) {
+	p.queueLatency.Observe(time.Since(it.enqueuedAt).Seconds())
+	p.busy.Inc()
+	defer p.busy.Dec()
+
+	ctx, cancel := context.WithCancel(context.WithoutCancel(it.ctx))
+	defer cancel()
+	stop := context.AfterFunc(p.runCtx, cancel)
+	defer stop()
+
+	if p.taskTimeout > 0 {
+		var cancelTimeout context.CancelFunc
+		ctx, cancelTimeout = context.WithTimeout(ctx, p.taskTimeout)
+		defer cancelTimeout()
+	}
+
+	begin := time.Now()
+	err := p.safeRun(ctx, it.task)
+	p.duration.Observe(time.Since(begin).Seconds())
+	p.tasks.Inc()
+	if err != nil {
+		p.failures.Inc()
+		log.LoggerFromContext(ctx).Error("Worker pool task failed", zap.Error(err))
+	}
+}
+
+// safeRun runs the task converting a panic into an error
+func (p *Pool) safeRun(ctx context.Context, task Task) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			p.panics.Inc()
+			err = fmt.Errorf("task panicked: %v\n%s", r, debug.Stack())
+		}
+	}()
+
+	return task(ctx)
+}
+
+// SetMetrics sets the metrics of the pool, tags are attached to all metrics as const labels
+func (p *Pool) SetMetrics(system, subsystem string, tags ...*tag.Tag) {
+	constLabels := prometheus.Labels(tag.Labels(tags...))
+
+	p.queueDepth = prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Namespace:   system,
+		Subsystem:   subsystem,
+		Name:        "queue_depth",
+		Help:        "Number of tasks waiting in the queue",
+		ConstLabels: constLabels,
+	}, func() float64 {
+		p.mux.RLock()
+		defer p.mux.RUnlock()
+		return float64(len(p.queue))
+	})
+	p.queueSaturation = prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Namespace:   system,
+		Subsystem:   subsystem,
+		Name:        "queue_saturation_ratio",
+		Help:        "Ratio of the queue capacity in use (between 0 and 1, always 0 without queue)",
+		ConstLabels: constLabels,
+	}, func() float64 {
+		if p.queueSize == 0 {
+			return 0
+		}
+		p.mux.RLock()
+		defer p.mux.RUnlock()
+		return float64(len(p.queue)) / float64(p.queueSize)
+	})
+	p.busy = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace:   system,
+		Subsystem:   subsystem,
+		Name:        "busy_workers",
+		Help:        "Number of workers running a task",
+		ConstLabels: constLabels,
+	})
+	p.queueLatency = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Namespace:   system,
+		Subsystem:   subsystem,
+		Name:        "queue_latency_seconds",
+		Help:        "Time tasks spent in the queue before being run in seconds",
+		ConstLabels: constLabels,
+		Buckets:     prometheus.DefBuckets,
+	})
+	p.duration = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Namespace:   system,
+		Subsystem:   subsystem,
+		Name:        "task_duration_seconds",
+		Help:        "Duration of the tasks in seconds",
+		ConstLabels: constLabels,
+		Buckets:     prometheus.DefBuckets,
+	})
+	p.tasks = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace:   system,
+		Subsystem:   subsystem,
+		Name:        "tasks_total",
+		Help:        "Total number of tasks run",
+		ConstLabels: constLabels,
+	})
+	p.failures = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace:   system,
+		Subsystem:   subsystem,
+		Name:        "task_failures_total",
+		Help:        "Total number of failed tasks (including panics and timeouts)",
+		ConstLabels: constLabels,
+	})
+	p.panics = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace:   system,
+		Subsystem:   subsystem,
+		Name:        "task_panics_total",
+		Help:        "Total number of tasks which panicked",
+		ConstLabels: constLabels,
+	})
+	p.dropped = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace:   system,
+		Subsystem:   subsystem,
+		Name:        "dropped_tasks_total",
+		Help:        "Total number of tasks dropped because the queue was full",
+		ConstLabels: constLabels,
+	})
+}
+
+func (p *Pool) Describe(ch chan<- *prometheus.Desc) {
+	p.queueDepth.Describe(ch)
+	p.queueSaturation.Describe(ch)
+	p.busy.Describe(ch)
+	p.queueLatency.Describe(ch)
+	p.duration.Describe(ch)
+	p.tasks.Describe(ch)
+	p.failures.Describe(ch)
+	p.panics.Describe(ch)
+	p.dropped.Describe(ch)
+}
+
+func (p *Pool) Collect(ch chan<- prometheus.Metric) {
+	p.queueDepth.Collect(ch)
+	p.queueSaturation.Collect(ch)
+	p.busy.Collect(ch)
+	p.queueLatency.Collect(ch)
+	p.duration.Collect(ch)
+	p.tasks.Collect(ch)
+	p.failures.Collect(ch)
+	p.panics.Collect(ch)
+	p.dropped.Collect(ch)
+}
@@ -0,0 +1,207 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/nmvalera/go-utils/tag"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type ctxKey struct{}
+
+func TestPool(t *testing.T) {
+	p := NewPool(WithConcurrency(4), WithQueueSize(10))
+	require.Error(t, p.Submit(context.Background(), func(_ context.Context) error { return nil }), "pool not started")
+
+	require.NoError(t, p.Start(context.Background()))
+	require.NoError(t, p.Ready(context.Background()))
+
+	var count atomic.Int32
+	var value atomic.Value
+	submitCtx, cancel := context.WithCancel(context.WithValue(context.Background(), ctxKey{}, "value"))
+	for i := range 20 {
+		require.NoError(t, p.Submit(submitCtx, func(ctx context.Context) error {
+			value.Store(ctx.Value(ctxKey{}))
+			count.Add(1)
+			if i == 0 {
+				return errors.New("test error")
+			}
+			return nil
+		}))
+	}
+	// Canceling the submit context does not cancel the tasks
+	cancel()
+
+	require.NoError(t, p.Stop(context.Background()))
+
+	// All queued tasks are processed on Stop
+	assert.Equal(t, int32(20), count.Load())
+	assert.Equal(t, "value", value.Load())
+	assert.Equal(t, float64(20), testutil.ToFloat64(p.tasks))
+	assert.Equal(t, float64(1), testutil.ToFloat64(p.failures))
+
+	assert.ErrorIs(t, p.Submit(context.Background(), func(_ context.Context) error { return nil }), ErrNotRunning)
+	assert.ErrorIs(t, p.Ready(context.Background()), ErrNotRunning)
+}
+
+func TestPoolQueuePolicy(t *testing.T) {
+	release := make(chan struct{})
+	blocking := func(_ context.Context) error {
+		<-release
+		return nil
+	}
+
+	// Drop
+	p := NewPool(WithQueueSize(1), WithQueuePolicy(Drop))
+	require.NoError(t, p.Start(context.Background()))
+	require.NoError(t, p.Submit(context.Background(), blocking))
+	require.Eventually(t, func() bool { return testutil.ToFloat64(p.busy) == 1 }, time.Second, time.Millisecond)
+	require.NoError(t, p.Submit(context.Background(), blocking))
+	assert.ErrorIs(t, p.Submit(context.Background(), blocking), ErrQueueFull)
+	assert.Equal(t, float64(1), testutil.ToFloat64(p.dropped))
+	assert.NoError(t, p.Ready(context.Background()), "A full queue should not make the pool unready")
+	assert.Equal(t, float64(1), testutil.ToFloat64(p.queueDepth))
+	assert.Equal(t, float64(1), testutil.ToFloat64(p.queueSaturation))
+
+	// Block
+	b := NewPool(WithQueueSize(1))
+	require.NoError(t, b.Start(context.Background()))
+	require.NoError(t, b.Submit(context.Background(), blocking))
+	require.Eventually(t, func() bool { return testutil.ToFloat64(b.busy) == 1 }, time.Second, time.Millisecond)
+	require.NoError(t, b.Submit(context.Background(), blocking))
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, b.Submit(ctx, blocking), context.DeadlineExceeded)
+
+	// A blocked submit is released when the pool is drained
+	submitErr := make(chan error)
+	go func() {
+		submitErr <- b.Submit(context.Background(), blocking)
+	}()
+	time.Sleep(10 * time.Millisecond)
+
+	drained := make(chan error)
+	go func() {
+		drained <- b.Drain(context.Background())
+	}()
+	assert.ErrorIs(t, <-submitErr, ErrNotRunning)
+
+	close(release)
+	require.NoError(t, <-drained)
+	require.NoError(t, b.Stop(context.Background()))
+	require.NoError(t, p.Stop(context.Background()))
+}
+
+func TestPoolStopTimeout(t *testing.T) {
+	p := NewPool(WithQueueSize(1))
+	require.NoError(t, p.Start(context.Background()))
+
+	started := make(chan struct{})
+	var canceled, queuedRan atomic.Bool
+	require.NoError(t, p.Submit(context.Background(), func(ctx context.Context) error {
+		close(started)
+		<-ctx.Done()
+		canceled.Store(true)
+		return ctx.Err()
+	}))
+	<-started
+	require.NoError(t, p.Submit(context.Background(), func(_ context.Context) error {
+		queuedRan.Store(true)
+		return nil
+	}))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	require.ErrorIs(t, p.Stop(ctx), context.DeadlineExceeded)
+
+	// Running task is canceled and queued task is discarded
+	assert.True(t, canceled.Load())
+	assert.False(t, queuedRan.Load())
+}
+
+func TestPoolTaskTimeoutAndPanic(t *testing.T) {
+	p := NewPool(WithTaskTimeout(10 * time.Millisecond))
+	require.NoError(t, p.Start(context.Background()))
+
+	var timedOut atomic.Bool
+	require.NoError(t, p.Submit(context.Background(), func(ctx context.Context) error {
+		<-ctx.Done()
+		timedOut.Store(errors.Is(ctx.Err(), context.DeadlineExceeded))
+		return ctx.Err()
+	}))
+	require.NoError(t, p.Submit(context.Background(), func(_ context.Context) error {
+		panic("test panic")
+	}))
+	require.NoError(t, p.Stop(context.Background()))
+
+	assert.True(t, timedOut.Load())
+	assert.Equal(t, float64(2), testutil.ToFloat64(p.failures))
+	assert.Equal(t, float64(1), testutil.ToFloat64(p.panics))
+}
+
+func TestPoolStopNotStarted(t *testing.T) {
+	p := NewPool()
+	require.NoError(t, p.Stop(context.Background()))
+}
+
+func TestPoolRestart(t *testing.T) {
+	p := NewPool()
+	for range 2 {
+		require.NoError(t, p.Start(context.Background()))
+		require.NoError(t, p.Submit(context.Background(), func(_ context.Context) error { return nil }))
+		require.NoError(t, p.Stop(context.Background()))
+	}
+	assert.Equal(t, float64(2), testutil.ToFloat64(p.tasks))
+}
+
+func TestPoolStartTwice(t *testing.T) {
+	p := NewPool(WithConcurrency(2))
+	require.NoError(t, p.Start(context.Background()))
+	require.ErrorIs(t, p.Start(context.Background()), ErrAlreadyRunning)
+
+	// Draining does not stop the pool
+	require.NoError(t, p.Drain(context.Background()))
+	require.ErrorIs(t, p.Start(context.Background()), ErrAlreadyRunning)
+
+	require.NoError(t, p.Stop(context.Background()))
+	require.NoError(t, p.Start(context.Background()))
+	require.NoError(t, p.Submit(context.Background(), func(_ context.Context) error { return nil }))
+	require.NoError(t, p.Stop(context.Background()))
+	assert.Equal(t, float64(1), testutil.ToFloat64(p.tasks))
+}
+
+func TestPoolMetrics(t *testing.T) {
+	p := NewPool()
+	p.SetMetrics("app", "ingest", tag.Key("component").String("ingest"))
+
+	reg := prometheus.NewRegistry()
+	require.NoError(t, reg.Register(p))
+
+	families, err := reg.Gather()
+	require.NoError(t, err)
+	for _, family := range families {
+		require.Len(t, family.GetMetric()[0].GetLabel(), 1)
+		assert.Equal(t, "component", family.GetMetric()[0].GetLabel()[0].GetName(), "Tags should be attached as const labels")
+	}
+
+	count, err := testutil.GatherAndCount(reg,
+		"app_ingest_queue_depth",
+		"app_ingest_queue_saturation_ratio",
+		"app_ingest_busy_workers",
+		"app_ingest_queue_latency_seconds",
+		"app_ingest_task_duration_seconds",
+		"app_ingest_tasks_total",
+		"app_ingest_task_failures_total",
+		"app_ingest_task_panics_total",
+		"app_ingest_dropped_tasks_total",
+	)
+	require.NoError(t, err)
+	assert.Equal(t, 9, count)
+}